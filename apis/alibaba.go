@@ -18,6 +18,8 @@ limitations under the License.
 package apis
 
 import (
+	"sort"
+
 	"k8s.io/apimachinery/pkg/runtime"
 
 	databasev1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
@@ -31,21 +33,54 @@ import (
 
 func init() {
 	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
-	AddToSchemes = append(AddToSchemes,
-		v1beta1.SchemeBuilder.AddToScheme,
-		databasev1alpha1.SchemeBuilder.AddToScheme,
-		slsv1alpha1.AddToScheme,
-		ossv1alpha1.SchemeBuilder.AddToScheme,
-		nasv1alpha1.AddToScheme,
-		slbv1alpha1.AddToScheme,
-		redisv1alpha1.SchemeBuilder.AddToScheme,
-	)
+	AddToSchemes = append(AddToSchemes, v1beta1.SchemeBuilder.AddToScheme)
+
+	AddToSchemesByGroup[databasev1alpha1.Group] = runtime.SchemeBuilder{databasev1alpha1.SchemeBuilder.AddToScheme}
+	AddToSchemesByGroup[slsv1alpha1.GroupVersion.Group] = runtime.SchemeBuilder{slsv1alpha1.AddToScheme}
+	AddToSchemesByGroup[ossv1alpha1.GroupVersion.Group] = runtime.SchemeBuilder{ossv1alpha1.SchemeBuilder.AddToScheme}
+	AddToSchemesByGroup[nasv1alpha1.GroupVersion.Group] = runtime.SchemeBuilder{nasv1alpha1.AddToScheme}
+	AddToSchemesByGroup[slbv1alpha1.GroupVersion.Group] = runtime.SchemeBuilder{slbv1alpha1.AddToScheme}
+	AddToSchemesByGroup[redisv1alpha1.Group] = runtime.SchemeBuilder{redisv1alpha1.SchemeBuilder.AddToScheme}
+	for _, group := range Groups() {
+		AddToSchemes = append(AddToSchemes, AddToSchemesByGroup[group]...)
+	}
 }
 
 // AddToSchemes may be used to add all resources defined in the project to a Scheme
 var AddToSchemes runtime.SchemeBuilder
 
+// AddToSchemesByGroup may be used to add the managed resources of an API group
+// to a Scheme, by API group.
+var AddToSchemesByGroup = map[string]runtime.SchemeBuilder{}
+
 // AddToScheme adds all Resources to the Scheme
 func AddToScheme(s *runtime.Scheme) error {
 	return AddToSchemes.AddToScheme(s)
 }
+
+// AddToSchemeForGroups adds the ProviderConfig resources, and the managed
+// resources of the API groups which are enabled, to the Scheme.
+func AddToSchemeForGroups(s *runtime.Scheme, enabled func(group string) bool) error {
+	if err := v1beta1.SchemeBuilder.AddToScheme(s); err != nil {
+		return err
+	}
+	for _, group := range Groups() {
+		if !enabled(group) {
+			continue
+		}
+		if err := AddToSchemesByGroup[group].AddToScheme(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Groups returns the sorted API groups of the managed resources.
+func Groups() []string {
+	groups := make([]string, 0, len(AddToSchemesByGroup))
+	for group := range AddToSchemesByGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return groups
+}
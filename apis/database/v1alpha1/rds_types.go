@@ -108,6 +108,17 @@ type RDSInstanceParameters struct {
 	// +immutable
 	// +optional
 	MasterUsername string `json:"masterUsername"`
+
+	// Region is the ID of the region of the RDS instance, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// Tags are the tags of the RDS instance. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // RDS instance states.
@@ -67,6 +67,17 @@ type NASFileSystemParameter struct {
 	ProtocolType   *string `json:"protocolType"`
 	VpcID          *string `json:"vpcId,omitempty"`
 	VSwitchID      *string `json:"vSwitchId,omitempty"`
+
+	// Region is the ID of the region of the file system, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// Tags are the tags of the file system. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // NASFileSystemObservation is the representation of the current state that is observed.
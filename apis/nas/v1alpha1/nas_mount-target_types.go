@@ -61,12 +61,31 @@ type NASMountTargetStatus struct {
 
 // NASMountTargetParameter is the isolated place to store files
 type NASMountTargetParameter struct {
-	FileSystemID    *string `json:"fileSystemID"`
+	// FileSystemID is the ID of the file system of the mount target.
+	// +optional
+	FileSystemID *string `json:"fileSystemID,omitempty"`
+
+	// FileSystemIDRef references a NASFileSystem to retrieve its
+	// FileSystemID.
+	// +optional
+	FileSystemIDRef *runtimev1.Reference `json:"fileSystemIDRef,omitempty"`
+
+	// FileSystemIDSelector selects a reference to a NASFileSystem to retrieve
+	// its FileSystemID.
+	// +optional
+	FileSystemIDSelector *runtimev1.Selector `json:"fileSystemIDSelector,omitempty"`
+
 	AccessGroupName *string `json:"accessGroupName,omitempty"`
 	NetworkType     *string `json:"networkType"`
 	VpcID           *string `json:"vpcId,omitempty"`
 	VSwitchID       *string `json:"vSwitchId,omitempty"`
 	SecurityGroupID *string `json:"securityGroupId,omitempty"`
+
+	// Region is the ID of the region of the mount target, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // NASMountTargetObservation is the representation of the current state that is observed.
@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	runtimev1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FileSystemID extracts the FileSystemID of a NASFileSystem once it is ready,
+// so that the resources referencing it wait until it is.
+func FileSystemID() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		fs, ok := mg.(*NASFileSystem)
+		if !ok || fs.GetCondition(runtimev1.TypeReady).Status != corev1.ConditionTrue {
+			return ""
+		}
+		return fs.Status.AtProvider.FileSystemID
+	}
+}
+
+// ResolveReferences of this NASMountTarget.
+func (mg *NASMountTarget) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.FileSystemID),
+		Reference:    mg.Spec.ForProvider.FileSystemIDRef,
+		Selector:     mg.Spec.ForProvider.FileSystemIDSelector,
+		To:           reference.To{Managed: &NASFileSystem{}, List: &NASFileSystemList{}},
+		Extract:      FileSystemID(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.fileSystemID")
+	}
+	mg.Spec.ForProvider.FileSystemID = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.FileSystemIDRef = rsp.ResolvedReference
+
+	return nil
+}
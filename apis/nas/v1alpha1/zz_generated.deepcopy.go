@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -132,6 +133,18 @@ func (in *NASFileSystemParameter) DeepCopyInto(out *NASFileSystemParameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NASFileSystemParameter.
@@ -265,6 +278,16 @@ func (in *NASMountTargetParameter) DeepCopyInto(out *NASMountTargetParameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FileSystemIDRef != nil {
+		in, out := &in.FileSystemIDRef, &out.FileSystemIDRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.FileSystemIDSelector != nil {
+		in, out := &in.FileSystemIDSelector, &out.FileSystemIDSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AccessGroupName != nil {
 		in, out := &in.AccessGroupName, &out.AccessGroupName
 		*out = new(string)
@@ -290,6 +313,11 @@ func (in *NASMountTargetParameter) DeepCopyInto(out *NASMountTargetParameter) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NASMountTargetParameter.
@@ -69,6 +69,17 @@ type BucketParameter struct {
 	ACL                string `json:"acl,omitempty"`
 	StorageClass       string `json:"storageClass,omitempty"`
 	DataRedundancyType string `json:"dataRedundancyType,omitempty"`
+
+	// Region is the ID of the region of the bucket, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// Tags are the tags of the bucket. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // BucketObservation is the representation of the current state that is observed.
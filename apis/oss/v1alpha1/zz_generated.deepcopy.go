@@ -102,6 +102,18 @@ func (in *BucketObservation) DeepCopy() *BucketObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketParameter) DeepCopyInto(out *BucketParameter) {
 	*out = *in
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BucketParameter.
@@ -118,7 +130,7 @@ func (in *BucketParameter) DeepCopy() *BucketParameter {
 func (in *BucketSpec) DeepCopyInto(out *BucketSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	out.BucketParameter = in.BucketParameter
+	in.BucketParameter.DeepCopyInto(&out.BucketParameter)
 	if in.Profile != nil {
 		in, out := &in.Profile, &out.Profile
 		*out = new(runtime.RawExtension)
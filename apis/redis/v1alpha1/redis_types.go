@@ -123,6 +123,17 @@ type RedisInstanceParameters struct {
 	// VSwitchId is indicates VSwitch ID
 	// +optional
 	VSwitchID string `json:"vSwitchId"`
+
+	// Region is the ID of the region of the Redis instance, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// Tags are the tags of the Redis instance. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // RedisInstanceObservation is the representation of the current state that is observed.
@@ -117,6 +117,18 @@ func (in *RedisInstanceObservation) DeepCopy() *RedisInstanceObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RedisInstanceParameters) DeepCopyInto(out *RedisInstanceParameters) {
 	*out = *in
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisInstanceParameters.
@@ -133,7 +145,7 @@ func (in *RedisInstanceParameters) DeepCopy() *RedisInstanceParameters {
 func (in *RedisInstanceSpec) DeepCopyInto(out *RedisInstanceSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisInstanceSpec.
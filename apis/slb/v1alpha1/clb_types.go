@@ -100,7 +100,7 @@ type CLBParameter struct {
 
 	// ClientToken that is used to ensure the idempotence of the request. You can use the client to generate the value,
 	// but you must ensure that it is unique among different requests. The token can contain only ASCII characters and
-	// cannot exceed 64 characters in length.
+	// cannot exceed 64 characters in length. It defaults to the UID of the CLB.
 	ClientToken *string `json:"clientToken,omitempty"`
 
 	OwnerID                      *int64  `json:"ownerId,omitempty"`
@@ -118,6 +118,11 @@ type CLBParameter struct {
 	DeleteProtection             *string `json:"deleteProtection,omitempty"`
 	ModificationProtectionStatus *string `json:"modificationProtectionStatus,omitempty"`
 	ModificationProtectionReason *string `json:"modificationProtectionReason,omitempty"`
+
+	// Tags are the tags of the SLB instance. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // CLBObservation is the representation of the current state that is observed.
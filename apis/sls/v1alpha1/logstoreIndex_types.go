@@ -43,10 +43,40 @@ type LogstoreIndexStatus struct {
 
 // LogstoreIndexParameters define the desired state of an SLS LogstoreIndex.
 type LogstoreIndexParameters struct {
-	ProjectName  *string             `json:"projectName"`
-	LogstoreName *string             `json:"logstoreName"`
-	Keys         map[string]IndexKey `json:"keys"`
+	// ProjectName is the name of the SLS project of the logstore.
+	// +optional
+	ProjectName *string `json:"projectName,omitempty"`
+
+	// ProjectNameRef references a Project to retrieve its name.
+	// +optional
+	ProjectNameRef *xpv1.Reference `json:"projectNameRef,omitempty"`
+
+	// ProjectNameSelector selects a reference to a Project to retrieve its
+	// name.
+	// +optional
+	ProjectNameSelector *xpv1.Selector `json:"projectNameSelector,omitempty"`
+
+	// LogstoreName is the name of the indexed logstore.
+	// +optional
+	LogstoreName *string `json:"logstoreName,omitempty"`
+
+	// LogstoreNameRef references a LogStore to retrieve its name.
+	// +optional
+	LogstoreNameRef *xpv1.Reference `json:"logstoreNameRef,omitempty"`
+
+	// LogstoreNameSelector selects a reference to a LogStore to retrieve its
+	// name.
+	// +optional
+	LogstoreNameSelector *xpv1.Selector `json:"logstoreNameSelector,omitempty"`
+
+	Keys map[string]IndexKey `json:"keys"`
 	// Confirmed with Alibaba Cloud SLS developer, using `line` index is not encouraged. So we don't support it.
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // IndexKey is the index by key.
@@ -51,7 +51,17 @@ type StoreParameters struct {
 	// SLS project name
 	// +kubebuilder:validation:MinLength:=3
 	// +kubebuilder:validation:MaxLength:=63
-	ProjectName string `json:"projectName"`
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// ProjectNameRef references a Project to retrieve its name.
+	// +optional
+	ProjectNameRef *xpv1.Reference `json:"projectNameRef,omitempty"`
+
+	// ProjectNameSelector selects a reference to a Project to retrieve its
+	// name.
+	// +optional
+	ProjectNameSelector *xpv1.Selector `json:"projectNameSelector,omitempty"`
 
 	// The data retention period. Unit: days. If you set the value to 3650, the data is permanently stored
 	// +kubebuilder:validation:Minimum:=1
@@ -71,6 +81,12 @@ type StoreParameters struct {
 	// The maximum number of shards for automatic sharding.
 	// +optional
 	MaxSplitShard *int `json:"maxSplitShard,omitempty"`
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // +kubebuilder:object:root=true
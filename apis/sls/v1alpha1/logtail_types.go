@@ -55,6 +55,12 @@ type LogtailParameters struct {
 	OutputType   *string      `json:"outputType"`
 	OutputDetail OutputDetail `json:"outputDetail"`
 	LogSample    *string      `json:"logSample,omitempty"`
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // InputDetail defines all file input detail's basic config
@@ -86,8 +92,31 @@ type InputDetail struct {
 
 // OutputDetail defines output
 type OutputDetail struct {
-	ProjectName  string `json:"projectName"`
-	LogStoreName string `json:"logstoreName"`
+	// ProjectName is the name of the SLS project of the logstore.
+	// +optional
+	ProjectName string `json:"projectName,omitempty"`
+
+	// ProjectNameRef references a Project to retrieve its name.
+	// +optional
+	ProjectNameRef *xpv1.Reference `json:"projectNameRef,omitempty"`
+
+	// ProjectNameSelector selects a reference to a Project to retrieve its
+	// name.
+	// +optional
+	ProjectNameSelector *xpv1.Selector `json:"projectNameSelector,omitempty"`
+
+	// LogStoreName is the name of the logstore the logs are shipped to.
+	// +optional
+	LogStoreName string `json:"logstoreName,omitempty"`
+
+	// LogStoreNameRef references a LogStore to retrieve its name.
+	// +optional
+	LogStoreNameRef *xpv1.Reference `json:"logstoreNameRef,omitempty"`
+
+	// LogStoreNameSelector selects a reference to a LogStore to retrieve its
+	// name.
+	// +optional
+	LogStoreNameSelector *xpv1.Selector `json:"logstoreNameSelector,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -47,11 +47,49 @@ type MachineGroupBindingParameters struct {
 	// SLS project name
 	// +kubebuilder:validation:MinLength:=3
 	// +kubebuilder:validation:MaxLength:=63
-	ProjectName *string `json:"projectName"`
-
-	GroupName *string `json:"groupName"`
-
-	ConfigName *string `json:"configName"`
+	// +optional
+	ProjectName *string `json:"projectName,omitempty"`
+
+	// ProjectNameRef references a Project to retrieve its name.
+	// +optional
+	ProjectNameRef *xpv1.Reference `json:"projectNameRef,omitempty"`
+
+	// ProjectNameSelector selects a reference to a Project to retrieve its
+	// name.
+	// +optional
+	ProjectNameSelector *xpv1.Selector `json:"projectNameSelector,omitempty"`
+
+	// GroupName is the name of the bound machine group.
+	// +optional
+	GroupName *string `json:"groupName,omitempty"`
+
+	// GroupNameRef references a MachineGroup to retrieve its name.
+	// +optional
+	GroupNameRef *xpv1.Reference `json:"groupNameRef,omitempty"`
+
+	// GroupNameSelector selects a reference to a MachineGroup to retrieve its
+	// name.
+	// +optional
+	GroupNameSelector *xpv1.Selector `json:"groupNameSelector,omitempty"`
+
+	// ConfigName is the name of the bound Logtail config.
+	// +optional
+	ConfigName *string `json:"configName,omitempty"`
+
+	// ConfigNameRef references a Logtail to retrieve its name.
+	// +optional
+	ConfigNameRef *xpv1.Reference `json:"configNameRef,omitempty"`
+
+	// ConfigNameSelector selects a reference to a Logtail to retrieve its
+	// name.
+	// +optional
+	ConfigNameSelector *xpv1.Selector `json:"configNameSelector,omitempty"`
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // +kubebuilder:object:root=true
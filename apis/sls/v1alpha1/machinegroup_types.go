@@ -55,6 +55,12 @@ type MachineGroupParameters struct {
 	MachineIDType *string                   `json:"machineIDType"`
 	MachineIDList *[]string                 `json:"machineIDList"`
 	Attribute     *sdk.MachinGroupAttribute `json:"attribute"`
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -58,6 +58,17 @@ type ProjectStatus struct {
 // ProjectParameters define the desired state of an SLS project.
 type ProjectParameters struct {
 	Description string `json:"description"`
+
+	// Region is the ID of the region of the SLS project, e.g. "cn-hangzhou". It
+	// defaults to the region of the ProviderConfig.
+	// +immutable
+	// +optional
+	Region *string `json:"region,omitempty"`
+
+	// Tags are the tags of the SLS project. The default tags configured in the
+	// ProviderConfig are added to them.
+	// +optional
+	Tags map[string]string `json:"tags,omitempty"`
 }
 
 // +kubebuilder:object:root=true
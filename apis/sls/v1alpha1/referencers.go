@@ -0,0 +1,174 @@
+/*
+
+ Copyright 2021 The Crossplane Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReadyExternalName extracts the external name of a managed resource once it
+// is ready, so that the resources referencing it wait until it is.
+func ReadyExternalName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		if mg.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+			return ""
+		}
+		return meta.GetExternalName(mg)
+	}
+}
+
+// ResolveReferences of this LogStore.
+func (mg *LogStore) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.ProjectName,
+		Reference:    mg.Spec.ForProvider.ProjectNameRef,
+		Selector:     mg.Spec.ForProvider.ProjectNameSelector,
+		To:           reference.To{Managed: &Project{}, List: &ProjectList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.projectName")
+	}
+	mg.Spec.ForProvider.ProjectName = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this LogstoreIndex.
+func (mg *LogstoreIndex) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.ProjectName),
+		Reference:    mg.Spec.ForProvider.ProjectNameRef,
+		Selector:     mg.Spec.ForProvider.ProjectNameSelector,
+		To:           reference.To{Managed: &Project{}, List: &ProjectList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.projectName")
+	}
+	mg.Spec.ForProvider.ProjectName = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.ProjectNameRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.LogstoreName),
+		Reference:    mg.Spec.ForProvider.LogstoreNameRef,
+		Selector:     mg.Spec.ForProvider.LogstoreNameSelector,
+		To:           reference.To{Managed: &LogStore{}, List: &LogStoreList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.logstoreName")
+	}
+	mg.Spec.ForProvider.LogstoreName = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.LogstoreNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this Logtail.
+func (mg *Logtail) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.OutputDetail.ProjectName,
+		Reference:    mg.Spec.ForProvider.OutputDetail.ProjectNameRef,
+		Selector:     mg.Spec.ForProvider.OutputDetail.ProjectNameSelector,
+		To:           reference.To{Managed: &Project{}, List: &ProjectList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.outputDetail.projectName")
+	}
+	mg.Spec.ForProvider.OutputDetail.ProjectName = rsp.ResolvedValue
+	mg.Spec.ForProvider.OutputDetail.ProjectNameRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.OutputDetail.LogStoreName,
+		Reference:    mg.Spec.ForProvider.OutputDetail.LogStoreNameRef,
+		Selector:     mg.Spec.ForProvider.OutputDetail.LogStoreNameSelector,
+		To:           reference.To{Managed: &LogStore{}, List: &LogStoreList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.outputDetail.logstoreName")
+	}
+	mg.Spec.ForProvider.OutputDetail.LogStoreName = rsp.ResolvedValue
+	mg.Spec.ForProvider.OutputDetail.LogStoreNameRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this MachineGroupBinding.
+func (mg *MachineGroupBinding) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.ProjectName),
+		Reference:    mg.Spec.ForProvider.ProjectNameRef,
+		Selector:     mg.Spec.ForProvider.ProjectNameSelector,
+		To:           reference.To{Managed: &Project{}, List: &ProjectList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.projectName")
+	}
+	mg.Spec.ForProvider.ProjectName = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.ProjectNameRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.GroupName),
+		Reference:    mg.Spec.ForProvider.GroupNameRef,
+		Selector:     mg.Spec.ForProvider.GroupNameSelector,
+		To:           reference.To{Managed: &MachineGroup{}, List: &MachineGroupList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.groupName")
+	}
+	mg.Spec.ForProvider.GroupName = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.GroupNameRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: reference.FromPtrValue(mg.Spec.ForProvider.ConfigName),
+		Reference:    mg.Spec.ForProvider.ConfigNameRef,
+		Selector:     mg.Spec.ForProvider.ConfigNameSelector,
+		To:           reference.To{Managed: &Logtail{}, List: &LogtailList{}},
+		Extract:      ReadyExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.configName")
+	}
+	mg.Spec.ForProvider.ConfigName = reference.ToPtrValue(rsp.ResolvedValue)
+	mg.Spec.ForProvider.ConfigNameRef = rsp.ResolvedReference
+
+	return nil
+}
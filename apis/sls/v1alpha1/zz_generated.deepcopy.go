@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	aliyun_log_go_sdk "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/crossplane/crossplane-runtime/apis/common/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -394,11 +395,31 @@ func (in *LogstoreIndexParameters) DeepCopyInto(out *LogstoreIndexParameters) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ProjectNameRef != nil {
+		in, out := &in.ProjectNameRef, &out.ProjectNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ProjectNameSelector != nil {
+		in, out := &in.ProjectNameSelector, &out.ProjectNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LogstoreName != nil {
 		in, out := &in.LogstoreName, &out.LogstoreName
 		*out = new(string)
 		**out = **in
 	}
+	if in.LogstoreNameRef != nil {
+		in, out := &in.LogstoreNameRef, &out.LogstoreNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.LogstoreNameSelector != nil {
+		in, out := &in.LogstoreNameSelector, &out.LogstoreNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Keys != nil {
 		in, out := &in.Keys, &out.Keys
 		*out = make(map[string]IndexKey, len(*in))
@@ -406,6 +427,11 @@ func (in *LogstoreIndexParameters) DeepCopyInto(out *LogstoreIndexParameters) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogstoreIndexParameters.
@@ -540,12 +566,17 @@ func (in *LogtailParameters) DeepCopyInto(out *LogtailParameters) {
 		*out = new(string)
 		**out = **in
 	}
-	out.OutputDetail = in.OutputDetail
+	in.OutputDetail.DeepCopyInto(&out.OutputDetail)
 	if in.LogSample != nil {
 		in, out := &in.LogSample, &out.LogSample
 		*out = new(string)
 		**out = **in
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogtailParameters.
@@ -706,16 +737,51 @@ func (in *MachineGroupBindingParameters) DeepCopyInto(out *MachineGroupBindingPa
 		*out = new(string)
 		**out = **in
 	}
+	if in.ProjectNameRef != nil {
+		in, out := &in.ProjectNameRef, &out.ProjectNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ProjectNameSelector != nil {
+		in, out := &in.ProjectNameSelector, &out.ProjectNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.GroupName != nil {
 		in, out := &in.GroupName, &out.GroupName
 		*out = new(string)
 		**out = **in
 	}
+	if in.GroupNameRef != nil {
+		in, out := &in.GroupNameRef, &out.GroupNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.GroupNameSelector != nil {
+		in, out := &in.GroupNameSelector, &out.GroupNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ConfigName != nil {
 		in, out := &in.ConfigName, &out.ConfigName
 		*out = new(string)
 		**out = **in
 	}
+	if in.ConfigNameRef != nil {
+		in, out := &in.ConfigNameRef, &out.ConfigNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ConfigNameSelector != nil {
+		in, out := &in.ConfigNameSelector, &out.ConfigNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineGroupBindingParameters.
@@ -846,6 +912,11 @@ func (in *MachineGroupParameters) DeepCopyInto(out *MachineGroupParameters) {
 		*out = new(aliyun_log_go_sdk.MachinGroupAttribute)
 		**out = **in
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineGroupParameters.
@@ -895,6 +966,26 @@ func (in *MachineGroupStatus) DeepCopy() *MachineGroupStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OutputDetail) DeepCopyInto(out *OutputDetail) {
 	*out = *in
+	if in.ProjectNameRef != nil {
+		in, out := &in.ProjectNameRef, &out.ProjectNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ProjectNameSelector != nil {
+		in, out := &in.ProjectNameSelector, &out.ProjectNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LogStoreNameRef != nil {
+		in, out := &in.LogStoreNameRef, &out.LogStoreNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.LogStoreNameSelector != nil {
+		in, out := &in.LogStoreNameSelector, &out.LogStoreNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputDetail.
@@ -984,6 +1075,18 @@ func (in *ProjectObservation) DeepCopy() *ProjectObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectParameters) DeepCopyInto(out *ProjectParameters) {
 	*out = *in
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectParameters.
@@ -1000,7 +1103,7 @@ func (in *ProjectParameters) DeepCopy() *ProjectParameters {
 func (in *ProjectSpec) DeepCopyInto(out *ProjectSpec) {
 	*out = *in
 	in.ResourceSpec.DeepCopyInto(&out.ResourceSpec)
-	out.ForProvider = in.ForProvider
+	in.ForProvider.DeepCopyInto(&out.ForProvider)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSpec.
@@ -1048,6 +1151,16 @@ func (in *StoreObservation) DeepCopy() *StoreObservation {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StoreParameters) DeepCopyInto(out *StoreParameters) {
 	*out = *in
+	if in.ProjectNameRef != nil {
+		in, out := &in.ProjectNameRef, &out.ProjectNameRef
+		*out = new(v1.Reference)
+		**out = **in
+	}
+	if in.ProjectNameSelector != nil {
+		in, out := &in.ProjectNameSelector, &out.ProjectNameSelector
+		*out = new(v1.Selector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AutoSplit != nil {
 		in, out := &in.AutoSplit, &out.AutoSplit
 		*out = new(bool)
@@ -1058,6 +1171,11 @@ func (in *StoreParameters) DeepCopyInto(out *StoreParameters) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Region != nil {
+		in, out := &in.Region, &out.Region
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoreParameters.
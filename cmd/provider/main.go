@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 
@@ -25,17 +26,31 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/webhook"
 )
 
 func main() {
 	var (
-		app            = kingpin.New(filepath.Base(os.Args[0]), "Alibaba Cloud support for Crossplane.").DefaultEnvars()
-		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
-		syncPeriod     = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
-		leaderElection = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		app                     = kingpin.New(filepath.Base(os.Args[0]), "Alibaba Cloud support for Crossplane.").DefaultEnvars()
+		debug                   = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		syncPeriod              = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
+		leaderElection          = app.Flag("leader-election", "Use leader election for the conroller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		pollInterval            = app.Flag("poll", "Poll interval controls how often an individual resource should be checked for drift.").Default("1m").Duration()
+		maxReconcileRate        = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may be checked for drift from the desired state.").Default("10").Int()
+		maxConcurrentReconciles = app.Flag("max-concurrent-reconciles", "The maximum number of resources of a kind reconciled concurrently.").Default("1").Int()
+		groupConcurrency        = app.Flag("group-max-concurrent-reconciles", "The maximum number of resources of a kind of an API group reconciled concurrently, e.g. sls=10. Overrides --max-concurrent-reconciles.").StringMap()
+		enableGroups            = app.Flag("enable-groups", "Only install the kinds and start the controllers of these API groups, e.g. sls,oss. May be repeated.").Strings()
+		disableGroups           = app.Flag("disable-groups", "Install the kinds and start the controllers of all the API groups but these, e.g. database,redis. May be repeated.").Strings()
+		dryRun                  = app.Flag("dry-run", "Only plan the changes of the external resources of all the managed resources, reporting them as events and in the Planned condition.").Default("false").Bool()
+		webhookCertDir          = app.Flag("webhook-tls-cert-dir", "The directory of the tls.crt and tls.key files serving the validating admission webhooks. The webhooks are disabled if it's not set.").Envar("WEBHOOK_TLS_CERT_DIR").String()
+		webhookPort             = app.Flag("webhook-port", "The port serving the validating admission webhooks.").Default("9443").Int()
+		otlpEndpoint            = app.Flag("otlp-endpoint", "The OTLP gRPC endpoint, e.g. otel-collector:4317, to export the traces of the reconciles and the API requests to. Tracing is disabled if it's not set.").Envar("OTLP_ENDPOINT").String()
+		otlpInsecure            = app.Flag("otlp-insecure", "Export the traces to --otlp-endpoint without TLS.").Default("false").Bool()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -48,7 +63,23 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "poll-interval", pollInterval.String(), "max-reconcile-rate", *maxReconcileRate)
+
+	if *otlpEndpoint != "" {
+		shutdown, err := util.StartTracing(context.Background(), *otlpEndpoint, *otlpInsecure)
+		kingpin.FatalIfError(err, "Cannot start tracing")
+		defer func() {
+			if err := shutdown(context.Background()); err != nil {
+				log.Info("Cannot flush the traces", "error", err)
+			}
+		}()
+	}
+
+	groupMaxConcurrentReconciles, err := util.ParseGroupMaxConcurrentReconciles(*groupConcurrency)
+	kingpin.FatalIfError(err, "Cannot parse --group-max-concurrent-reconciles")
+
+	groups, err := util.NewGroupFilter(apis.Groups(), *enableGroups, *disableGroups)
+	kingpin.FatalIfError(err, "Cannot parse --enable-groups and --disable-groups")
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
@@ -57,10 +88,25 @@ func main() {
 		LeaderElection:   *leaderElection,
 		LeaderElectionID: "crossplane-leader-election-provider-alibaba",
 		SyncPeriod:       syncPeriod,
+		CertDir:          *webhookCertDir,
+		Port:             *webhookPort,
 	})
 	kingpin.FatalIfError(err, "Cannot create controller manager")
 
-	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Alibaba Cloud APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log), "Cannot setup Alibaba Cloud controllers")
+	o := util.ControllerOptions{
+		Logger:                       log,
+		GlobalRateLimiter:            ratelimiter.NewDefaultProviderRateLimiter(*maxReconcileRate),
+		PollInterval:                 *pollInterval,
+		MaxConcurrentReconciles:      *maxConcurrentReconciles,
+		GroupMaxConcurrentReconciles: groupMaxConcurrentReconciles,
+		Groups:                       groups,
+		DryRun:                       *dryRun,
+	}
+
+	kingpin.FatalIfError(apis.AddToSchemeForGroups(mgr.GetScheme(), groups.Enabled), "Cannot add Alibaba Cloud APIs to scheme")
+	kingpin.FatalIfError(controller.Setup(mgr, o), "Cannot setup Alibaba Cloud controllers")
+	if *webhookCertDir != "" {
+		kingpin.FatalIfError(webhook.Setup(mgr, groups.Enabled), "Cannot setup Alibaba Cloud webhooks")
+	}
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
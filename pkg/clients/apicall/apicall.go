@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apicall sends the API requests of the SDK clients, limiting their
+// rate and recording their metrics and traces.
+package apicall
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/ratelimit"
+)
+
+// tracerName is the name of the tracer of the API requests.
+const tracerName = "github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+
+// Attributes of the spans of the API requests.
+const (
+	attrService   = attribute.Key("alibaba.service")
+	attrRegion    = attribute.Key("alibaba.region")
+	attrOperation = attribute.Key("alibaba.operation")
+	attrRequestID = attribute.Key("alibaba.request_id")
+	attrErrorCode = attribute.Key("alibaba.error_code")
+)
+
+// Caller sends the API requests of a client of a service in a region. A nil
+// Caller sends them as is.
+type Caller struct {
+	service string
+	region  string
+	limiter *ratelimit.Limiter
+}
+
+// NewCaller returns a Caller of the client of service in region, whose
+// requests are limited by limiter.
+func NewCaller(service, region string, limiter *ratelimit.Limiter) *Caller {
+	return &Caller{service: service, region: region, limiter: limiter}
+}
+
+// Do sends the request of operation, e.g. DescribeDBInstances, with fn once
+// the rate limit allows, and records its result and latency. The request is
+// traced as a child span of the span of ctx, if any.
+func (c *Caller) Do(ctx context.Context, operation string, fn func() error) error {
+	if c == nil {
+		return fn()
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	_, span := otel.Tracer(tracerName).Start(ctx, c.service+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrService.String(c.service), attrRegion.String(c.region), attrOperation.String(operation)))
+	defer span.End()
+
+	start := time.Now()
+	err := fn()
+	class := errorClass(err)
+	requestsTotal.WithLabelValues(c.service, operation, c.region, class).Inc()
+	requestDuration.WithLabelValues(c.service, operation, c.region, class).Observe(time.Since(start).Seconds())
+	c.limiter.Observe(err)
+	if err != nil {
+		recordError(span, err)
+	}
+	return err
+}
+
+// recordError records err and its details in span.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	d, ok := errorclass.GetDetails(err)
+	if !ok {
+		return
+	}
+	if d.RequestID != "" {
+		span.SetAttributes(attrRequestID.String(d.RequestID))
+	}
+	if d.Code != "" {
+		span.SetAttributes(attrErrorCode.String(d.Code))
+	}
+	if d.HTTPStatus != 0 {
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(d.HTTPStatus))
+	}
+}
+
+// errorClass is the value of the error class label of the result err.
+func errorClass(err error) string {
+	if err == nil {
+		return classNone
+	}
+	if class := errorclass.Classify(err); class != errorclass.Unknown {
+		return string(class)
+	}
+	return classUnknown
+}
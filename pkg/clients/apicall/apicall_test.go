@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apicall
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCallerDo(t *testing.T) {
+	var nilCaller *Caller
+	boom := errors.New("boom")
+	if err := nilCaller.Do(context.Background(), "DescribeDBInstances", func() error { return boom }); err != boom {
+		t.Errorf("Do(...): want a nil Caller to return the error of the request, got %v", err)
+	}
+
+	c := NewCaller("rds", "cn-hangzhou", nil)
+	notFound := tea.NewSDKError(map[string]interface{}{"code": "InvalidDBInstanceId.NotFound"})
+	cases := map[string]struct {
+		err   error
+		class string
+	}{
+		"Success":      {class: classNone},
+		"NotFound":     {err: notFound, class: "NotFound"},
+		"Unclassified": {err: boom, class: classUnknown},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			counter := requestsTotal.WithLabelValues("rds", "DescribeDBInstances", "cn-hangzhou", tc.class)
+			before := testutil.ToFloat64(counter)
+			if err := c.Do(context.Background(), "DescribeDBInstances", func() error { return tc.err }); err != tc.err {
+				t.Errorf("Do(...): want the error of the request %v, got %v", tc.err, err)
+			}
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("Do(...): want 1 request recorded with error class %q, got %v", tc.class, got)
+			}
+		})
+	}
+}
+
+func TestCallerDoTrace(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(prev)
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "Observe")
+	c := NewCaller("nas", "cn-hangzhou", nil)
+	notFound := tea.NewSDKError(map[string]interface{}{"code": "InvalidFileSystem.NotFound", "message": "code: 404, The specified file system does not exist. request id: 5E4F1A2C"})
+	if err := c.Do(ctx, "DescribeFileSystems", func() error { return notFound }); err != notFound {
+		t.Errorf("Do(...): want the error of the request %v, got %v", notFound, err)
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Do(...): want 2 spans, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name != "nas.DescribeFileSystems" {
+		t.Errorf("Do(...): want span nas.DescribeFileSystems, got %s", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindClient {
+		t.Errorf("Do(...): want a client span, got %s", span.SpanKind)
+	}
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("Do(...): want the span to be a child of the span of the context")
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("Do(...): want the span status to be an error, got %s", span.Status.Code)
+	}
+	want := []attribute.KeyValue{
+		attrService.String("nas"),
+		attrRegion.String("cn-hangzhou"),
+		attrOperation.String("DescribeFileSystems"),
+		attrRequestID.String("5E4F1A2C"),
+		attrErrorCode.String("InvalidFileSystem.NotFound"),
+		semconv.HTTPStatusCodeKey.Int(404),
+	}
+	if diff := cmp.Diff(want, span.Attributes, cmp.AllowUnexported(attribute.Value{})); diff != "" {
+		t.Errorf("Do(...): -want attributes, +got attributes:\n%s", diff)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apicall
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Values of the error class label of the requests which succeed, and which
+// fail with errors that aren't classified.
+const (
+	classNone    = "None"
+	classUnknown = "Unknown"
+)
+
+var (
+	labels = []string{"service", "operation", "region", "error_class"}
+
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alibaba_api_requests_total",
+		Help: "Number of API requests sent to Alibaba Cloud.",
+	}, labels)
+
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alibaba_api_request_duration_seconds",
+		Help:    "Latency of the API requests sent to Alibaba Cloud, excluding the time spent waiting for the rate limit.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, labels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(requestsTotal, requestDuration)
+}
@@ -0,0 +1,353 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errorclass classifies the errors returned by the Alibaba Cloud SDKs,
+// i.e. alibaba-cloud-sdk-go, the Tea based SDKs, the OSS SDK and the SLS SDK,
+// into a common set of classes.
+package errorclass
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// Class is the class of an error.
+type Class string
+
+// Classes of the errors.
+const (
+	// Unknown errors aren't classified.
+	Unknown Class = ""
+	// NotFound errors are returned when the requested resource doesn't exist.
+	NotFound Class = "NotFound"
+	// AlreadyExists errors are returned when the resource to create exists.
+	AlreadyExists Class = "AlreadyExists"
+	// Throttled errors are returned when the requests are throttled, or the
+	// service is too busy to serve them.
+	Throttled Class = "Throttled"
+	// QuotaExceeded errors are returned when the quota of the account is
+	// exhausted.
+	QuotaExceeded Class = "QuotaExceeded"
+	// InvalidParameter errors are returned when the request is invalid.
+	InvalidParameter Class = "InvalidParameter"
+	// Forbidden errors are returned when the credentials are invalid, or not
+	// permitted to send the request.
+	Forbidden Class = "Forbidden"
+	// Transient errors are returned when the request might succeed if retried
+	// later, e.g. on timeouts, internal errors or resources in a wrong state.
+	Transient Class = "Transient"
+)
+
+// classified is implemented by the errors which know their class, e.g. the
+// errors created by New.
+type classified interface {
+	Class() Class
+}
+
+type classError struct {
+	class   Class
+	message string
+}
+
+func (e *classError) Error() string { return e.message }
+func (e *classError) Class() Class  { return e.class }
+
+// New returns an error with message of class. The clients use it for the
+// errors they detect themselves, e.g. an empty response of a describe API.
+func New(class Class, message string) error {
+	return &classError{class: class, message: message}
+}
+
+// apiError is the error returned by an API.
+type apiError struct {
+	requestID string
+	code      string
+	status    int
+}
+
+// Code returns the error code of err returned by any of the SDKs, or an empty
+// string if err isn't returned by an API.
+func Code(err error) string {
+	e, _ := parse(err)
+	return e.code
+}
+
+// Details are the details of an error returned by an API. The request ID
+// identifies the failed request to the support of Alibaba Cloud.
+type Details struct {
+	RequestID  string
+	Code       string
+	HTTPStatus int
+}
+
+// GetDetails returns the details of err returned by any of the SDKs, or false
+// if err isn't returned by an API.
+func GetDetails(err error) (Details, bool) {
+	e, ok := parse(err)
+	return Details{RequestID: e.requestID, Code: e.code, HTTPStatus: e.status}, ok
+}
+
+// String returns the details which are known, e.g. "RequestId: 5E4F..., Code:
+// Throttling.User, HTTPStatus: 400".
+func (d Details) String() string {
+	var s []string
+	if d.RequestID != "" {
+		s = append(s, "RequestId: "+d.RequestID)
+	}
+	if d.Code != "" {
+		s = append(s, "Code: "+d.Code)
+	}
+	if d.HTTPStatus != 0 {
+		s = append(s, "HTTPStatus: "+strconv.Itoa(d.HTTPStatus))
+	}
+	return strings.Join(s, ", ")
+}
+
+// KeysAndValues returns the details which are known as the key value pairs of
+// a structured log.
+func (d Details) KeysAndValues() []interface{} {
+	var kv []interface{}
+	if d.RequestID != "" {
+		kv = append(kv, "requestId", d.RequestID)
+	}
+	if d.Code != "" {
+		kv = append(kv, "code", d.Code)
+	}
+	if d.HTTPStatus != 0 {
+		kv = append(kv, "httpStatus", d.HTTPStatus)
+	}
+	return kv
+}
+
+// requestIDError is implemented by the errors of alibaba-cloud-sdk-go returned
+// by the APIs, but not by those detected by the SDK.
+type requestIDError interface {
+	RequestId() string //nolint:golint
+}
+
+func parse(err error) (apiError, bool) {
+	var (
+		srverr   sdkerrors.Error
+		teaerr   *tea.SDKError
+		osserr   oss.ServiceError
+		slserr   *sls.Error
+		slsvalue sls.Error
+	)
+	switch {
+	case errors.As(err, &srverr):
+		e := apiError{code: srverr.ErrorCode()}
+		// The errors detected by the SDK have the 400 status, although no
+		// request reached the API.
+		if r, ok := srverr.(requestIDError); ok {
+			e.requestID = r.RequestId()
+			e.status = srverr.HttpStatus()
+		}
+		return e, true
+	case errors.As(err, &teaerr):
+		message := tea.StringValue(teaerr.Message)
+		return apiError{requestID: teaRequestID(message), code: tea.StringValue(teaerr.Code), status: teaStatus(message)}, true
+	case errors.As(err, &osserr):
+		return apiError{requestID: osserr.RequestID, code: osserr.Code, status: osserr.StatusCode}, true
+	case errors.As(err, &slserr):
+		return apiError{requestID: slserr.RequestID, code: slserr.Code, status: int(slserr.HTTPCode)}, true
+	case errors.As(err, &slsvalue):
+		return apiError{requestID: slsvalue.RequestID, code: slsvalue.Code, status: int(slsvalue.HTTPCode)}, true
+	}
+	return apiError{}, false
+}
+
+// teaStatus parses the HTTP status from the message of the Tea errors, e.g.
+// "code: 404, The specified file system does not exist. request id: ...".
+func teaStatus(message string) int {
+	if !strings.HasPrefix(message, "code: ") {
+		return 0
+	}
+	status, _ := strconv.Atoi(strings.SplitN(strings.TrimPrefix(message, "code: "), ",", 2)[0])
+	return status
+}
+
+// teaRequestID parses the request ID from the message of the Tea errors, e.g.
+// "code: 404, The specified file system does not exist. request id: ...".
+func teaRequestID(message string) string {
+	i := strings.LastIndex(message, teaRequestIDPrefix)
+	if i < 0 {
+		return ""
+	}
+	return strings.TrimSpace(message[i+len(teaRequestIDPrefix):])
+}
+
+// Classify returns the class of err.
+func Classify(err error) Class {
+	if err == nil {
+		return Unknown
+	}
+	var c classified
+	if errors.As(err, &c) {
+		return c.Class()
+	}
+	if e, ok := parse(err); ok {
+		if class := classifyCode(e.code); class != Unknown {
+			return class
+		}
+		return classifyStatus(e.status)
+	}
+	var nerr net.Error
+	if errors.As(err, &nerr) {
+		return Transient
+	}
+	return Unknown
+}
+
+// teaRequestIDPrefix precedes the request ID in the message of the Tea errors.
+const teaRequestIDPrefix = " request id: "
+
+// dependencies are the resources the external resources depend on, e.g. their
+// VPC or vSwitch. The errors of the codes about them, e.g.
+// InvalidVSwitchId.NotFound, are InvalidParameter rather than NotFound, so
+// that they aren't mistaken for the external resources being gone.
+var dependencies = []string{
+	"InvalidRegion", "InvalidZone", "InvalidVpc", "InvalidVPC", "InvalidVSwitch", "InvalidVswitch",
+	"InvalidSecurityGroup", "InvalidResourceGroup", "InvalidKMS", "InvalidKms",
+}
+
+// Error codes, which aren't classified by their pattern.
+var codes = map[string]Class{
+	"ServiceUnavailable":    Throttled,
+	"ServerBusy":            Throttled,
+	"WriteQuotaExceed":      Throttled,
+	"ReadQuotaExceed":       Throttled,
+	"RequestTimeout":        Transient,
+	"ServiceTimeout":        Transient,
+	"UnknownError":          Transient,
+	"SDK.ServerUnreachable": Transient,
+	"SignatureDoesNotMatch": Forbidden,
+	"TooManyBuckets":        QuotaExceeded,
+}
+
+// classifyCode classifies the error codes by the conventions shared by the
+// APIs, e.g. InvalidDBInstanceId.NotFound, InvalidAccountName.Duplicate or
+// ProjectAlreadyExist. The order matters, e.g. InvalidAccessKeyId.NotFound is
+// Forbidden rather than NotFound, InvalidVSwitchId.NotFound is InvalidParameter
+// rather than NotFound, and InvalidInstanceId.NotFound is NotFound rather than
+// InvalidParameter.
+//nolint:gocyclo
+func classifyCode(code string) Class {
+	if class, ok := codes[code]; ok {
+		return class
+	}
+	switch {
+	case code == "":
+		return Unknown
+	case hasPrefix(code, "Forbidden", "AccessDenied", "NoPermission", "Unauthorized", "InvalidAccessKeyId", "InvalidSecurityToken", "SecurityTokenExpired"):
+		return Forbidden
+	case strings.HasPrefix(code, "Throttling"):
+		return Throttled
+	case hasPrefix(code, dependencies...):
+		return InvalidParameter
+	case contains(code, "NotFound", "NotExist", "NoSuch"):
+		return NotFound
+	case contains(code, "Duplicate", "AlreadyExist") || strings.HasSuffix(code, "Exists"):
+		return AlreadyExists
+	case contains(code, "QuotaExceed", "QuotaExhausted", "ExceedQuota", "QuotaFull"):
+		return QuotaExceeded
+	case hasPrefix(code, "InternalError", "SDK.Timeout") ||
+		hasPrefix(code, "Incorrect", "OperationDenied") && contains(code, "State", "Status"):
+		return Transient
+	case hasPrefix(code, "Invalid", "Missing", "Parameter") || strings.Contains(code, "InvalidParameter"):
+		return InvalidParameter
+	}
+	return Unknown
+}
+
+// classifyStatus classifies the errors with unknown codes by their HTTP status.
+// The 400 status isn't classified since alibaba-cloud-sdk-go returns it for
+// any client error.
+func classifyStatus(status int) Class {
+	switch {
+	case status == http.StatusNotFound:
+		return NotFound
+	case status == http.StatusConflict:
+		return AlreadyExists
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return Forbidden
+	case status == http.StatusTooManyRequests:
+		return Throttled
+	case status >= http.StatusInternalServerError:
+		return Transient
+	}
+	return Unknown
+}
+
+func hasPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(s string, substrs ...string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotFound returns true if err is a NotFound error.
+func IsNotFound(err error) bool {
+	return Classify(err) == NotFound
+}
+
+// IsAlreadyExists returns true if err is an AlreadyExists error.
+func IsAlreadyExists(err error) bool {
+	return Classify(err) == AlreadyExists
+}
+
+// IsThrottled returns true if err is a Throttled error.
+func IsThrottled(err error) bool {
+	return Classify(err) == Throttled
+}
+
+// IsQuotaExceeded returns true if err is a QuotaExceeded error.
+func IsQuotaExceeded(err error) bool {
+	return Classify(err) == QuotaExceeded
+}
+
+// IsInvalidParameter returns true if err is an InvalidParameter error.
+func IsInvalidParameter(err error) bool {
+	return Classify(err) == InvalidParameter
+}
+
+// IsForbidden returns true if err is a Forbidden error.
+func IsForbidden(err error) bool {
+	return Classify(err) == Forbidden
+}
+
+// IsTransient returns true if err is a Transient error.
+func IsTransient(err error) bool {
+	return Classify(err) == Transient
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errorclass
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/alibabacloud-go/tea/tea"
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+	"github.com/pkg/errors"
+)
+
+func serverError(status int, code string) error {
+	return sdkerrors.NewServerError(status, fmt.Sprintf(`{"Code": %q}`, code), "")
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Class
+	}{
+		"Nil":   {err: nil, want: Unknown},
+		"Other": {err: errors.New("boom"), want: Unknown},
+		"New": {
+			err:  errors.Wrap(New(NotFound, "DBInstanceNotFound"), "wrapped"),
+			want: NotFound,
+		},
+		"RDSNotFound": {
+			err:  serverError(404, "InvalidDBInstanceId.NotFound"),
+			want: NotFound,
+		},
+		"RDSAccountDuplicate": {
+			err:  serverError(400, "InvalidAccountName.Duplicate"),
+			want: AlreadyExists,
+		},
+		"RedisNetTypeExists": {
+			err:  serverError(400, "NetTypeExists"),
+			want: AlreadyExists,
+		},
+		"RDSIncorrectState": {
+			err:  serverError(403, "IncorrectDBInstanceState"),
+			want: Transient,
+		},
+		"RDSInvalidParameter": {
+			err:  serverError(400, "InvalidDBInstanceClass.Malformed"),
+			want: InvalidParameter,
+		},
+		"RDSQuotaExceeded": {
+			err:  serverError(400, "QuotaExceed.DBInstance"),
+			want: QuotaExceeded,
+		},
+		"RDSForbidden": {
+			err:  serverError(404, "InvalidAccessKeyId.NotFound"),
+			want: Forbidden,
+		},
+		"RDSVSwitchNotFound": {
+			err:  serverError(404, "InvalidVSwitchId.NotFound"),
+			want: InvalidParameter,
+		},
+		"RedisVpcNotFound": {
+			err:  serverError(404, "InvalidVpcId.NotFound"),
+			want: InvalidParameter,
+		},
+		"ClientTimeout": {
+			err:  sdkerrors.NewClientError("SDK.TimeoutError", "timeout", nil),
+			want: Transient,
+		},
+		"TeaThrottling": {
+			err:  tea.NewSDKError(map[string]interface{}{"code": "Throttling.User", "message": "Request was denied due to user flow control."}),
+			want: Throttled,
+		},
+		"TeaNotFound": {
+			err:  tea.NewSDKError(map[string]interface{}{"code": "InvalidFileSystem.NotFound"}),
+			want: NotFound,
+		},
+		"TeaForbidden": {
+			err:  tea.NewSDKError(map[string]interface{}{"code": "Forbidden.RAM"}),
+			want: Forbidden,
+		},
+		"TeaUnknownCodeServerError": {
+			err:  tea.NewSDKError(map[string]interface{}{"code": "Boom", "message": "code: 503, Boom request id: 1234"}),
+			want: Transient,
+		},
+		"OSSNotFound": {
+			err:  oss.ServiceError{Code: "NoSuchBucket", StatusCode: 404},
+			want: NotFound,
+		},
+		"OSSAlreadyExists": {
+			err:  oss.ServiceError{Code: "BucketAlreadyExists", StatusCode: 409},
+			want: AlreadyExists,
+		},
+		"OSSServiceUnavailable": {
+			err:  errors.Wrap(oss.ServiceError{Code: "ServiceUnavailable"}, "wrapped"),
+			want: Throttled,
+		},
+		"SLSNotFound": {
+			err:  errors.Wrap(&sls.Error{Code: "LogStoreNotExist"}, "wrapped"),
+			want: NotFound,
+		},
+		"SLSAlreadyExists": {
+			err:  sls.Error{Code: "ProjectAlreadyExist"},
+			want: AlreadyExists,
+		},
+		"SLSServerBusy": {
+			err:  &sls.Error{Code: "ServerBusy"},
+			want: Throttled,
+		},
+		"SLSInvalidParameter": {
+			err:  &sls.Error{Code: "ParameterInvalid"},
+			want: InvalidParameter,
+		},
+		"SLSQuotaExceeded": {
+			err:  &sls.Error{Code: "ProjectQuotaExceed"},
+			want: QuotaExceeded,
+		},
+		"SLSUnauthorized": {
+			err:  &sls.Error{Code: "Unauthorized", HTTPCode: 401},
+			want: Forbidden,
+		},
+		"NetworkError": {
+			err:  &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			want: Transient,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Classify(tc.err); got != tc.want {
+				t.Errorf("Classify(%v): want %q, got %q", tc.err, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	if got := Code(errors.Wrap(&sls.Error{Code: "LogStoreNotExist"}, "wrapped")); got != "LogStoreNotExist" {
+		t.Errorf("Code(...): want LogStoreNotExist, got %q", got)
+	}
+	if got := Code(errors.New("boom")); got != "" {
+		t.Errorf("Code(...): want an empty code, got %q", got)
+	}
+}
+
+func TestGetDetails(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Details
+		ok   bool
+	}{
+		"Other": {err: errors.New("boom")},
+		"ServerError": {
+			err:  errors.Wrap(sdkerrors.NewServerError(400, `{"Code": "Throttling.User", "RequestId": "5E4F1A2B"}`, ""), "wrapped"),
+			want: Details{RequestID: "5E4F1A2B", Code: "Throttling.User", HTTPStatus: 400},
+			ok:   true,
+		},
+		"ClientError": {
+			err:  sdkerrors.NewClientError("SDK.TimeoutError", "timeout", nil),
+			want: Details{Code: "SDK.TimeoutError"},
+			ok:   true,
+		},
+		"Tea": {
+			err:  tea.NewSDKError(map[string]interface{}{"code": "InvalidFileSystem.NotFound", "message": "code: 404, The specified file system does not exist. request id: 5E4F1A2C"}),
+			want: Details{RequestID: "5E4F1A2C", Code: "InvalidFileSystem.NotFound", HTTPStatus: 404},
+			ok:   true,
+		},
+		"OSS": {
+			err:  oss.ServiceError{Code: "NoSuchBucket", StatusCode: 404, RequestID: "5E4F1A2D"},
+			want: Details{RequestID: "5E4F1A2D", Code: "NoSuchBucket", HTTPStatus: 404},
+			ok:   true,
+		},
+		"SLS": {
+			err:  errors.Wrap(&sls.Error{Code: "LogStoreNotExist", HTTPCode: 404, RequestID: "5E4F1A2E"}, "wrapped"),
+			want: Details{RequestID: "5E4F1A2E", Code: "LogStoreNotExist", HTTPStatus: 404},
+			ok:   true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, ok := GetDetails(tc.err)
+			if got != tc.want || ok != tc.ok {
+				t.Errorf("GetDetails(%v): want %+v, %t, got %+v, %t", tc.err, tc.want, tc.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestDetailsString(t *testing.T) {
+	d := Details{RequestID: "5E4F1A2B", Code: "Throttling.User", HTTPStatus: 400}
+	if got, want := d.String(), "RequestId: 5E4F1A2B, Code: Throttling.User, HTTPStatus: 400"; got != want {
+		t.Errorf("String(): want %q, got %q", want, got)
+	}
+	if got := (Details{Code: "ServerBusy"}).String(); got != "Code: ServerBusy" {
+		t.Errorf("String(): want the known details only, got %q", got)
+	}
+}
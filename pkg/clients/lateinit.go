@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+// LateInitializeString returns from if in is empty, otherwise in.
+func LateInitializeString(in, from string) string {
+	if in != "" {
+		return in
+	}
+	return from
+}
+
+// LateInitializeStringPtr returns from if in is nil and from is not empty,
+// otherwise in.
+func LateInitializeStringPtr(in, from *string) *string {
+	if in != nil || from == nil || *from == "" {
+		return in
+	}
+	v := *from
+	return &v
+}
+
+// LateInitializeInt32Ptr returns from if in is nil and from is not zero,
+// otherwise in.
+func LateInitializeInt32Ptr(in, from *int32) *int32 {
+	if in != nil || from == nil || *from == 0 {
+		return in
+	}
+	v := *from
+	return &v
+}
+
+// LateInitializeIntPtr returns a pointer to from if in is nil and from is not
+// zero, otherwise in.
+func LateInitializeIntPtr(in *int, from int) *int {
+	if in != nil || from == 0 {
+		return in
+	}
+	return &from
+}
+
+// LateInitializeBoolPtr returns a pointer to from if in is nil, otherwise in.
+func LateInitializeBoolPtr(in *bool, from bool) *bool {
+	if in != nil {
+		return in
+	}
+	return &from
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+)
+
+func TestLateInitialize(t *testing.T) {
+	if got := LateInitializeString("", "LRS"); got != "LRS" {
+		t.Errorf("LateInitializeString(...): want LRS, got %s", got)
+	}
+	if got := LateInitializeString("ZRS", "LRS"); got != "ZRS" {
+		t.Errorf("LateInitializeString(...): want ZRS, got %s", got)
+	}
+
+	cases := map[string]struct {
+		got  interface{}
+		want interface{}
+	}{
+		"StringPtrUnset":    {got: LateInitializeStringPtr(nil, pointer.StringPtr("PayOnDemand")), want: pointer.StringPtr("PayOnDemand")},
+		"StringPtrSet":      {got: LateInitializeStringPtr(pointer.StringPtr("PrePay"), pointer.StringPtr("PayOnDemand")), want: pointer.StringPtr("PrePay")},
+		"StringPtrEmpty":    {got: LateInitializeStringPtr(nil, pointer.StringPtr("")), want: (*string)(nil)},
+		"Int32PtrUnset":     {got: LateInitializeInt32Ptr(nil, pointer.Int32Ptr(5120)), want: pointer.Int32Ptr(5120)},
+		"Int32PtrSet":       {got: LateInitializeInt32Ptr(pointer.Int32Ptr(10), pointer.Int32Ptr(5120)), want: pointer.Int32Ptr(10)},
+		"IntPtrZero":        {got: LateInitializeIntPtr(nil, 0), want: (*int)(nil)},
+		"IntPtrUnset":       {got: LateInitializeIntPtr(nil, 64), want: pointer.IntPtr(64)},
+		"BoolPtrUnset":      {got: LateInitializeBoolPtr(nil, true), want: pointer.BoolPtr(true)},
+		"BoolPtrSetToFalse": {got: LateInitializeBoolPtr(pointer.BoolPtr(false), true), want: pointer.BoolPtr(false)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, tc.got); diff != "" {
+				t.Errorf("\nLateInitialize(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
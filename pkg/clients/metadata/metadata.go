@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultEndpoint is the base URL of the ECS instance metadata service
+	DefaultEndpoint = "http://100.100.100.200"
+
+	credentialsPath = "/latest/meta-data/ram/security-credentials/"
+	codeSuccess     = "Success"
+	requestTimeout  = 5 * time.Second
+
+	errFmtUnexpectedStatusCode  = "unexpected status code %d from the instance metadata service"
+	errFmtGetCredentialsFailed  = "instance metadata service failed to issue credentials: %s"
+	errNoRoleAttached           = "no RAM role is attached to the instance"
+	errFailedToParseCredentials = "failed to parse the credentials from the instance metadata service"
+	errFailedToParseExpiration  = "failed to parse the expiration of the temporary credentials"
+)
+
+// Client defines instance metadata service client operations
+type Client interface {
+	GetRoleCredentials(ctx context.Context, roleName string) (*Credentials, error)
+}
+
+// Credentials are the temporary credentials of the RAM role attached to the instance
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+type credentialsResponse struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	AccessKeySecret string `json:"AccessKeySecret"`
+	SecurityToken   string `json:"SecurityToken"`
+	Expiration      string `json:"Expiration"`
+	Code            string `json:"Code"`
+}
+
+type client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient creates new instance metadata service client. The default endpoint
+// is used if endpoint is empty.
+func NewClient(endpoint string) Client {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// GetRoleCredentials gets the temporary credentials of the RAM role attached to
+// the instance. The attached role is discovered if roleName is empty.
+func (c *client) GetRoleCredentials(ctx context.Context, roleName string) (*Credentials, error) {
+	if roleName == "" {
+		body, err := c.get(ctx, credentialsPath)
+		if err != nil {
+			return nil, err
+		}
+		roleName = strings.TrimSpace(strings.SplitN(string(body), "\n", 2)[0])
+		if roleName == "" {
+			return nil, errors.New(errNoRoleAttached)
+		}
+	}
+
+	body, err := c.get(ctx, credentialsPath+roleName)
+	if err != nil {
+		return nil, err
+	}
+	var resp credentialsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errFailedToParseCredentials)
+	}
+	if resp.Code != codeSuccess {
+		return nil, errors.Errorf(errFmtGetCredentialsFailed, resp.Code)
+	}
+	expiration, err := time.Parse(time.RFC3339, resp.Expiration)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToParseExpiration)
+	}
+
+	return &Credentials{
+		AccessKeyID:     resp.AccessKeyID,
+		AccessKeySecret: resp.AccessKeySecret,
+		SecurityToken:   resp.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+func (c *client) get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtUnexpectedStatusCode, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
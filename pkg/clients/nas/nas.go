@@ -25,50 +25,66 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 )
 
-// ErrCodeNoSuchNASFileSystem is the error code "NoSuchNASFileSystem" returned by SDK
 const (
 	errFailedToCreateNASClient = "failed to crate NAS client"
-	errCodeFileSystemNotExist  = "InvalidFileSystem.NotFound"
-	errMountTargetNotExisted   = "InvalidMountTarget.NotFound"
+
+	// fileSystemResourceType is the type of the tagged file systems
+	fileSystemResourceType = "filesystem"
+
+	// describePageSize is the maximum number of the file systems described by
+	// a request
+	describePageSize = 100
 )
 
 // ClientInterface create a client inferface
 type ClientInterface interface {
-	DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error)
-	CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error)
-	DeleteFileSystem(fileSystemID string) error
-
-	DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error)
-	CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error)
-	DeleteMountTarget(fileSystemID, mountTargetDomain *string) error
+	DescribeFileSystems(ctx context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error)
+	FindFileSystems(ctx context.Context, description string, tags map[string]string) ([]string, error)
+	CreateFileSystem(ctx context.Context, fs v1alpha1.NASFileSystemParameter, description, clientToken string) (*sdk.CreateFileSystemResponse, error)
+	DeleteFileSystem(ctx context.Context, fileSystemID string) error
+	ListFileSystemTags(ctx context.Context, fileSystemID string) (map[string]string, error)
+	TagFileSystem(ctx context.Context, fileSystemID string, tags map[string]string) error
+	UntagFileSystem(ctx context.Context, fileSystemID string, keys []string) error
+
+	DescribeMountTargets(ctx context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error)
+	CreateMountTarget(ctx context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error)
+	DeleteMountTarget(ctx context.Context, fileSystemID, mountTargetDomain *string) error
 }
 
 // SDKClient is the SDK client for NASFileSystem
 type SDKClient struct {
 	Client *sdk.Client
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
 // NewClient will create NAS client
-func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, securityToken string) (*SDKClient, error) {
+func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, securityToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*SDKClient, error) {
 	config := &openapi.Config{
 		AccessKeyId:     &accessKeyID,
 		AccessKeySecret: &accessKeySecret,
 		SecurityToken:   &securityToken,
 		Endpoint:        &endpoint,
 	}
+	if err := transport.ConfigureOpenAPIConfig(config); err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateNASClient)
+	}
 	client, err := sdk.NewClient(config)
 	if err != nil {
 		return nil, errors.Wrap(err, errFailedToCreateNASClient)
 	}
-	return &SDKClient{Client: client}, nil
+	return &SDKClient{Client: client, caller: caller}, nil
 }
 
 // -------------------------------- FileSystem ----------------------------------------------------
 
 // DescribeFileSystems describes NAS FileSystem
-func (c *SDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
+func (c *SDKClient) DescribeFileSystems(ctx context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
 	describeFileSystemsRequest := &sdk.DescribeFileSystemsRequest{}
 	if fileSystemID != nil {
 		describeFileSystemsRequest.FileSystemId = tea.String(*fileSystemID)
@@ -79,16 +95,72 @@ func (c *SDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *str
 	if vpcID != nil {
 		describeFileSystemsRequest.VpcId = tea.String(*vpcID)
 	}
-	fs, err := c.Client.DescribeFileSystems(describeFileSystemsRequest)
+	var fs *sdk.DescribeFileSystemsResponse
+	err := c.caller.Do(ctx, "DescribeFileSystems", func() (err error) {
+		fs, err = c.Client.DescribeFileSystems(describeFileSystemsRequest)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return fs, nil
 }
 
-// CreateFileSystem creates NASFileSystem
-func (c *SDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
-	createFileSystemRequest := &sdk.CreateFileSystemRequest{
+// FindFileSystems finds the IDs of the NASFileSystems described by
+// description, if it's not empty, and which have all of tags.
+func (c *SDKClient) FindFileSystems(ctx context.Context, description string, tags map[string]string) ([]string, error) {
+	describeFileSystemsRequest := &sdk.DescribeFileSystemsRequest{
+		PageSize: tea.Int32(describePageSize),
+	}
+	if description != "" {
+		describeFileSystemsRequest.Description = tea.String(description)
+	}
+	for k, v := range tags {
+		describeFileSystemsRequest.Tag = append(describeFileSystemsRequest.Tag, &sdk.DescribeFileSystemsRequestTag{Key: tea.String(k), Value: tea.String(v)})
+	}
+
+	var ids []string
+	for page, described := int32(1), int32(0); ; page++ {
+		describeFileSystemsRequest.PageNumber = tea.Int32(page)
+		var res *sdk.DescribeFileSystemsResponse
+		err := c.caller.Do(ctx, "DescribeFileSystems", func() (err error) {
+			res, err = c.Client.DescribeFileSystems(describeFileSystemsRequest)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		var fss []*sdk.DescribeFileSystemsResponseBodyFileSystemsFileSystem
+		if res.Body.FileSystems != nil {
+			fss = res.Body.FileSystems.FileSystem
+		}
+		// Only the file systems described exactly by description are found.
+		for _, fs := range fss {
+			if description == "" || tea.StringValue(fs.Description) == description {
+				ids = append(ids, tea.StringValue(fs.FileSystemId))
+			}
+		}
+		described += int32(len(fss))
+		if len(fss) == 0 || described >= tea.Int32Value(res.Body.TotalCount) {
+			return ids, nil
+		}
+	}
+}
+
+// CreateFileSystem creates NASFileSystem, only once for the same clientToken
+func (c *SDKClient) CreateFileSystem(ctx context.Context, fs v1alpha1.NASFileSystemParameter, description, clientToken string) (*sdk.CreateFileSystemResponse, error) {
+	createFileSystemRequest := MakeCreateFileSystemRequest(fs, description, clientToken)
+	var res *sdk.CreateFileSystemResponse
+	err := c.caller.Do(ctx, "CreateFileSystem", func() (err error) {
+		res, err = c.Client.CreateFileSystem(createFileSystemRequest)
+		return err
+	})
+	return res, err
+}
+
+// MakeCreateFileSystemRequest generates CreateFileSystemRequest
+func MakeCreateFileSystemRequest(fs v1alpha1.NASFileSystemParameter, description, clientToken string) *sdk.CreateFileSystemRequest {
+	req := &sdk.CreateFileSystemRequest{
 		FileSystemType: fs.FileSystemType,
 		ChargeType:     fs.ChargeType,
 		VpcId:          fs.VpcID,
@@ -96,16 +168,82 @@ func (c *SDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.C
 		StorageType:    fs.StorageType,
 		ProtocolType:   fs.ProtocolType,
 	}
-	res, err := c.Client.CreateFileSystem(createFileSystemRequest)
-	return res, err
+	if description != "" {
+		req.Description = tea.String(description)
+	}
+	if clientToken != "" {
+		req.ClientToken = tea.String(clientToken)
+	}
+	return req
 }
 
 // DeleteFileSystem deletes NASFileSystem
-func (c *SDKClient) DeleteFileSystem(fileSystemID string) error {
+func (c *SDKClient) DeleteFileSystem(ctx context.Context, fileSystemID string) error {
 	deleteFileSystemRequest := &sdk.DeleteFileSystemRequest{
 		FileSystemId: tea.String(fileSystemID),
 	}
-	_, err := c.Client.DeleteFileSystem(deleteFileSystemRequest)
+	err := c.caller.Do(ctx, "DeleteFileSystem", func() error {
+		_, err := c.Client.DeleteFileSystem(deleteFileSystemRequest)
+		return err
+	})
+	return err
+}
+
+// ListFileSystemTags lists the tags of NASFileSystem
+func (c *SDKClient) ListFileSystemTags(ctx context.Context, fileSystemID string) (map[string]string, error) {
+	listTagResourcesRequest := &sdk.ListTagResourcesRequest{
+		ResourceType: tea.String(fileSystemResourceType),
+		ResourceId:   tea.StringSlice([]string{fileSystemID}),
+	}
+	tags := map[string]string{}
+	for {
+		var res *sdk.ListTagResourcesResponse
+		err := c.caller.Do(ctx, "ListTagResources", func() (err error) {
+			res, err = c.Client.ListTagResources(listTagResourcesRequest)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.Body.TagResources != nil {
+			for _, t := range res.Body.TagResources.TagResource {
+				tags[tea.StringValue(t.TagKey)] = tea.StringValue(t.TagValue)
+			}
+		}
+		if tea.StringValue(res.Body.NextToken) == "" {
+			return tags, nil
+		}
+		listTagResourcesRequest.NextToken = res.Body.NextToken
+	}
+}
+
+// TagFileSystem adds or changes the tags of NASFileSystem
+func (c *SDKClient) TagFileSystem(ctx context.Context, fileSystemID string, tags map[string]string) error {
+	tagResourcesRequest := &sdk.TagResourcesRequest{
+		ResourceType: tea.String(fileSystemResourceType),
+		ResourceId:   tea.StringSlice([]string{fileSystemID}),
+	}
+	for k, v := range tags {
+		tagResourcesRequest.Tag = append(tagResourcesRequest.Tag, &sdk.TagResourcesRequestTag{Key: tea.String(k), Value: tea.String(v)})
+	}
+	err := c.caller.Do(ctx, "TagResources", func() error {
+		_, err := c.Client.TagResources(tagResourcesRequest)
+		return err
+	})
+	return err
+}
+
+// UntagFileSystem removes the tags of NASFileSystem
+func (c *SDKClient) UntagFileSystem(ctx context.Context, fileSystemID string, keys []string) error {
+	untagResourcesRequest := &sdk.UntagResourcesRequest{
+		ResourceType: tea.String(fileSystemResourceType),
+		ResourceId:   tea.StringSlice([]string{fileSystemID}),
+		TagKey:       tea.StringSlice(keys),
+	}
+	err := c.caller.Do(ctx, "UntagResources", func() error {
+		_, err := c.Client.UntagResources(untagResourcesRequest)
+		return err
+	})
 	return err
 }
 
@@ -129,6 +267,19 @@ func GenerateObservation(fileSystemID *string, descriptionResponse *sdk.Describe
 	return observation
 }
 
+// LateInitialize fills the empty fields in *v1alpha1.NASFileSystemParameter
+// with the values the cloud defaulted for the file system.
+func LateInitialize(in *v1alpha1.NASFileSystemParameter, fsResponse *sdk.DescribeFileSystemsResponse) {
+	if len(fsResponse.Body.FileSystems.FileSystem) == 0 {
+		return
+	}
+	fs := fsResponse.Body.FileSystems.FileSystem[0]
+	in.FileSystemType = clients.LateInitializeStringPtr(in.FileSystemType, fs.FileSystemType)
+	in.ChargeType = clients.LateInitializeStringPtr(in.ChargeType, fs.ChargeType)
+	in.StorageType = clients.LateInitializeStringPtr(in.StorageType, fs.StorageType)
+	in.ProtocolType = clients.LateInitializeStringPtr(in.ProtocolType, fs.ProtocolType)
+}
+
 // IsUpdateToDate checks whether cr is up to date
 func IsUpdateToDate(cr *v1alpha1.NASFileSystem, fsResponse *sdk.DescribeFileSystemsResponse) bool {
 	if *fsResponse.Body.TotalCount == 0 {
@@ -142,19 +293,10 @@ func IsUpdateToDate(cr *v1alpha1.NASFileSystem, fsResponse *sdk.DescribeFileSyst
 	return false
 }
 
-// IsNotFoundError helper function to test for SLS project not found error
-func IsNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var sdkerr *tea.SDKError
-	return errors.As(err, &sdkerr) && *sdkerr.Code == errCodeFileSystemNotExist
-}
-
 // -------------------------------- MountTarget ----------------------------------------------------
 
 // DescribeMountTargets describes NAS MountTarget
-func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
+func (c *SDKClient) DescribeMountTargets(ctx context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
 	describeMountTargetsRequest := &sdk.DescribeMountTargetsRequest{}
 	if fileSystemID != nil {
 		describeMountTargetsRequest.FileSystemId = tea.String(*fileSystemID)
@@ -162,7 +304,11 @@ func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string
 	if mountTargetDomain != nil {
 		describeMountTargetsRequest.MountTargetDomain = tea.String(*mountTargetDomain)
 	}
-	fs, err := c.Client.DescribeMountTargets(describeMountTargetsRequest)
+	var fs *sdk.DescribeMountTargetsResponse
+	err := c.caller.Do(ctx, "DescribeMountTargets", func() (err error) {
+		fs, err = c.Client.DescribeMountTargets(describeMountTargetsRequest)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -170,8 +316,19 @@ func (c *SDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string
 }
 
 // CreateMountTarget creates NASMountTarget
-func (c *SDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
-	createMountTargetRequest := &sdk.CreateMountTargetRequest{
+func (c *SDKClient) CreateMountTarget(ctx context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
+	createMountTargetRequest := MakeCreateMountTargetRequest(fs)
+	var res *sdk.CreateMountTargetResponse
+	err := c.caller.Do(ctx, "CreateMountTarget", func() (err error) {
+		res, err = c.Client.CreateMountTarget(createMountTargetRequest)
+		return err
+	})
+	return res, err
+}
+
+// MakeCreateMountTargetRequest generates CreateMountTargetRequest
+func MakeCreateMountTargetRequest(fs v1alpha1.NASMountTargetParameter) *sdk.CreateMountTargetRequest {
+	return &sdk.CreateMountTargetRequest{
 		FileSystemId:    fs.FileSystemID,
 		AccessGroupName: fs.AccessGroupName,
 		NetworkType:     fs.NetworkType,
@@ -179,17 +336,18 @@ func (c *SDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk
 		VSwitchId:       fs.VSwitchID,
 		SecurityGroupId: fs.SecurityGroupID,
 	}
-	res, err := c.Client.CreateMountTarget(createMountTargetRequest)
-	return res, err
 }
 
 // DeleteMountTarget deletes NASMountTarget
-func (c *SDKClient) DeleteMountTarget(fileSystemID, mountTargetDomain *string) error {
+func (c *SDKClient) DeleteMountTarget(ctx context.Context, fileSystemID, mountTargetDomain *string) error {
 	deleteMountTargetRequest := &sdk.DeleteMountTargetRequest{
 		FileSystemId:      fileSystemID,
 		MountTargetDomain: mountTargetDomain,
 	}
-	_, err := c.Client.DeleteMountTarget(deleteMountTargetRequest)
+	err := c.caller.Do(ctx, "DeleteMountTarget", func() error {
+		_, err := c.Client.DeleteMountTarget(deleteMountTargetRequest)
+		return err
+	})
 	return err
 }
 
@@ -198,6 +356,19 @@ func GenerateObservation4MountTarget(res *sdk.CreateMountTargetResponse) v1alpha
 	return v1alpha1.NASMountTargetObservation{MountTargetDomain: res.Body.MountTargetDomain}
 }
 
+// LateInitializeMountTarget fills the empty fields in
+// *v1alpha1.NASMountTargetParameter with the values the cloud defaulted for the
+// mount target.
+func LateInitializeMountTarget(in *v1alpha1.NASMountTargetParameter, mountTargetResponse *sdk.DescribeMountTargetsResponse) {
+	if len(mountTargetResponse.Body.MountTargets.MountTarget) == 0 {
+		return
+	}
+	res := mountTargetResponse.Body.MountTargets.MountTarget[0]
+	in.AccessGroupName = clients.LateInitializeStringPtr(in.AccessGroupName, res.AccessGroup)
+	in.VpcID = clients.LateInitializeStringPtr(in.VpcID, res.VpcId)
+	in.VSwitchID = clients.LateInitializeStringPtr(in.VSwitchID, res.VswId)
+}
+
 // IsMountTargetUpdateToDate checks whether cr is up to date
 //nolint:gocyclo
 func IsMountTargetUpdateToDate(cr *v1alpha1.NASMountTarget, mountTargetResponse *sdk.DescribeMountTargetsResponse) bool {
@@ -226,11 +397,3 @@ func IsMountTargetUpdateToDate(cr *v1alpha1.NASMountTarget, mountTargetResponse
 	return true
 }
 
-// IsMountTargetNotFoundError helper function to test for SLS project not found error
-func IsMountTargetNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var sdkerr *tea.SDKError
-	return errors.As(err, &sdkerr) && *sdkerr.Code == errMountTargetNotExisted
-}
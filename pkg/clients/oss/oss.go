@@ -18,11 +18,14 @@ package oss
 
 import (
 	"context"
+	"net/http"
 
 	sdk "github.com/aliyun/aliyun-oss-go-sdk/oss"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 )
 
 // ErrCodeNoSuchBucket is the error code "NoSuchBucket" returned by SDK
@@ -30,38 +33,50 @@ const ErrCodeNoSuchBucket = "NoSuchBucket"
 
 // ClientInterface will help fakeOSSClient in unit tests
 type ClientInterface interface {
-	Describe(name string) (*sdk.GetBucketInfoResult, error)
-	Create(name string, bucket v1alpha1.BucketParameter) error
-	Update(name string, aclStr string) error
-	Delete(name string) error
+	Describe(ctx context.Context, name string) (*sdk.GetBucketInfoResult, error)
+	Create(ctx context.Context, name string, bucket v1alpha1.BucketParameter) error
+	Update(ctx context.Context, name string, aclStr string) error
+	Delete(ctx context.Context, name string) error
+	GetTags(ctx context.Context, name string) (map[string]string, error)
+	SetTags(ctx context.Context, name string, tags map[string]string) error
 }
 
 // SDKClient is the SDK client for Bucket
 type SDKClient struct {
 	Client *sdk.Client
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
 // NewClient will create OSS client
-func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, stsToken string) (*SDKClient, error) {
-	var (
-		client *sdk.Client
-		err    error
-	)
-
-	if stsToken == "" {
-		client, err = sdk.New(endpoint, accessKeyID, accessKeySecret)
-	} else {
-		client, err = sdk.New(endpoint, accessKeyID, accessKeySecret, sdk.SecurityToken(stsToken))
+func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, stsToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*SDKClient, error) {
+	var options []sdk.ClientOption
+	if stsToken != "" {
+		options = append(options, sdk.SecurityToken(stsToken))
 	}
+	if transport != nil {
+		t, err := transport.NewTransport()
+		if err != nil {
+			return nil, errors.Errorf("failed to crate Bucket client: %v", err)
+		}
+		options = append(options, sdk.HTTPClient(&http.Client{Transport: t}))
+	}
+
+	client, err := sdk.New(endpoint, accessKeyID, accessKeySecret, options...)
 	if err != nil {
 		return nil, errors.Errorf("failed to crate Bucket client: %v", err)
 	}
-	return &SDKClient{Client: client}, nil
+	return &SDKClient{Client: client, caller: caller}, nil
 }
 
 // Describe describes OSS bucket
-func (c *SDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error) {
-	bucketInfoResult, err := c.Client.GetBucketInfo(name)
+func (c *SDKClient) Describe(ctx context.Context, name string) (*sdk.GetBucketInfoResult, error) {
+	var bucketInfoResult sdk.GetBucketInfoResult
+	err := c.caller.Do(ctx, "GetBucketInfo", func() (err error) {
+		bucketInfoResult, err = c.Client.GetBucketInfo(name)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +84,7 @@ func (c *SDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error) {
 }
 
 // Create creates Bucket bucket
-func (c *SDKClient) Create(name string, bucket v1alpha1.BucketParameter) error {
+func (c *SDKClient) Create(ctx context.Context, name string, bucket v1alpha1.BucketParameter) error {
 	var options []sdk.Option
 	var (
 		acl                sdk.ACLType
@@ -86,49 +101,78 @@ func (c *SDKClient) Create(name string, bucket v1alpha1.BucketParameter) error {
 	options = append(options, sdk.ACL(acl))
 
 	// validate StorageClass
-	storageClass, err = validateOSSStorageClass(bucket.StorageClass)
+	storageClass, err = ValidateOSSStorageClass(bucket.StorageClass)
 	if err != nil {
 		return err
 	}
 	options = append(options, sdk.StorageClass(storageClass))
 
 	// validate DataRedundancyType
-	dataRedundancyType, err = validateOSSDataRedundancyType(bucket.DataRedundancyType)
+	dataRedundancyType, err = ValidateOSSDataRedundancyType(bucket.DataRedundancyType)
 	if err != nil {
 		return err
 	}
 	options = append(options, sdk.RedundancyType(dataRedundancyType))
 
-	if err := c.Client.CreateBucket(name, options...); err != nil {
+	err = c.caller.Do(ctx, "CreateBucket", func() error {
+		return c.Client.CreateBucket(name, options...)
+	})
+	if err != nil {
 		return err
 	}
 	return nil
 }
 
 // Update sets bucket acl
-func (c *SDKClient) Update(name string, aclStr string) error {
+func (c *SDKClient) Update(ctx context.Context, name string, aclStr string) error {
 	acl, err := ValidateOSSAcl(aclStr)
 	if err != nil {
 		return err
 	}
-	return c.Client.SetBucketACL(name, acl)
+	return c.caller.Do(ctx, "SetBucketACL", func() error {
+		return c.Client.SetBucketACL(name, acl)
+	})
 }
 
 // Delete deletes OSS Bucket
-func (c *SDKClient) Delete(name string) error {
-	return c.Client.DeleteBucket(name)
+func (c *SDKClient) Delete(ctx context.Context, name string) error {
+	return c.caller.Do(ctx, "DeleteBucket", func() error {
+		return c.Client.DeleteBucket(name)
+	})
+}
+
+// GetTags gets the tags of OSS bucket
+func (c *SDKClient) GetTags(ctx context.Context, name string) (map[string]string, error) {
+	var result sdk.GetBucketTaggingResult
+	err := c.caller.Do(ctx, "GetBucketTagging", func() (err error) {
+		result, err = c.Client.GetBucketTagging(name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string]string, len(result.Tags))
+	for _, t := range result.Tags {
+		tags[t.Key] = t.Value
+	}
+	return tags, nil
 }
 
-// IsNotFoundError checks whether the error is an NotFound error
-func IsNotFoundError(err error) bool {
-	if err == nil {
-		return false
+// SetTags replaces the tags of OSS bucket, they are all deleted if tags is
+// empty
+func (c *SDKClient) SetTags(ctx context.Context, name string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return c.caller.Do(ctx, "DeleteBucketTagging", func() error {
+			return c.Client.DeleteBucketTagging(name)
+		})
 	}
-	var osserr sdk.ServiceError
-	if errors.As(err, &osserr) && osserr.Code == ErrCodeNoSuchBucket {
-		return true
+	tagging := sdk.Tagging{Tags: make([]sdk.Tag, 0, len(tags))}
+	for k, v := range tags {
+		tagging.Tags = append(tagging.Tags, sdk.Tag{Key: k, Value: v})
 	}
-	return false
+	return c.caller.Do(ctx, "SetBucketTagging", func() error {
+		return c.Client.SetBucketTagging(name, tagging)
+	})
 }
 
 // GenerateObservation generates BucketObservation from bucket information
@@ -156,7 +200,8 @@ func ValidateOSSAcl(aclStr string) (sdk.ACLType, error) {
 	return acl, nil
 }
 
-func validateOSSStorageClass(storageClassStr string) (sdk.StorageClassType, error) {
+// ValidateOSSStorageClass validates Bucket StorageClass and convert it to sdk.StorageClassType if possible
+func ValidateOSSStorageClass(storageClassStr string) (sdk.StorageClassType, error) {
 	var storageClass sdk.StorageClassType
 	switch storageClassStr {
 	case string(sdk.StorageStandard), "":
@@ -174,7 +219,8 @@ func validateOSSStorageClass(storageClassStr string) (sdk.StorageClassType, erro
 	return storageClass, nil
 }
 
-func validateOSSDataRedundancyType(dataRedundancyTypeStr string) (sdk.DataRedundancyType, error) {
+// ValidateOSSDataRedundancyType validates Bucket DataRedundancyType and convert it to sdk.DataRedundancyType if possible
+func ValidateOSSDataRedundancyType(dataRedundancyTypeStr string) (sdk.DataRedundancyType, error) {
 	var dataRedundancyType sdk.DataRedundancyType
 	switch dataRedundancyTypeStr {
 	case string(sdk.RedundancyLRS), "":
@@ -182,11 +228,19 @@ func validateOSSDataRedundancyType(dataRedundancyTypeStr string) (sdk.DataRedund
 	case string(sdk.RedundancyZRS):
 		dataRedundancyType = sdk.RedundancyZRS
 	default:
-		return "", errors.Errorf("bucket DataRedundancyType %s is invalid. It only supports could be LRS and ZRS", dataRedundancyType)
+		return "", errors.Errorf("bucket DataRedundancyType %s is invalid. It only supports could be LRS and ZRS", dataRedundancyTypeStr)
 	}
 	return dataRedundancyType, nil
 }
 
+// LateInitialize fills the empty fields in *v1alpha1.BucketParameter with the
+// values the cloud defaulted for the bucket.
+func LateInitialize(in *v1alpha1.BucketParameter, bucket *sdk.GetBucketInfoResult) {
+	in.ACL = clients.LateInitializeString(in.ACL, bucket.BucketInfo.ACL)
+	in.StorageClass = clients.LateInitializeString(in.StorageClass, bucket.BucketInfo.StorageClass)
+	in.DataRedundancyType = clients.LateInitializeString(in.DataRedundancyType, bucket.BucketInfo.RedundancyType)
+}
+
 // IsUpdateToDate checks whether cr is up to date
 func IsUpdateToDate(cr *v1alpha1.Bucket, bucket *sdk.GetBucketInfoResult) bool {
 	if (cr.Spec.ACL == bucket.BucketInfo.ACL) || (cr.Spec.ACL == "" && bucket.BucketInfo.ACL == "private") {
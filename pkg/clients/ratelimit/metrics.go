@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	waitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alibaba_api_rate_limit_wait_seconds",
+		Help:    "Time API requests spent waiting for the rate limit and the throttling backoff.",
+		Buckets: []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"account", "service"})
+
+	throttledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alibaba_api_throttled_requests_total",
+		Help: "Number of API requests throttled by Alibaba Cloud.",
+	}, []string{"account", "service"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(waitSeconds, throttledTotal)
+}
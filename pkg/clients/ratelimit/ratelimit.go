@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit limits the rate of the API requests sent by the SDK
+// clients, per account and service, and backs off when they're throttled.
+package ratelimit
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
+)
+
+// Config configures a Limiter.
+type Config struct {
+	// RequestsPerSecond is the sustained rate of the requests.
+	RequestsPerSecond float64
+	// Burst is the maximum number of requests sent at once.
+	Burst int
+	// MaxBackoff caps the exponential backoff after throttling errors.
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig is the configuration of the limiters which ProviderConfig
+// doesn't configure.
+var DefaultConfig = Config{
+	RequestsPerSecond: 10,
+	Burst:             20,
+	MaxBackoff:        30 * time.Second,
+}
+
+const baseBackoff = time.Second
+
+// limiters are shared by all the clients of the same account and service.
+var limiters = &registry{limiters: map[string]*Limiter{}}
+
+type registry struct {
+	mu       sync.Mutex
+	limiters map[string]*Limiter
+}
+
+// Get returns the Limiter of the service of account, creating it if it
+// doesn't exist yet. The Limiter is reconfigured with cfg if it differs, so
+// the configuration applied last wins if multiple ProviderConfigs of the same
+// account configure different limits.
+func Get(account, service string, cfg Config) *Limiter {
+	return limiters.get(account, service, cfg)
+}
+
+func (r *registry) get(account, service string, cfg Config) *Limiter {
+	key := strings.Join([]string{account, service}, "/")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.limiters[key]
+	if !ok {
+		l = &Limiter{
+			account: account,
+			service: service,
+			limiter: rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), cfg.Burst),
+			cfg:     cfg,
+		}
+		r.limiters[key] = l
+		return l
+	}
+	l.configure(cfg)
+	return l
+}
+
+// Limiter limits the rate of the requests sent to a service with the
+// credentials of an account. After a request is throttled, all the requests
+// wait for an exponential backoff with jitter, which is reset once a request
+// succeeds. A nil Limiter doesn't limit anything.
+type Limiter struct {
+	account string
+	service string
+	limiter *rate.Limiter
+
+	mu           sync.Mutex
+	cfg          Config
+	throttled    int
+	blockedUntil time.Time
+}
+
+func (l *Limiter) configure(cfg Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cfg == cfg {
+		return
+	}
+	l.cfg = cfg
+	l.limiter.SetLimit(rate.Limit(cfg.RequestsPerSecond))
+	l.limiter.SetBurst(cfg.Burst)
+}
+
+// Do sends a request with fn once the rate limit and the backoff allow, and
+// backs off if fn returns a throttling error.
+func (l *Limiter) Do(fn func() error) error {
+	if l == nil {
+		return fn()
+	}
+	if err := l.Wait(context.Background()); err != nil {
+		return err
+	}
+	err := fn()
+	l.Observe(err)
+	return err
+}
+
+// Wait blocks until the rate limit and the backoff allow a request, or ctx is
+// done. The time spent waiting is recorded in the wait time metric.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	start := time.Now()
+	defer func() {
+		waitSeconds.WithLabelValues(l.account, l.service).Observe(time.Since(start).Seconds())
+	}()
+
+	l.mu.Lock()
+	backoff := time.Until(l.blockedUntil)
+	l.mu.Unlock()
+	if backoff > 0 {
+		t := time.NewTimer(backoff)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return l.limiter.Wait(ctx)
+}
+
+// Observe updates the backoff with the result of a request.
+func (l *Limiter) Observe(err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !errorclass.IsThrottled(err) {
+		l.throttled = 0
+		return
+	}
+
+	throttledTotal.WithLabelValues(l.account, l.service).Inc()
+	l.throttled++
+	if until := time.Now().Add(jitter(backoff(l.throttled, l.cfg.MaxBackoff))); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// backoff is the exponential backoff after n consecutive throttling errors.
+func backoff(n int, max time.Duration) time.Duration {
+	d := baseBackoff
+	for i := 1; i < n && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// jitter spreads the backoff of the clients over [d/2, d), so that they don't
+// retry at the same time.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2))) //nolint:gosec
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+func TestBackoff(t *testing.T) {
+	max := 10 * time.Second
+	for n, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 4: 8 * time.Second, 5: max, 100: max} {
+		if got := backoff(n, max); got != want {
+			t.Errorf("backoff(%d, %s): want %s, got %s", n, max, want, got)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if got := jitter(4 * time.Second); got < 2*time.Second || got >= 4*time.Second {
+			t.Errorf("jitter(4s): want [2s, 4s), got %s", got)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	r := &registry{limiters: map[string]*Limiter{}}
+	l := r.get("1234", "rds", Config{RequestsPerSecond: 1000, Burst: 1, MaxBackoff: time.Minute})
+	if got := r.get("1234", "rds", DefaultConfig); got != l {
+		t.Errorf("get(...): want the limiter of the account and service to be shared")
+	}
+	if l.cfg != DefaultConfig {
+		t.Errorf("get(...): want the limiter to be reconfigured with %+v, got %+v", DefaultConfig, l.cfg)
+	}
+
+	throttling := tea.NewSDKError(map[string]interface{}{"code": "Throttling.User"})
+	if err := l.Do(func() error { return throttling }); err != throttling {
+		t.Errorf("Do(...): want the error of the request, got %v", err)
+	}
+	if backoff := time.Until(l.blockedUntil); backoff < baseBackoff/2 || backoff > baseBackoff {
+		t.Errorf("Do(...): want a backoff of [0.5s, 1s] after throttling, got %s", backoff)
+	}
+
+	l.Observe(nil)
+	if l.throttled != 0 {
+		t.Errorf("Observe(nil): want the backoff to be reset, got %d throttled requests", l.throttled)
+	}
+
+	var nilLimiter *Limiter
+	called := false
+	if err := nilLimiter.Do(func() error { called = true; return nil }); err != nil || !called {
+		t.Errorf("Do(...): want a nil limiter to send the request, got called %t, error %v", called, err)
+	}
+}
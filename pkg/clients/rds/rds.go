@@ -18,33 +18,52 @@ package rds
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	"strings"
 	"time"
 
-	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	alirds "github.com/aliyun/alibaba-cloud-sdk-go/services/rds"
+	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 )
 
 var (
 	// ErrDBInstanceNotFound indicates DBInstance not found
-	ErrDBInstanceNotFound = errors.New("DBInstanceNotFound")
+	ErrDBInstanceNotFound = errorclass.New(errorclass.NotFound, "DBInstanceNotFound")
 	// ErrCodeInstanceNotFound error code of ServerError when DBInstance not found
 	ErrCodeInstanceNotFound = "InvalidDBInstanceId.NotFound"
 )
 
 const (
 	httpsScheme = "https"
+
+	// tagResourceType is the type of the tagged RDS instances
+	tagResourceType = "INSTANCE"
+
+	// defaultReadTimeout is the read timeout of the time-consuming requests
+	defaultReadTimeout = 60 * time.Second
+
+	// describePageSize is the maximum number of instances described per page
+	describePageSize = 100
+
+	errFmtUnsupportedEngine = "engine %q is not supported, it could only be %s or %s"
 )
 
 // Client defines RDS client operations
 type Client interface {
-	DescribeDBInstance(id string) (*DBInstance, error)
-	CreateAccount(id, username, password string) error
-	CreateDBInstance(*CreateDBInstanceRequest) (*DBInstance, error)
-	DeleteDBInstance(id string) error
+	DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error)
+	FindDBInstances(ctx context.Context, description string, tags map[string]string) ([]*DBInstance, error)
+	CreateAccount(ctx context.Context, id, username, password string) error
+	CreateDBInstance(ctx context.Context, req *CreateDBInstanceRequest) (*DBInstance, error)
+	DeleteDBInstance(ctx context.Context, id string) error
+	ListTags(ctx context.Context, id string) (map[string]string, error)
+	TagResources(ctx context.Context, id string, tags map[string]string) error
+	UntagResources(ctx context.Context, id string, keys []string) error
 }
 
 // DBInstance defines the DB instance information
@@ -55,6 +74,15 @@ type DBInstance struct {
 	// Database engine
 	Engine string
 
+	// Database engine version
+	EngineVersion string
+
+	// Instance class
+	DBInstanceClass string
+
+	// Storage in GB. It's only described by DescribeDBInstance.
+	DBInstanceStorageInGB int
+
 	// Instance status
 	Status string
 
@@ -70,14 +98,26 @@ type CreateDBInstanceRequest struct {
 	SecurityIPList        string
 	DBInstanceClass       string
 	DBInstanceStorageInGB int
+	// ClientToken ensures that the instance is only created once when the
+	// request is retried.
+	ClientToken string
 }
 
 type client struct {
 	rdsCli *alirds.Client
+	region string
+	// endpoint overrides the endpoint resolved by the SDK if it's not empty
+	endpoint string
+	// readTimeout is the read timeout of the time-consuming requests
+	readTimeout time.Duration
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
-// NewClient creates new RDS RDSClient
-func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (Client, error) {
+// NewClient creates new RDS RDSClient. The endpoint is resolved by the SDK
+// from region if endpoint is empty.
+func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (Client, error) {
 	var (
 		rdsCli *alirds.Client
 		err    error
@@ -91,36 +131,99 @@ func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken,
 	if err != nil {
 		return nil, err
 	}
-	c := &client{rdsCli: rdsCli}
+	if err := transport.ConfigureSDKClient(&rdsCli.Client); err != nil {
+		return nil, err
+	}
+	c := &client{rdsCli: rdsCli, region: region, endpoint: endpoint, caller: caller, readTimeout: transport.ReadTimeoutOr(defaultReadTimeout)}
 	return c, nil
 }
 
-func (c *client) DescribeDBInstance(id string) (*DBInstance, error) {
-	request := alirds.CreateDescribeDBInstancesRequest()
+func (c *client) DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error) {
+	request := alirds.CreateDescribeDBInstanceAttributeRequest()
 	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
 
 	request.DBInstanceId = id
 
-	response, err := c.rdsCli.DescribeDBInstances(request)
+	var response *alirds.DescribeDBInstanceAttributeResponse
+	err := c.caller.Do(ctx, "DescribeDBInstanceAttribute", func() (err error) {
+		response, err = c.rdsCli.DescribeDBInstanceAttribute(request)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	if len(response.Items.DBInstance) == 0 {
+	if len(response.Items.DBInstanceAttribute) == 0 {
 		return nil, ErrDBInstanceNotFound
 	}
-	rsp := response.Items.DBInstance[0]
-	in := &DBInstance{
-		ID:     rsp.DBInstanceId,
-		Engine: rsp.Engine,
-		Status: rsp.DBInstanceStatus,
+	attr := response.Items.DBInstanceAttribute[0]
+	return &DBInstance{
+		ID:                    attr.DBInstanceId,
+		Engine:                attr.Engine,
+		EngineVersion:         attr.EngineVersion,
+		DBInstanceClass:       attr.DBInstanceClass,
+		DBInstanceStorageInGB: attr.DBInstanceStorage,
+		Status:                attr.DBInstanceStatus,
+	}, nil
+}
+
+// FindDBInstances finds the instances whose description is description, if
+// it's not empty, and which have all of tags.
+func (c *client) FindDBInstances(ctx context.Context, description string, tags map[string]string) ([]*DBInstance, error) {
+	request := alirds.CreateDescribeDBInstancesRequest()
+	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.PageSize = requests.NewInteger(describePageSize)
+
+	// The search key also matches the instances whose description or ID
+	// contains it, they are filtered out below.
+	request.SearchKey = description
+	if len(tags) > 0 {
+		t, err := json.Marshal(tags)
+		if err != nil {
+			return nil, err
+		}
+		request.Tags = string(t)
 	}
 
-	return in, nil
+	var instances []*DBInstance
+	for page, described := 1, 0; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+		var response *alirds.DescribeDBInstancesResponse
+		err := c.caller.Do(ctx, "DescribeDBInstances", func() (err error) {
+			response, err = c.rdsCli.DescribeDBInstances(request)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, rsp := range response.Items.DBInstance {
+			if description == "" || rsp.DBInstanceDescription == description {
+				instances = append(instances, newDBInstance(rsp))
+			}
+		}
+		described += len(response.Items.DBInstance)
+		if len(response.Items.DBInstance) == 0 || described >= response.TotalRecordCount {
+			return instances, nil
+		}
+	}
 }
 
-func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, error) {
+func newDBInstance(rsp alirds.DBInstanceInDescribeDBInstances) *DBInstance {
+	return &DBInstance{
+		ID:              rsp.DBInstanceId,
+		Engine:          rsp.Engine,
+		EngineVersion:   rsp.EngineVersion,
+		DBInstanceClass: rsp.DBInstanceClass,
+		Status:          rsp.DBInstanceStatus,
+	}
+}
+
+func (c *client) CreateDBInstance(ctx context.Context, req *CreateDBInstanceRequest) (*DBInstance, error) {
 	request := alirds.CreateCreateDBInstanceRequest()
 	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
 
 	request.DBInstanceDescription = req.Name
 	request.Engine = req.Engine
@@ -130,10 +233,14 @@ func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, er
 	request.SecurityIPList = req.SecurityIPList
 	request.DBInstanceNetType = "Internet"
 	request.PayType = "Postpaid"
-	request.ReadTimeout = 60 * time.Second
-	request.ClientToken = req.Name
-
-	resp, err := c.rdsCli.CreateDBInstance(request)
+	request.ReadTimeout = c.readTimeout
+	request.ClientToken = req.ClientToken
+
+	var resp *alirds.CreateDBInstanceResponse
+	err := c.caller.Do(ctx, "CreateDBInstance", func() (err error) {
+		resp, err = c.rdsCli.CreateDBInstance(request)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -147,32 +254,125 @@ func (c *client) CreateDBInstance(req *CreateDBInstanceRequest) (*DBInstance, er
 	}, nil
 }
 
-func (c *client) CreateAccount(id, user, pw string) error {
+func (c *client) CreateAccount(ctx context.Context, id, user, pw string) error {
 	request := alirds.CreateCreateAccountRequest()
 	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
 	request.DBInstanceId = id
 	request.AccountName = user
 	request.AccountPassword = pw
-	request.ReadTimeout = 60 * time.Second
+	request.ReadTimeout = c.readTimeout
 
-	_, err := c.rdsCli.CreateAccount(request)
+	err := c.caller.Do(ctx, "CreateAccount", func() error {
+		_, err := c.rdsCli.CreateAccount(request)
+		return err
+	})
 	return err
 }
 
-func (c *client) DeleteDBInstance(id string) error {
+func (c *client) DeleteDBInstance(ctx context.Context, id string) error {
 	request := alirds.CreateDeleteDBInstanceRequest()
 	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
 
 	request.DBInstanceId = id
 
-	_, err := c.rdsCli.DeleteDBInstance(request)
+	err := c.caller.Do(ctx, "DeleteDBInstance", func() error {
+		_, err := c.rdsCli.DeleteDBInstance(request)
+		return err
+	})
 	return err
 }
 
+func (c *client) ListTags(ctx context.Context, id string) (map[string]string, error) {
+	request := alirds.CreateListTagResourcesRequest()
+	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+
+	tags := map[string]string{}
+	for {
+		var response *alirds.ListTagResourcesResponse
+		err := c.caller.Do(ctx, "ListTagResources", func() (err error) {
+			response, err = c.rdsCli.ListTagResources(request)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range response.TagResources.TagResource {
+			tags[t.TagKey] = t.TagValue
+		}
+		if response.NextToken == "" {
+			return tags, nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
+
+func (c *client) TagResources(ctx context.Context, id string, tags map[string]string) error {
+	request := alirds.CreateTagResourcesRequest()
+	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+	tag := make([]alirds.TagResourcesTag, 0, len(tags))
+	for k, v := range tags {
+		tag = append(tag, alirds.TagResourcesTag{Key: k, Value: v})
+	}
+	request.Tag = &tag
+
+	err := c.caller.Do(ctx, "TagResources", func() error {
+		_, err := c.rdsCli.TagResources(request)
+		return err
+	})
+	return err
+}
+
+func (c *client) UntagResources(ctx context.Context, id string, keys []string) error {
+	request := alirds.CreateUntagResourcesRequest()
+	request.Scheme = httpsScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+	request.TagKey = &keys
+
+	err := c.caller.Do(ctx, "UntagResources", func() error {
+		_, err := c.rdsCli.UntagResources(request)
+		return err
+	})
+	return err
+}
+
+// ValidateEngine validates the database engine of an RDS instance, which is
+// case insensitive.
+func ValidateEngine(engine string) error {
+	if strings.EqualFold(engine, v1alpha1.MysqlEngine) || strings.EqualFold(engine, v1alpha1.PostgresqlEngine) {
+		return nil
+	}
+	return errors.Errorf(errFmtUnsupportedEngine, engine, v1alpha1.MysqlEngine, v1alpha1.PostgresqlEngine)
+}
+
 // LateInitialize fills the empty fields in *v1alpha1.RDSInstanceParameters with
 // the values seen in rds.DBInstance.
 func LateInitialize(in *v1alpha1.RDSInstanceParameters, db *DBInstance) {
-	in.Engine = db.Engine
+	in.Engine = clients.LateInitializeString(in.Engine, db.Engine)
+	in.EngineVersion = clients.LateInitializeString(in.EngineVersion, db.EngineVersion)
+	in.DBInstanceClass = clients.LateInitializeString(in.DBInstanceClass, db.DBInstanceClass)
+}
+
+// IsUpToDate checks whether the engine, the engine version, the class and the
+// storage of db match the parameters p. The parameters which aren't set, and so
+// are late initialized, aren't compared.
+func IsUpToDate(p v1alpha1.RDSInstanceParameters, db *DBInstance) bool {
+	return (p.Engine == "" || strings.EqualFold(p.Engine, db.Engine)) &&
+		(p.EngineVersion == "" || p.EngineVersion == db.EngineVersion) &&
+		(p.DBInstanceClass == "" || p.DBInstanceClass == db.DBInstanceClass) &&
+		(p.DBInstanceStorageInGB == 0 || p.DBInstanceStorageInGB == db.DBInstanceStorageInGB)
 }
 
 // GenerateObservation is used to produce v1alpha1.RDSInstanceObservation from
@@ -195,16 +395,3 @@ func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RDSInstanceParameters)
 		DBInstanceStorageInGB: p.DBInstanceStorageInGB,
 	}
 }
-
-// IsErrorNotFound helper function to test for ErrCodeDBInstanceNotFoundFault error
-func IsErrorNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	// If instance already remove from console.  should ignore when delete instance
-	var srverr *sdkerrors.ServerError
-	if !errors.As(err, &srverr) {
-		return false || errors.Is(err, ErrDBInstanceNotFound)
-	}
-	return srverr.ErrorCode() == ErrCodeInstanceNotFound
-}
@@ -11,6 +11,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 )
 
 func TestGenerateObservation(t *testing.T) {
@@ -44,6 +45,48 @@ func TestGenerateObservation(t *testing.T) {
 	}
 }
 
+func TestLateInitialize(t *testing.T) {
+	in := &v1alpha1.RDSInstanceParameters{Engine: "MySQL"}
+	LateInitialize(in, &DBInstance{Engine: "mysql", EngineVersion: "8.0", DBInstanceClass: "rds.mysql.t1.small"})
+	want := &v1alpha1.RDSInstanceParameters{Engine: "MySQL", EngineVersion: "8.0", DBInstanceClass: "rds.mysql.t1.small"}
+	if diff := cmp.Diff(want, in); diff != "" {
+		t.Errorf("\nLateInitialize(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	db := &DBInstance{Engine: "MySQL", EngineVersion: "8.0", DBInstanceClass: "rds.mysql.t1.small", DBInstanceStorageInGB: 20}
+	cases := map[string]struct {
+		p    v1alpha1.RDSInstanceParameters
+		want bool
+	}{
+		"UpToDate": {
+			p:    v1alpha1.RDSInstanceParameters{Engine: "mysql", EngineVersion: "8.0", DBInstanceClass: "rds.mysql.t1.small", DBInstanceStorageInGB: 20},
+			want: true,
+		},
+		"NotSet": {
+			p:    v1alpha1.RDSInstanceParameters{},
+			want: true,
+		},
+		"EngineVersionDiffers": {
+			p: v1alpha1.RDSInstanceParameters{EngineVersion: "5.7"},
+		},
+		"ClassDiffers": {
+			p: v1alpha1.RDSInstanceParameters{DBInstanceClass: "rds.mysql.c1.large"},
+		},
+		"StorageDiffers": {
+			p: v1alpha1.RDSInstanceParameters{DBInstanceStorageInGB: 40},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsUpToDate(tc.p, db); got != tc.want {
+				t.Errorf("IsUpToDate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestIsErrorNotFound(t *testing.T) {
 	var response = make(map[string]string)
 	response["Code"] = ErrCodeInstanceNotFound
@@ -77,7 +120,7 @@ func TestIsErrorNotFound(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			err := sdkerrors.NewServerError(tc.args.httpStatus, tc.args.responseContent, tc.args.comment)
-			isErrorNotFound := IsErrorNotFound(err)
+			isErrorNotFound := errorclass.IsNotFound(err)
 			if diff := cmp.Diff(tc.want.found, isErrorNotFound, test.EquateConditions()); diff != "" {
 				t.Errorf("\nIsErrorNotFound(...) %s\n", diff)
 			}
@@ -116,7 +159,7 @@ func TestNewClient(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			_, err := NewClient(tc.args.ctx, tc.args.accessKeyID, tc.args.accessKeySecret, tc.args.securityToken, tc.args.region)
+			_, err := NewClient(tc.args.ctx, tc.args.accessKeyID, tc.args.accessKeySecret, tc.args.securityToken, tc.args.region, "", nil, nil)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\nNewClient(...) -want error, +got error:\n%s\n", diff)
 			}
@@ -125,7 +168,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestDescribeDBInstance(t *testing.T) {
-	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing")
+	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing", "", nil, nil)
 	type args struct {
 		id string
 	}
@@ -151,7 +194,7 @@ func TestDescribeDBInstance(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			db, err := c.DescribeDBInstance(tc.args.id)
+			db, err := c.DescribeDBInstance(context.Background(), tc.args.id)
 			if err != nil {
 				var srverr *sdkerrors.ServerError
 				if errors.As(err, &srverr) {
@@ -169,7 +212,7 @@ func TestDescribeDBInstance(t *testing.T) {
 }
 
 func TestCreateDBInstance(t *testing.T) {
-	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing")
+	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing", "", nil, nil)
 	type args struct {
 		req CreateDBInstanceRequest
 	}
@@ -202,7 +245,7 @@ func TestCreateDBInstance(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			db, err := c.CreateDBInstance(&tc.args.req)
+			db, err := c.CreateDBInstance(context.Background(), &tc.args.req)
 			if err != nil {
 				var srverr *sdkerrors.ServerError
 				if errors.As(err, &srverr) {
@@ -220,7 +263,7 @@ func TestCreateDBInstance(t *testing.T) {
 }
 
 func TestDeleteDBInstance(t *testing.T) {
-	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing")
+	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing", "", nil, nil)
 	type args struct {
 		id string
 	}
@@ -244,7 +287,7 @@ func TestDeleteDBInstance(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := c.DeleteDBInstance(tc.args.id)
+			err := c.DeleteDBInstance(context.Background(), tc.args.id)
 			if err != nil {
 				var srverr *sdkerrors.ServerError
 				if errors.As(err, &srverr) {
@@ -258,7 +301,7 @@ func TestDeleteDBInstance(t *testing.T) {
 }
 
 func TestCreateAccount(t *testing.T) {
-	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing")
+	c, _ := NewClient(context.TODO(), "xwerwrfYfwq934tsfsFAKED", "fsdfwerfaUIIffaYYYYYFUUFHUDSDKSDFAKED", "", "cn-beijing", "", nil, nil)
 	type args struct {
 		id       string
 		username string
@@ -286,7 +329,7 @@ func TestCreateAccount(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			err := c.CreateAccount(tc.args.id, tc.args.username, tc.args.password)
+			err := c.CreateAccount(context.Background(), tc.args.id, tc.args.username, tc.args.password)
 			if err != nil {
 				var srverr *sdkerrors.ServerError
 				if errors.As(err, &srverr) {
@@ -19,23 +19,28 @@ package redis
 import (
 	"context"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
-	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
 	aliredis "github.com/aliyun/alibaba-cloud-sdk-go/services/r-kvstore"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 )
 
 var (
 	// ErrDBInstanceNotFound indicates DBInstance not found
-	ErrDBInstanceNotFound = errors.New("DBInstanceNotFound")
+	ErrDBInstanceNotFound = errorclass.New(errorclass.NotFound, "DBInstanceNotFound")
 )
 
 const (
-	// DefaultReadTime indicates default connect timeout number
+	// DefaultReadTime indicates the default read timeout of the
+	// time-consuming requests, unless ProviderConfig configures one
 	DefaultReadTime = 60 * time.Second
 	// PubilConnectionDomain indicates instances connect domain
 	PubilConnectionDomain = "-pb.redis.rds.aliyuncs.com"
@@ -43,17 +48,27 @@ const (
 	HTTPSScheme = "https"
 	// VPCNetworkType indicates network type by vpc
 	VPCNetworkType = "VPC"
+
+	// tagResourceType is the type of the tagged Redis instances
+	tagResourceType = "INSTANCE"
+
+	// describePageSize is the maximum number of instances described per page
+	describePageSize = 50
 )
 
 // Client defines Redis client operations
 type Client interface {
-	DescribeDBInstance(id string) (*DBInstance, error)
-	CreateAccount(id, username, password string) error
-	CreateDBInstance(*CreateRedisInstanceRequest) (*DBInstance, error)
-	DeleteDBInstance(id string) error
-	AllocateInstancePublicConnection(id string, port int) (string, error)
-	ModifyDBInstanceConnectionString(id string, port int) (string, error)
-	Update(id string, req *ModifyRedisInstanceRequest) error
+	DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error)
+	FindDBInstances(ctx context.Context, name string, tags map[string]string) ([]*DBInstance, error)
+	CreateAccount(ctx context.Context, id, username, password string) error
+	CreateDBInstance(ctx context.Context, req *CreateRedisInstanceRequest) (*DBInstance, error)
+	DeleteDBInstance(ctx context.Context, id string) error
+	AllocateInstancePublicConnection(ctx context.Context, id string, port int) (string, error)
+	ModifyDBInstanceConnectionString(ctx context.Context, id string, port int) (string, error)
+	Update(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error
+	ListTags(ctx context.Context, id string) (map[string]string, error)
+	TagResources(ctx context.Context, id string, tags map[string]string) error
+	UntagResources(ctx context.Context, id string, keys []string) error
 }
 
 // DBInstance defines the DB instance information
@@ -64,6 +79,17 @@ type DBInstance struct {
 	// Instance status
 	Status string
 
+	// Engine version, instance class and type
+	EngineVersion string
+	InstanceClass string
+	InstanceType  string
+
+	// Billing method and network
+	ChargeType  string
+	NetworkType string
+	VpcID       string
+	VSwitchID   string
+
 	// Endpoint specifies the connection endpoint.
 	Endpoint *v1alpha1.Endpoint
 }
@@ -81,6 +107,9 @@ type CreateRedisInstanceRequest struct {
 	NetworkType    string
 	VpcID          string
 	VSwitchID      string
+	// ClientToken ensures that the instance is only created once when the
+	// request is retried.
+	ClientToken string
 }
 
 // ModifyRedisInstanceRequest defines the request info to modify DB Instance
@@ -90,57 +119,139 @@ type ModifyRedisInstanceRequest struct {
 
 type client struct {
 	redisCli *aliredis.Client
+	region   string
+	// endpoint overrides the endpoint resolved by the SDK if it's not empty
+	endpoint string
+	// readTimeout is the read timeout of the time-consuming requests
+	readTimeout time.Duration
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
-// NewClient creates new Redis RedisClient
-func NewClient(ctx context.Context, accessKeyID, accessKeySecret, region string) (Client, error) {
-	redisCli, err := aliredis.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+// NewClient creates new Redis RedisClient. The endpoint is resolved by the SDK
+// from region if endpoint is empty.
+func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (Client, error) {
+	var (
+		redisCli *aliredis.Client
+		err      error
+	)
+	if securityToken != "" {
+		redisCli, err = aliredis.NewClientWithStsToken(region, accessKeyID, accessKeySecret, securityToken)
+	} else {
+		redisCli, err = aliredis.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+	}
+
 	if err != nil {
 		return nil, err
 	}
-	c := &client{redisCli: redisCli}
+	if err := transport.ConfigureSDKClient(&redisCli.Client); err != nil {
+		return nil, err
+	}
+	c := &client{redisCli: redisCli, region: region, endpoint: endpoint, caller: caller, readTimeout: transport.ReadTimeoutOr(DefaultReadTime)}
 	return c, nil
 }
 
-func (c *client) DescribeDBInstance(id string) (*DBInstance, error) {
+func (c *client) DescribeDBInstance(ctx context.Context, id string) (*DBInstance, error) {
 	request := aliredis.CreateDescribeInstancesRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 
 	request.InstanceIds = id
 
-	response, err := c.redisCli.DescribeInstances(request)
+	var response *aliredis.DescribeInstancesResponse
+	err := c.caller.Do(ctx, "DescribeInstances", func() (err error) {
+		response, err = c.redisCli.DescribeInstances(request)
+		return err
+	})
 	if err != nil {
 		return nil, errors.Wrap(err, "cannot describe redis instance")
 	}
 	if len(response.Instances.KVStoreInstance) == 0 {
 		return nil, ErrDBInstanceNotFound
 	}
-	rsp := response.Instances.KVStoreInstance[0]
-	in := &DBInstance{
-		ID:     rsp.InstanceId,
-		Status: rsp.InstanceStatus,
+	return newDBInstance(response.Instances.KVStoreInstance[0]), nil
+}
+
+// FindDBInstances finds the instances whose name is name, if it's not empty,
+// and which have all of tags.
+func (c *client) FindDBInstances(ctx context.Context, name string, tags map[string]string) ([]*DBInstance, error) {
+	request := aliredis.CreateDescribeInstancesRequest()
+	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
+	request.PageSize = requests.NewInteger(describePageSize)
+
+	// The search key also matches the instances whose name or ID contains
+	// it, they are filtered out below.
+	request.SearchKey = name
+	if len(tags) > 0 {
+		tag := make([]aliredis.DescribeInstancesTag, 0, len(tags))
+		for k, v := range tags {
+			tag = append(tag, aliredis.DescribeInstancesTag{Key: k, Value: v})
+		}
+		request.Tag = &tag
 	}
 
-	return in, nil
+	var instances []*DBInstance
+	for page, described := 1, 0; ; page++ {
+		request.PageNumber = requests.NewInteger(page)
+		var response *aliredis.DescribeInstancesResponse
+		err := c.caller.Do(ctx, "DescribeInstances", func() (err error) {
+			response, err = c.redisCli.DescribeInstances(request)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot describe redis instances")
+		}
+		for _, rsp := range response.Instances.KVStoreInstance {
+			if name == "" || rsp.InstanceName == name {
+				instances = append(instances, newDBInstance(rsp))
+			}
+		}
+		described += len(response.Instances.KVStoreInstance)
+		if len(response.Instances.KVStoreInstance) == 0 || described >= response.TotalCount {
+			return instances, nil
+		}
+	}
+}
+
+func newDBInstance(rsp aliredis.KVStoreInstance) *DBInstance {
+	return &DBInstance{
+		ID:            rsp.InstanceId,
+		Status:        rsp.InstanceStatus,
+		EngineVersion: rsp.EngineVersion,
+		InstanceClass: rsp.InstanceClass,
+		InstanceType:  rsp.InstanceType,
+		ChargeType:    rsp.ChargeType,
+		NetworkType:   rsp.NetworkType,
+		VpcID:         rsp.VpcId,
+		VSwitchID:     rsp.VSwitchId,
+	}
 }
 
-func (c *client) CreateDBInstance(req *CreateRedisInstanceRequest) (*DBInstance, error) {
+func (c *client) CreateDBInstance(ctx context.Context, req *CreateRedisInstanceRequest) (*DBInstance, error) {
 	request := aliredis.CreateCreateInstanceRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 
 	request.InstanceName = req.Name
 	request.EngineVersion = req.EngineVersion
 	request.InstanceClass = req.InstanceClass
 	request.InstanceType = req.InstanceType
-	request.ReadTimeout = DefaultReadTime
+	request.ReadTimeout = c.readTimeout
 	request.ChargeType = req.ChargeType
 	request.NetworkType = req.NetworkType
+	request.Token = req.ClientToken
 
 	if req.NetworkType == VPCNetworkType {
 		request.VpcId = req.VpcID
 		request.VSwitchId = req.VSwitchID
 	}
-	resp, err := c.redisCli.CreateInstance(request)
+	var resp *aliredis.CreateInstanceResponse
+	err := c.caller.Do(ctx, "CreateInstance", func() (err error) {
+		resp, err = c.redisCli.CreateInstance(request)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -154,25 +265,33 @@ func (c *client) CreateDBInstance(req *CreateRedisInstanceRequest) (*DBInstance,
 	}, nil
 }
 
-func (c *client) CreateAccount(id, user, pw string) error {
+func (c *client) CreateAccount(ctx context.Context, id, user, pw string) error {
 	request := aliredis.CreateCreateAccountRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 	request.InstanceId = id
 	request.AccountName = user
 	request.AccountPassword = pw
-	request.ReadTimeout = DefaultReadTime
+	request.ReadTimeout = c.readTimeout
 
-	_, err := c.redisCli.CreateAccount(request)
+	err := c.caller.Do(ctx, "CreateAccount", func() error {
+		_, err := c.redisCli.CreateAccount(request)
+		return err
+	})
 	return err
 }
 
-func (c *client) DeleteDBInstance(id string) error {
+func (c *client) DeleteDBInstance(ctx context.Context, id string) error {
 	request := aliredis.CreateDeleteInstanceRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 
 	request.InstanceId = id
 
-	_, err := c.redisCli.DeleteInstance(request)
+	err := c.caller.Do(ctx, "DeleteInstance", func() error {
+		_, err := c.redisCli.DeleteInstance(request)
+		return err
+	})
 	return err
 }
 
@@ -185,6 +304,31 @@ func GenerateObservation(db *DBInstance) v1alpha1.RedisInstanceObservation {
 	}
 }
 
+// LateInitialize fills the empty fields in *v1alpha1.RedisInstanceParameters
+// with the values seen in redis.DBInstance.
+func LateInitialize(in *v1alpha1.RedisInstanceParameters, db *DBInstance) {
+	in.EngineVersion = clients.LateInitializeString(in.EngineVersion, db.EngineVersion)
+	in.InstanceClass = clients.LateInitializeString(in.InstanceClass, db.InstanceClass)
+	in.InstanceType = clients.LateInitializeString(in.InstanceType, db.InstanceType)
+	in.ChargeType = clients.LateInitializeString(in.ChargeType, db.ChargeType)
+	in.NetworkType = clients.LateInitializeString(in.NetworkType, db.NetworkType)
+	in.VpcID = clients.LateInitializeString(in.VpcID, db.VpcID)
+	in.VSwitchID = clients.LateInitializeString(in.VSwitchID, db.VSwitchID)
+}
+
+// IsUpToDate checks whether the engine version, the class, the type, the
+// billing method and the network of db match the parameters p. The parameters
+// which aren't set, and so are late initialized, aren't compared.
+func IsUpToDate(p v1alpha1.RedisInstanceParameters, db *DBInstance) bool {
+	return (p.EngineVersion == "" || p.EngineVersion == db.EngineVersion) &&
+		(p.InstanceClass == "" || p.InstanceClass == db.InstanceClass) &&
+		(p.InstanceType == "" || strings.EqualFold(p.InstanceType, db.InstanceType)) &&
+		(p.ChargeType == "" || strings.EqualFold(p.ChargeType, db.ChargeType)) &&
+		(p.NetworkType == "" || strings.EqualFold(p.NetworkType, db.NetworkType)) &&
+		(p.VpcID == "" || p.VpcID == db.VpcID) &&
+		(p.VSwitchID == "" || p.VSwitchID == db.VSwitchID)
+}
+
 // MakeCreateDBInstanceRequest generates CreateDBInstanceRequest
 func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RedisInstanceParameters) *CreateRedisInstanceRequest {
 	return &CreateRedisInstanceRequest{
@@ -200,64 +344,126 @@ func MakeCreateDBInstanceRequest(name string, p *v1alpha1.RedisInstanceParameter
 	}
 }
 
-// IsErrorNotFound helper function to test for ErrCodeDBInstanceNotFoundFault error
-func IsErrorNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	// If the instance is already removed, errors should be ignored when deleting it.
-	var srverr *sdkerrors.ServerError
-	if !errors.As(err, &srverr) {
-		return false || errors.Is(err, ErrDBInstanceNotFound)
-	}
-
-	return srverr.ErrorCode() == "InvalidInstanceId.NotFound"
-}
-
-func (c *client) AllocateInstancePublicConnection(id string, port int) (string, error) {
+func (c *client) AllocateInstancePublicConnection(ctx context.Context, id string, port int) (string, error) {
 	request := aliredis.CreateAllocateInstancePublicConnectionRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 	request.InstanceId = id
 	request.ConnectionStringPrefix = id + PubilConnectionDomain
 	request.Port = strconv.Itoa(port)
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.AllocateInstancePublicConnection(request)
+	request.ReadTimeout = c.readTimeout
+	err := c.caller.Do(ctx, "AllocateInstancePublicConnection", func() error {
+		_, err := c.redisCli.AllocateInstancePublicConnection(request)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
 	return request.ConnectionStringPrefix, err
 }
 
-func (c *client) ModifyDBInstanceConnectionString(id string, port int) (string, error) {
+func (c *client) ModifyDBInstanceConnectionString(ctx context.Context, id string, port int) (string, error) {
 	request := aliredis.CreateModifyDBInstanceConnectionStringRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 	request.DBInstanceId = id
 	request.CurrentConnectionString = id + PubilConnectionDomain
 	request.Port = strconv.Itoa(port)
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.ModifyDBInstanceConnectionString(request)
+	request.ReadTimeout = c.readTimeout
+	err := c.caller.Do(ctx, "ModifyDBInstanceConnectionString", func() error {
+		_, err := c.redisCli.ModifyDBInstanceConnectionString(request)
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
 	return request.CurrentConnectionString, err
 }
 
-func (c *client) Update(id string, req *ModifyRedisInstanceRequest) error {
+func (c *client) Update(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error {
 	if req.InstanceClass == "" {
 		return errors.New("modify instances spec is require")
 	}
 	if req.InstanceClass != "" {
-		return c.modifyInstanceSpec(id, req)
+		return c.modifyInstanceSpec(ctx, id, req)
 	}
 	return nil
 }
 
-func (c *client) modifyInstanceSpec(id string, req *ModifyRedisInstanceRequest) error {
+func (c *client) modifyInstanceSpec(ctx context.Context, id string, req *ModifyRedisInstanceRequest) error {
 	request := aliredis.CreateModifyInstanceSpecRequest()
 	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
 	request.InstanceId = id
 	request.InstanceClass = req.InstanceClass
-	request.ReadTimeout = DefaultReadTime
-	_, err := c.redisCli.ModifyInstanceSpec(request)
+	request.ReadTimeout = c.readTimeout
+	err := c.caller.Do(ctx, "ModifyInstanceSpec", func() error {
+		_, err := c.redisCli.ModifyInstanceSpec(request)
+		return err
+	})
+	return err
+}
+
+func (c *client) ListTags(ctx context.Context, id string) (map[string]string, error) {
+	request := aliredis.CreateListTagResourcesRequest()
+	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+
+	tags := map[string]string{}
+	for {
+		var response *aliredis.ListTagResourcesResponse
+		err := c.caller.Do(ctx, "ListTagResources", func() (err error) {
+			response, err = c.redisCli.ListTagResources(request)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range response.TagResources.TagResource {
+			tags[t.TagKey] = t.TagValue
+		}
+		if response.NextToken == "" {
+			return tags, nil
+		}
+		request.NextToken = response.NextToken
+	}
+}
+
+func (c *client) TagResources(ctx context.Context, id string, tags map[string]string) error {
+	request := aliredis.CreateTagResourcesRequest()
+	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+	tag := make([]aliredis.TagResourcesTag, 0, len(tags))
+	for k, v := range tags {
+		tag = append(tag, aliredis.TagResourcesTag{Key: k, Value: v})
+	}
+	request.Tag = &tag
+
+	err := c.caller.Do(ctx, "TagResources", func() error {
+		_, err := c.redisCli.TagResources(request)
+		return err
+	})
+	return err
+}
+
+func (c *client) UntagResources(ctx context.Context, id string, keys []string) error {
+	request := aliredis.CreateUntagResourcesRequest()
+	request.Scheme = HTTPSScheme
+	request.Domain = c.endpoint
+	request.RegionId = c.region
+	request.ResourceType = tagResourceType
+	request.ResourceId = &[]string{id}
+	request.TagKey = &keys
+
+	err := c.caller.Do(ctx, "UntagResources", func() error {
+		_, err := c.redisCli.UntagResources(request)
+		return err
+	})
 	return err
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 )
 
 func TestGenerateObservation(t *testing.T) {
@@ -16,13 +17,57 @@ func TestGenerateObservation(t *testing.T) {
 	}
 }
 
+func TestLateInitialize(t *testing.T) {
+	in := &v1alpha1.RedisInstanceParameters{EngineVersion: "5.0", ChargeType: "PrePaid"}
+	LateInitialize(in, &DBInstance{EngineVersion: "4.0", InstanceType: "Redis", ChargeType: "PostPaid", NetworkType: "CLASSIC"})
+	if in.EngineVersion != "5.0" || in.ChargeType != "PrePaid" {
+		t.Errorf("LateInitialize: the set fields should not be changed, got EngineVersion=%v ChargeType=%v", in.EngineVersion, in.ChargeType)
+	}
+	if in.InstanceType != "Redis" || in.NetworkType != "CLASSIC" {
+		t.Errorf("LateInitialize: the empty fields should be filled, got InstanceType=%v NetworkType=%v", in.InstanceType, in.NetworkType)
+	}
+}
+
+func TestIsUpToDate(t *testing.T) {
+	db := &DBInstance{EngineVersion: "5.0", InstanceClass: "redis.master.small.default", InstanceType: "Redis", ChargeType: "PostPaid", NetworkType: "VPC", VpcID: "vpc-1", VSwitchID: "vsw-1"}
+	cases := map[string]struct {
+		p    v1alpha1.RedisInstanceParameters
+		want bool
+	}{
+		"UpToDate": {
+			p:    v1alpha1.RedisInstanceParameters{EngineVersion: "5.0", InstanceClass: "redis.master.small.default", InstanceType: "Redis", ChargeType: "PostPaid", NetworkType: "VPC", VpcID: "vpc-1", VSwitchID: "vsw-1"},
+			want: true,
+		},
+		"NotSet": {
+			p:    v1alpha1.RedisInstanceParameters{},
+			want: true,
+		},
+		"EngineVersionDiffers": {
+			p: v1alpha1.RedisInstanceParameters{EngineVersion: "4.0"},
+		},
+		"ClassDiffers": {
+			p: v1alpha1.RedisInstanceParameters{InstanceClass: "redis.master.mid.default"},
+		},
+		"VSwitchDiffers": {
+			p: v1alpha1.RedisInstanceParameters{VSwitchID: "vsw-2"},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsUpToDate(tc.p, db); got != tc.want {
+				t.Errorf("IsUpToDate(...): want %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
 func TestIsErrorNotFound(t *testing.T) {
 	var response = make(map[string]string)
 	response["Code"] = "InvalidInstanceId.NotFound"
 
 	responseContent, _ := json.Marshal(response) //nolint:errchkjson
 	err := errors.NewServerError(404, string(responseContent), "comment")
-	isErrorNotFound := IsErrorNotFound(err)
+	isErrorNotFound := errorclass.IsNotFound(err)
 	if !isErrorNotFound {
 		t.Errorf("IsErrorNotFound: want=%v, get=%v", true, isErrorNotFound)
 	}
@@ -18,47 +18,68 @@ package nas
 
 import (
 	"context"
+	"encoding/json"
 
 	openapi "github.com/alibabacloud-go/darabonba-openapi/client"
 	sdk "github.com/alibabacloud-go/slb-20140515/v2/client"
+	"github.com/alibabacloud-go/tea/tea"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 )
 
 const (
 	errFailedToCreateSLBClient = "failed to crate SLB client"
+
+	// loadBalancerResourceType is the type of the tagged SLB instances
+	loadBalancerResourceType = "instance"
+
+	// describePageSize is the maximum number of the SLB instances described
+	// by a request
+	describePageSize = 100
 )
 
 // ClientInterface creates a client interface
 type ClientInterface interface {
-	DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error)
-	CreateLoadBalancer(name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error)
-	DeleteLoadBalancer(region, loadBalancerID *string) error
+	DescribeLoadBalancers(ctx context.Context, region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error)
+	FindLoadBalancers(ctx context.Context, region *string, name string, tags map[string]string) ([]string, error)
+	CreateLoadBalancer(ctx context.Context, name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error)
+	DeleteLoadBalancer(ctx context.Context, region, loadBalancerID *string) error
+	ListLoadBalancerTags(ctx context.Context, region, loadBalancerID *string) (map[string]string, error)
+	TagLoadBalancer(ctx context.Context, region, loadBalancerID *string, tags map[string]string) error
+	UntagLoadBalancer(ctx context.Context, region, loadBalancerID *string, keys []string) error
 }
 
 // SDKClient is the SDK client for SLBLoadBalancer
 type SDKClient struct {
 	Client *sdk.Client
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
 // NewClient will create SLB client
-func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, securityToken string) (*SDKClient, error) {
+func NewClient(ctx context.Context, endpoint string, accessKeyID string, accessKeySecret string, securityToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*SDKClient, error) {
 	config := &openapi.Config{
 		AccessKeyId:     &accessKeyID,
 		AccessKeySecret: &accessKeySecret,
 		SecurityToken:   &securityToken,
 		Endpoint:        &endpoint,
 	}
+	if err := transport.ConfigureOpenAPIConfig(config); err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateSLBClient)
+	}
 	client, err := sdk.NewClient(config)
 	if err != nil {
 		return nil, errors.Wrap(err, errFailedToCreateSLBClient)
 	}
-	return &SDKClient{Client: client}, nil
+	return &SDKClient{Client: client, caller: caller}, nil
 }
 
 // DescribeLoadBalancers describes a SLBLoadBalancer instance
-func (c *SDKClient) DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error) {
+func (c *SDKClient) DescribeLoadBalancers(ctx context.Context, region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error) {
 	describeLoadBalancersRequest := &sdk.DescribeLoadBalancersRequest{
 		RegionId: region,
 	}
@@ -71,16 +92,81 @@ func (c *SDKClient) DescribeLoadBalancers(region, loadBalancerID, vpcID, vSwitch
 	if vSwitchID != nil {
 		describeLoadBalancersRequest.VSwitchId = vSwitchID
 	}
-	fs, err := c.Client.DescribeLoadBalancers(describeLoadBalancersRequest)
+	var fs *sdk.DescribeLoadBalancersResponse
+	err := c.caller.Do(ctx, "DescribeLoadBalancers", func() (err error) {
+		fs, err = c.Client.DescribeLoadBalancers(describeLoadBalancersRequest)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 	return fs, nil
 }
 
+// FindLoadBalancers finds the IDs of the SLBLoadBalancer instances named
+// name, if it's not empty, and which have all of tags.
+func (c *SDKClient) FindLoadBalancers(ctx context.Context, region *string, name string, tags map[string]string) ([]string, error) {
+	describeLoadBalancersRequest := &sdk.DescribeLoadBalancersRequest{
+		RegionId: region,
+		PageSize: tea.Int32(describePageSize),
+	}
+	if name != "" {
+		describeLoadBalancersRequest.LoadBalancerName = tea.String(name)
+	}
+	if len(tags) > 0 {
+		filter := make([]map[string]string, 0, len(tags))
+		for k, v := range tags {
+			filter = append(filter, map[string]string{"tagKey": k, "tagValue": v})
+		}
+		t, err := json.Marshal(filter)
+		if err != nil {
+			return nil, err
+		}
+		describeLoadBalancersRequest.Tags = tea.String(string(t))
+	}
+
+	var ids []string
+	for page, described := int32(1), int32(0); ; page++ {
+		describeLoadBalancersRequest.PageNumber = tea.Int32(page)
+		var res *sdk.DescribeLoadBalancersResponse
+		err := c.caller.Do(ctx, "DescribeLoadBalancers", func() (err error) {
+			res, err = c.Client.DescribeLoadBalancers(describeLoadBalancersRequest)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		var lbs []*sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer
+		if res.Body.LoadBalancers != nil {
+			lbs = res.Body.LoadBalancers.LoadBalancer
+		}
+		// Only the SLB instances named exactly name are found.
+		for _, lb := range lbs {
+			if name == "" || tea.StringValue(lb.LoadBalancerName) == name {
+				ids = append(ids, tea.StringValue(lb.LoadBalancerId))
+			}
+		}
+		described += int32(len(lbs))
+		if len(lbs) == 0 || described >= tea.Int32Value(res.Body.TotalCount) {
+			return ids, nil
+		}
+	}
+}
+
 // CreateLoadBalancer creates a SLBLoadBalancer instance
-func (c *SDKClient) CreateLoadBalancer(name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error) {
-	createLoadBalancerRequest := &sdk.CreateLoadBalancerRequest{
+func (c *SDKClient) CreateLoadBalancer(ctx context.Context, name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error) {
+	createLoadBalancerRequest := MakeCreateLoadBalancerRequest(name, clb)
+	var res *sdk.CreateLoadBalancerResponse
+	err := c.caller.Do(ctx, "CreateLoadBalancer", func() (err error) {
+		res, err = c.Client.CreateLoadBalancer(createLoadBalancerRequest)
+		return err
+	})
+	return res, err
+}
+
+// MakeCreateLoadBalancerRequest generates CreateLoadBalancerRequest
+func MakeCreateLoadBalancerRequest(name string, clb v1alpha1.CLBParameter) *sdk.CreateLoadBalancerRequest {
+	return &sdk.CreateLoadBalancerRequest{
 		RegionId:                     clb.Region,
 		AddressType:                  clb.AddressType,
 		Address:                      clb.Address,
@@ -105,17 +191,79 @@ func (c *SDKClient) CreateLoadBalancer(name string, clb v1alpha1.CLBParameter) (
 		ModificationProtectionStatus: clb.ModificationProtectionStatus,
 		ModificationProtectionReason: clb.ModificationProtectionReason,
 	}
-	res, err := c.Client.CreateLoadBalancer(createLoadBalancerRequest)
-	return res, err
 }
 
 // DeleteLoadBalancer deletes the SLBLoadBalancer instance
-func (c *SDKClient) DeleteLoadBalancer(region, loadBalancerID *string) error {
+func (c *SDKClient) DeleteLoadBalancer(ctx context.Context, region, loadBalancerID *string) error {
 	deleteLoadBalancerRequest := &sdk.DeleteLoadBalancerRequest{
 		RegionId:       region,
 		LoadBalancerId: loadBalancerID,
 	}
-	_, err := c.Client.DeleteLoadBalancer(deleteLoadBalancerRequest)
+	err := c.caller.Do(ctx, "DeleteLoadBalancer", func() error {
+		_, err := c.Client.DeleteLoadBalancer(deleteLoadBalancerRequest)
+		return err
+	})
+	return err
+}
+
+// ListLoadBalancerTags lists the tags of the SLBLoadBalancer instance
+func (c *SDKClient) ListLoadBalancerTags(ctx context.Context, region, loadBalancerID *string) (map[string]string, error) {
+	listTagResourcesRequest := &sdk.ListTagResourcesRequest{
+		RegionId:     region,
+		ResourceType: tea.String(loadBalancerResourceType),
+		ResourceId:   []*string{loadBalancerID},
+	}
+	tags := map[string]string{}
+	for {
+		var res *sdk.ListTagResourcesResponse
+		err := c.caller.Do(ctx, "ListTagResources", func() (err error) {
+			res, err = c.Client.ListTagResources(listTagResourcesRequest)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if res.Body.TagResources != nil {
+			for _, t := range res.Body.TagResources.TagResource {
+				tags[tea.StringValue(t.TagKey)] = tea.StringValue(t.TagValue)
+			}
+		}
+		if tea.StringValue(res.Body.NextToken) == "" {
+			return tags, nil
+		}
+		listTagResourcesRequest.NextToken = res.Body.NextToken
+	}
+}
+
+// TagLoadBalancer adds or changes the tags of the SLBLoadBalancer instance
+func (c *SDKClient) TagLoadBalancer(ctx context.Context, region, loadBalancerID *string, tags map[string]string) error {
+	tagResourcesRequest := &sdk.TagResourcesRequest{
+		RegionId:     region,
+		ResourceType: tea.String(loadBalancerResourceType),
+		ResourceId:   []*string{loadBalancerID},
+	}
+	for k, v := range tags {
+		tagResourcesRequest.Tag = append(tagResourcesRequest.Tag, &sdk.TagResourcesRequestTag{Key: tea.String(k), Value: tea.String(v)})
+	}
+	err := c.caller.Do(ctx, "TagResources", func() error {
+		_, err := c.Client.TagResources(tagResourcesRequest)
+		return err
+	})
+	return err
+}
+
+// UntagLoadBalancer removes the tags of the SLBLoadBalancer instance
+func (c *SDKClient) UntagLoadBalancer(ctx context.Context, region, loadBalancerID *string, keys []string) error {
+	untagResourcesRequest := &sdk.UntagResourcesRequest{
+		RegionId:     region,
+		ResourceType: tea.String(loadBalancerResourceType),
+		ResourceId:   []*string{loadBalancerID},
+		TagKey:       tea.StringSlice(keys),
+	}
+	err := c.caller.Do(ctx, "UntagResources", func() error {
+		_, err := c.Client.UntagResources(untagResourcesRequest)
+		return err
+	})
 	return err
 }
 
@@ -136,6 +284,24 @@ func GenerateObservation(res *sdk.DescribeLoadBalancersResponse) v1alpha1.CLBObs
 	return observation
 }
 
+// LateInitialize fills the empty fields in *v1alpha1.CLBParameter with the
+// values the cloud defaulted for the LoadBalancer. AddressType and
+// InternetChargeType are not late initialized, as their observed values differ
+// from the requested ones.
+func LateInitialize(in *v1alpha1.CLBParameter, res *sdk.DescribeLoadBalancersResponse) {
+	if *res.Body.TotalCount == 0 {
+		return
+	}
+	lb := res.Body.LoadBalancers.LoadBalancer[0]
+	in.Bandwidth = clients.LateInitializeInt32Ptr(in.Bandwidth, lb.Bandwidth)
+	in.MasterZoneID = clients.LateInitializeStringPtr(in.MasterZoneID, lb.MasterZoneId)
+	in.SlaveZoneID = clients.LateInitializeStringPtr(in.SlaveZoneID, lb.SlaveZoneId)
+	in.PayType = clients.LateInitializeStringPtr(in.PayType, lb.PayType)
+	in.AddressIPVersion = clients.LateInitializeStringPtr(in.AddressIPVersion, lb.AddressIPVersion)
+	in.LoadBalancerSpec = clients.LateInitializeStringPtr(in.LoadBalancerSpec, lb.LoadBalancerSpec)
+	in.ResourceGroupID = clients.LateInitializeStringPtr(in.ResourceGroupID, lb.ResourceGroupId)
+}
+
 // IsUpdateToDate checks whether cr is up to date
 //nolint:gocyclo
 func IsUpdateToDate(cr *v1alpha1.CLB, res *sdk.DescribeLoadBalancersResponse) bool {
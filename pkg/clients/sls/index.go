@@ -19,6 +19,8 @@
 package sls
 
 import (
+	"context"
+
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/pkg/errors"
 
@@ -35,14 +37,27 @@ var (
 )
 
 // DescribeIndex describes SLS Logstore index
-func (c *LogClient) DescribeIndex(project, logstore *string) (*sdk.Index, error) {
-	index, err := c.Client.GetIndex(*project, *logstore)
+func (c *LogClient) DescribeIndex(ctx context.Context, project, logstore *string) (*sdk.Index, error) {
+	var index *sdk.Index
+	err := c.caller.Do(ctx, "GetIndex", func() (err error) {
+		index, err = c.Client.GetIndex(*project, *logstore)
+		return err
+	})
 	return index, errors.Wrap(err, ErrCodeLogstoreIndexNotExist)
 }
 
 // CreateIndex creates SLS Logstore index
+func (c *LogClient) CreateIndex(ctx context.Context, param v1alpha1.LogstoreIndexParameters) error {
+	index := MakeIndex(param)
+	err := c.caller.Do(ctx, "CreateIndex", func() error {
+		return c.Client.CreateIndex(*param.ProjectName, *param.LogstoreName, index)
+	})
+	return errors.Wrap(err, ErrCreateIndex)
+}
+
+// MakeIndex generates the Index of SLS Logstore index
 //nolint:gocyclo
-func (c *LogClient) CreateIndex(param v1alpha1.LogstoreIndexParameters) error {
+func MakeIndex(param v1alpha1.LogstoreIndexParameters) sdk.Index {
 	keys := map[string]sdk.IndexKey{}
 	for name, v := range param.Keys {
 		key := sdk.IndexKey{
@@ -61,22 +76,22 @@ func (c *LogClient) CreateIndex(param v1alpha1.LogstoreIndexParameters) error {
 		}
 		keys[name] = key
 	}
-	index := sdk.Index{
+	return sdk.Index{
 		Keys: keys,
 	}
-	err := c.Client.CreateIndex(*param.ProjectName, *param.LogstoreName, index)
-	return errors.Wrap(err, ErrCreateIndex)
 }
 
 // UpdateIndex updates SLS Logstore index
-func (c *LogClient) UpdateIndex(project, logstore *string, index *sdk.Index) error {
+func (c *LogClient) UpdateIndex(ctx context.Context, project, logstore *string, index *sdk.Index) error {
 	// TODO(zzxwill) Need to implement Update SLS Logstore index
 	return nil
 }
 
 // DeleteIndex deletes SLS Logstore index
-func (c *LogClient) DeleteIndex(project, logstore *string) error {
-	err := c.Client.DeleteIndex(*project, *logstore)
+func (c *LogClient) DeleteIndex(ctx context.Context, project, logstore *string) error {
+	err := c.caller.Do(ctx, "DeleteIndex", func() error {
+		return c.Client.DeleteIndex(*project, *logstore)
+	})
 	return errors.Wrap(err, ErrDeleteIndex)
 }
 
@@ -99,11 +114,3 @@ func IsIndexUpdateToDate(cr *v1alpha1.LogstoreIndex, index *sdk.Index) bool {
 	return true
 }
 
-// IsIndexNotFoundError is helper function to test whether SLS Logstore index cloud not be found
-func IsIndexNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var slserr *sdk.Error
-	return errors.As(err, &slserr) && slserr.Code == ErrCodeLogstoreIndexNotExist
-}
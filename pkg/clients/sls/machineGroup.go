@@ -19,6 +19,7 @@
 package sls
 
 import (
+	"context"
 	"reflect"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
@@ -44,14 +45,26 @@ var (
 )
 
 // DescribeMachineGroup describes SLS Logtail MachineGroup
-func (c *LogClient) DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error) {
-	machineGroup, err := c.Client.GetMachineGroup(*project, name)
+func (c *LogClient) DescribeMachineGroup(ctx context.Context, project *string, name string) (*sdk.MachineGroup, error) {
+	var machineGroup *sdk.MachineGroup
+	err := c.caller.Do(ctx, "GetMachineGroup", func() (err error) {
+		machineGroup, err = c.Client.GetMachineGroup(*project, name)
+		return err
+	})
 	return machineGroup, errors.Wrap(err, ErrCodeMachineGroupNotExist)
 }
 
 // CreateMachineGroup creates SLS Logtail MachineGroup
-//nolint:gocyclo
-func (c *LogClient) CreateMachineGroup(name string, param v1alpha1.MachineGroupParameters) error {
+func (c *LogClient) CreateMachineGroup(ctx context.Context, name string, param v1alpha1.MachineGroupParameters) error {
+	machineGroup := MakeMachineGroup(name, param)
+	err := c.caller.Do(ctx, "CreateMachineGroup", func() error {
+		return c.Client.CreateMachineGroup(*param.Project, machineGroup)
+	})
+	return errors.Wrap(err, ErrCreateMachineGroup)
+}
+
+// MakeMachineGroup generates the MachineGroup of SLS Logtail MachineGroup
+func MakeMachineGroup(name string, param v1alpha1.MachineGroupParameters) *sdk.MachineGroup {
 	machineGroup := &sdk.MachineGroup{
 		Name:          name,
 		MachineIDType: *param.MachineIDType,
@@ -61,20 +74,20 @@ func (c *LogClient) CreateMachineGroup(name string, param v1alpha1.MachineGroupP
 	if param.Type != nil {
 		machineGroup.Type = *param.Type
 	}
-
-	err := c.Client.CreateMachineGroup(*param.Project, machineGroup)
-	return errors.Wrap(err, ErrCreateMachineGroup)
+	return machineGroup
 }
 
 // UpdateMachineGroup updates SLS Logtail MachineGroup
-func (c *LogClient) UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error {
+func (c *LogClient) UpdateMachineGroup(ctx context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error {
 	// TODO(zzxwill) Need to implement Update SLS Logtail MachineGroup
 	return nil
 }
 
 // DeleteMachineGroup deletes SLS Logtail MachineGroup
-func (c *LogClient) DeleteMachineGroup(project *string, machineGroup string) error {
-	err := c.Client.DeleteMachineGroup(*project, machineGroup)
+func (c *LogClient) DeleteMachineGroup(ctx context.Context, project *string, machineGroup string) error {
+	err := c.caller.Do(ctx, "DeleteMachineGroup", func() error {
+		return c.Client.DeleteMachineGroup(*project, machineGroup)
+	})
 	return errors.Wrap(err, ErrDeleteMachineGroup)
 }
 
@@ -106,33 +119,32 @@ func IsMachineGroupUpdateToDate(cr *v1alpha1.MachineGroup, machineGroup *sdk.Mac
 	return true
 }
 
-// IsMachineGroupNotFoundError is helper function to test whether SLS Logtail MachineGroup cloud not be found
-func IsMachineGroupNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var slserr *sdk.Error
-	return errors.As(err, &slserr) && slserr.Code == ErrCodeMachineGroupNotExist
-}
-
 // GetAppliedConfigs gets applied configs to a machine group
-func (c *LogClient) GetAppliedConfigs(projectName *string,
+func (c *LogClient) GetAppliedConfigs(ctx context.Context, projectName *string,
 	groupName *string) ([]string, error) {
-	configs, err := c.Client.GetAppliedConfigs(*projectName, *groupName)
+	var configs []string
+	err := c.caller.Do(ctx, "GetAppliedConfigs", func() (err error) {
+		configs, err = c.Client.GetAppliedConfigs(*projectName, *groupName)
+		return err
+	})
 	return configs, errors.Wrap(err, ErrGetAppliedConfigs)
 }
 
 // ApplyConfigToMachineGroup applied a config to a machine group
-func (c *LogClient) ApplyConfigToMachineGroup(projectName,
+func (c *LogClient) ApplyConfigToMachineGroup(ctx context.Context, projectName,
 	groupName, confName *string) error {
-	err := c.Client.ApplyConfigToMachineGroup(*projectName, *confName, *groupName)
+	err := c.caller.Do(ctx, "ApplyConfigToMachineGroup", func() error {
+		return c.Client.ApplyConfigToMachineGroup(*projectName, *confName, *groupName)
+	})
 	return errors.Wrap(err, ErrApplyConfigToMachineGroup)
 }
 
 // RemoveConfigFromMachineGroup remove a config from a machine group
-func (c *LogClient) RemoveConfigFromMachineGroup(projectName,
+func (c *LogClient) RemoveConfigFromMachineGroup(ctx context.Context, projectName,
 	groupName, confName *string) error {
-	err := c.Client.RemoveConfigFromMachineGroup(*projectName, *confName, *groupName)
+	err := c.caller.Do(ctx, "RemoveConfigFromMachineGroup", func() error {
+		return c.Client.RemoveConfigFromMachineGroup(*projectName, *confName, *groupName)
+	})
 	return errors.Wrap(err, ErrRemoveConfigFromMachineGroup)
 }
 
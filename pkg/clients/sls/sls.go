@@ -19,15 +19,22 @@
 package sls
 
 import (
+	"context"
 	"fmt"
+	"regexp/syntax"
 
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	"github.com/pkg/errors"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 )
 
 var (
+	// ErrFailedToCreateSLSClient is the error of failing to create an SLS client
+	ErrFailedToCreateSLSClient = "FailedToCreateSLSClient"
+
 	// ErrCodeProjectNotExist error code of ServerError when Project not found
 	ErrCodeProjectNotExist = "ProjectNotExist"
 	// ErrFailedToGetSLSProject is the error of failing to get an SLS project
@@ -38,6 +45,12 @@ var (
 	ErrFailedToUpdateSLSProject = "FailedToUpdateSLSProject"
 	// ErrFailedToDeleteSLSProject is the error of failing to delete an SLS project
 	ErrFailedToDeleteSLSProject = "FailedToDeleteSLSProject"
+	// ErrFailedToListSLSProjectTags is the error of failing to list the tags of an SLS project
+	ErrFailedToListSLSProjectTags = "FailedToListSLSProjectTags"
+	// ErrFailedToTagSLSProject is the error of failing to tag an SLS project
+	ErrFailedToTagSLSProject = "FailedToTagSLSProject"
+	// ErrFailedToUntagSLSProject is the error of failing to untag an SLS project
+	ErrFailedToUntagSLSProject = "FailedToUntagSLSProject"
 
 	// ErrCodeStoreNotExist error code of ServerError when LogStore not found
 	ErrCodeStoreNotExist = "LogStoreNotExist"
@@ -56,74 +69,149 @@ var (
 
 // LogClientInterface is the Log client interface
 type LogClientInterface interface {
-	Describe(name string) (*sdk.LogProject, error)
-	Create(name, description string) (*sdk.LogProject, error)
-	Update(name, description string) (*sdk.LogProject, error)
-	Delete(name string) error
-
-	DescribeStore(project string, logstore string) (*sdk.LogStore, error)
-	CreateStore(project string, store *sdk.LogStore) error
-	UpdateStore(project string, logstore string, ttl int) error
-	DeleteStore(project string, logstore string) error
-
-	DescribeConfig(project string, config string) (*sdk.LogConfig, error)
-	CreateConfig(name string, config v1alpha1.LogtailParameters) error
-	UpdateConfig(project string, config *sdk.LogConfig) error
-	DeleteConfig(project string, config string) error
-
-	DescribeIndex(project, logstore *string) (*sdk.Index, error)
-	CreateIndex(param v1alpha1.LogstoreIndexParameters) error
-	UpdateIndex(project, logstore *string, index *sdk.Index) error
-	DeleteIndex(project, logstore *string) error
-
-	DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error)
-	CreateMachineGroup(name string, param v1alpha1.MachineGroupParameters) error
-	UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error
-	DeleteMachineGroup(project *string, logstore string) error
-
-	GetAppliedConfigs(projectName *string, groupName *string) ([]string, error)
-	ApplyConfigToMachineGroup(projectName, groupName, confName *string) error
-	RemoveConfigFromMachineGroup(projectName, groupName, confName *string) error
+	Describe(ctx context.Context, name string) (*sdk.LogProject, error)
+	Create(ctx context.Context, name, description string) (*sdk.LogProject, error)
+	Update(ctx context.Context, name, description string) (*sdk.LogProject, error)
+	Delete(ctx context.Context, name string) error
+	ListTags(ctx context.Context, name string) (map[string]string, error)
+	TagProject(ctx context.Context, name string, tags map[string]string) error
+	UntagProject(ctx context.Context, name string, keys []string) error
+
+	DescribeStore(ctx context.Context, project string, logstore string) (*sdk.LogStore, error)
+	CreateStore(ctx context.Context, project string, store *sdk.LogStore) error
+	UpdateStore(ctx context.Context, project string, logstore string, ttl int) error
+	DeleteStore(ctx context.Context, project string, logstore string) error
+
+	DescribeConfig(ctx context.Context, project string, config string) (*sdk.LogConfig, error)
+	CreateConfig(ctx context.Context, name string, config v1alpha1.LogtailParameters) error
+	UpdateConfig(ctx context.Context, project string, config *sdk.LogConfig) error
+	DeleteConfig(ctx context.Context, project string, config string) error
+
+	DescribeIndex(ctx context.Context, project, logstore *string) (*sdk.Index, error)
+	CreateIndex(ctx context.Context, param v1alpha1.LogstoreIndexParameters) error
+	UpdateIndex(ctx context.Context, project, logstore *string, index *sdk.Index) error
+	DeleteIndex(ctx context.Context, project, logstore *string) error
+
+	DescribeMachineGroup(ctx context.Context, project *string, name string) (*sdk.MachineGroup, error)
+	CreateMachineGroup(ctx context.Context, name string, param v1alpha1.MachineGroupParameters) error
+	UpdateMachineGroup(ctx context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error
+	DeleteMachineGroup(ctx context.Context, project *string, logstore string) error
+
+	GetAppliedConfigs(ctx context.Context, projectName *string, groupName *string) ([]string, error)
+	ApplyConfigToMachineGroup(ctx context.Context, projectName, groupName, confName *string) error
+	RemoveConfigFromMachineGroup(ctx context.Context, projectName, groupName, confName *string) error
 }
 
 // LogClient is the SDK client of SLS
 type LogClient struct {
 	Client sdk.ClientInterface
+	// caller sends the requests, limiting their rate and recording their
+	// metrics
+	caller *apicall.Caller
 }
 
 // NewClient creates new SLS client
-func NewClient(accessKeyID, accessKeySecret, securityToken, endpoint string) *LogClient {
-	logClient := sdk.CreateNormalInterface(endpoint, accessKeyID, accessKeySecret, securityToken)
-	return &LogClient{Client: logClient}
+func NewClient(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*LogClient, error) {
+	logClient := &sdk.Client{
+		Endpoint:        endpoint,
+		AccessKeyID:     accessKeyID,
+		AccessKeySecret: accessKeySecret,
+		SecurityToken:   securityToken,
+	}
+	if transport != nil {
+		t, err := transport.NewTransport()
+		if err != nil {
+			return nil, errors.Wrap(err, ErrFailedToCreateSLSClient)
+		}
+		registerTransport(endpoint, t)
+		logClient.RequestTimeOut = transport.ReadTimeout
+	}
+	return &LogClient{Client: logClient, caller: caller}, nil
 }
 
 // ----------------------SLS Project------------------------------ //
 
 // Describe describes SLS project
-func (c *LogClient) Describe(name string) (*sdk.LogProject, error) {
-	logProject, err := c.Client.GetProject(name)
+func (c *LogClient) Describe(ctx context.Context, name string) (*sdk.LogProject, error) {
+	var logProject *sdk.LogProject
+	err := c.caller.Do(ctx, "GetProject", func() (err error) {
+		logProject, err = c.Client.GetProject(name)
+		return err
+	})
 	return logProject, errors.Wrap(err, ErrFailedToGetSLSProject)
 }
 
 // Create creates SLS project
-func (c *LogClient) Create(name, description string) (*sdk.LogProject, error) {
-	logProject, err := c.Client.CreateProject(name, description)
+func (c *LogClient) Create(ctx context.Context, name, description string) (*sdk.LogProject, error) {
+	var logProject *sdk.LogProject
+	err := c.caller.Do(ctx, "CreateProject", func() (err error) {
+		logProject, err = c.Client.CreateProject(name, description)
+		return err
+	})
 	return logProject, errors.Wrap(err, ErrFailedToCreateSLSProject)
 }
 
 // Update updates SLS project's description
-func (c *LogClient) Update(name, description string) (*sdk.LogProject, error) {
-	logProject, err := c.Client.UpdateProject(name, description)
+func (c *LogClient) Update(ctx context.Context, name, description string) (*sdk.LogProject, error) {
+	var logProject *sdk.LogProject
+	err := c.caller.Do(ctx, "UpdateProject", func() (err error) {
+		logProject, err = c.Client.UpdateProject(name, description)
+		return err
+	})
 	return logProject, errors.Wrap(err, ErrFailedToUpdateSLSProject)
 
 }
 
 // Delete deletes SLS project
-func (c *LogClient) Delete(name string) error {
-	err := c.Client.DeleteProject(name)
+func (c *LogClient) Delete(ctx context.Context, name string) error {
+	err := c.caller.Do(ctx, "DeleteProject", func() error {
+		return c.Client.DeleteProject(name)
+	})
 	return errors.Wrap(err, ErrFailedToDeleteSLSProject)
 }
 
+// ListTags lists the tags of SLS project
+func (c *LogClient) ListTags(ctx context.Context, name string) (map[string]string, error) {
+	tags := map[string]string{}
+	nextToken := ""
+	for {
+		var resp []*sdk.ResourceTagResponse
+		err := c.caller.Do(ctx, "ListTagResources", func() (err error) {
+			resp, nextToken, err = c.Client.ListTagResources(name, "project", []string{name}, nil, nextToken)
+			return err
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, ErrFailedToListSLSProjectTags)
+		}
+		for _, t := range resp {
+			tags[t.TagKey] = t.TagValue
+		}
+		if nextToken == "" {
+			return tags, nil
+		}
+	}
+}
+
+// TagProject adds or changes the tags of SLS project
+func (c *LogClient) TagProject(ctx context.Context, name string, tags map[string]string) error {
+	resourceTags := make([]sdk.ResourceTag, 0, len(tags))
+	for k, v := range tags {
+		resourceTags = append(resourceTags, sdk.ResourceTag{Key: k, Value: v})
+	}
+	err := c.caller.Do(ctx, "TagResources", func() error {
+		return c.Client.TagResources(name, sdk.NewProjectTags(name, resourceTags))
+	})
+	return errors.Wrap(err, ErrFailedToTagSLSProject)
+}
+
+// UntagProject removes the tags of SLS project
+func (c *LogClient) UntagProject(ctx context.Context, name string, keys []string) error {
+	err := c.caller.Do(ctx, "UnTagResources", func() error {
+		return c.Client.UnTagResources(name, sdk.NewProjectUnTags(name, keys))
+	})
+	return errors.Wrap(err, ErrFailedToUntagSLSProject)
+}
+
 // GenerateObservation is used to produce v1alpha1.ProjectObservation
 func GenerateObservation(project *sdk.LogProject) v1alpha1.ProjectObservation {
 	return v1alpha1.ProjectObservation{
@@ -135,42 +223,40 @@ func GenerateObservation(project *sdk.LogProject) v1alpha1.ProjectObservation {
 	}
 }
 
-// IsNotFoundError helper function to test for SLS project not found error
-func IsNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var slserr sdk.Error
-	if errors.As(err, &slserr) && slserr.Code == ErrCodeProjectNotExist {
-		return true
-	}
-	return false
-}
-
 // ----------------------SLS LogStore------------------------------ //
 
 // DescribeStore describes SLS store
-func (c *LogClient) DescribeStore(project string, logstore string) (*sdk.LogStore, error) {
-	logStore, err := c.Client.GetLogStore(project, logstore)
+func (c *LogClient) DescribeStore(ctx context.Context, project string, logstore string) (*sdk.LogStore, error) {
+	var logStore *sdk.LogStore
+	err := c.caller.Do(ctx, "GetLogStore", func() (err error) {
+		logStore, err = c.Client.GetLogStore(project, logstore)
+		return err
+	})
 	return logStore, errors.Wrap(err, ErrFailedToGetSLSStore)
 }
 
 // CreateStore creates SLS store
-func (c *LogClient) CreateStore(project string, logstore *sdk.LogStore) error {
-	err := c.Client.CreateLogStoreV2(project, logstore)
+func (c *LogClient) CreateStore(ctx context.Context, project string, logstore *sdk.LogStore) error {
+	err := c.caller.Do(ctx, "CreateLogStoreV2", func() error {
+		return c.Client.CreateLogStoreV2(project, logstore)
+	})
 	return errors.Wrap(err, ErrFailedToCreateSLSStore)
 }
 
 // UpdateStore updates SLS store's description
-func (c *LogClient) UpdateStore(project string, logstore string, ttl int) error {
-	err := c.Client.UpdateLogStore(project, logstore, ttl, 2)
+func (c *LogClient) UpdateStore(ctx context.Context, project string, logstore string, ttl int) error {
+	err := c.caller.Do(ctx, "UpdateLogStore", func() error {
+		return c.Client.UpdateLogStore(project, logstore, ttl, 2)
+	})
 	return errors.Wrap(err, ErrFailedToUpdateSLSStore)
 
 }
 
 // DeleteStore deletes SLS store
-func (c *LogClient) DeleteStore(project string, logstore string) error {
-	err := c.Client.DeleteLogStore(project, logstore)
+func (c *LogClient) DeleteStore(ctx context.Context, project string, logstore string) error {
+	err := c.caller.Do(ctx, "DeleteLogStore", func() error {
+		return c.Client.DeleteLogStore(project, logstore)
+	})
 	return errors.Wrap(err, ErrFailedToDeleteSLSStore)
 }
 
@@ -182,6 +268,13 @@ func GenerateStoreObservation(store *sdk.LogStore) v1alpha1.StoreObservation {
 	}
 }
 
+// LateInitializeStore fills the empty fields in *v1alpha1.StoreParameters with
+// the values seen in sdk.LogStore.
+func LateInitializeStore(in *v1alpha1.StoreParameters, store *sdk.LogStore) {
+	in.AutoSplit = clients.LateInitializeBoolPtr(in.AutoSplit, store.AutoSplit)
+	in.MaxSplitShard = clients.LateInitializeIntPtr(in.MaxSplitShard, store.MaxSplitShard)
+}
+
 // IsStoreUpdateToDate checks whether cr is up to date
 func IsStoreUpdateToDate(cr *v1alpha1.LogStore, store *sdk.LogStore) bool {
 	if (cr.Name == store.Name) && (cr.Spec.ForProvider.TTL == store.TTL) {
@@ -190,26 +283,33 @@ func IsStoreUpdateToDate(cr *v1alpha1.LogStore, store *sdk.LogStore) bool {
 	return false
 }
 
-// IsStoreNotFoundError helper function to test for SLS Store not found error
-func IsStoreNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var slserr *sdk.Error
-	return errors.As(err, &slserr) && slserr.Code == ErrCodeStoreNotExist
-}
-
 // ----------------------SLS Logtail------------------------------ //
 
 // DescribeConfig describes SLS Logtail config
-func (c *LogClient) DescribeConfig(project string, config string) (*sdk.LogConfig, error) {
-	logStore, err := c.Client.GetConfig(project, config)
+func (c *LogClient) DescribeConfig(ctx context.Context, project string, config string) (*sdk.LogConfig, error) {
+	var logStore *sdk.LogConfig
+	err := c.caller.Do(ctx, "GetConfig", func() (err error) {
+		logStore, err = c.Client.GetConfig(project, config)
+		return err
+	})
 	return logStore, errors.Wrap(err, ErrFailedToGetSLSStore)
 }
 
 // CreateConfig creates SLS Logtail config
+func (c *LogClient) CreateConfig(ctx context.Context, name string, t v1alpha1.LogtailParameters) error {
+	config, err := MakeLogConfig(name, t)
+	if err != nil {
+		return err
+	}
+	err = c.caller.Do(ctx, "CreateConfig", func() error {
+		return c.Client.CreateConfig(t.OutputDetail.ProjectName, config)
+	})
+	return errors.Wrap(err, ErrFailedToCreateSLSStore)
+}
+
+// MakeLogConfig generates the LogConfig of SLS Logtail config
 //nolint:gocyclo
-func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) error {
+func MakeLogConfig(name string, t v1alpha1.LogtailParameters) (*sdk.LogConfig, error) {
 	in := t.InputDetail
 	inputDetail := sdk.RegexConfigInputDetail{}
 	switch {
@@ -267,9 +367,9 @@ func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) erro
 			inputDetail.Regex = *in.Regex
 		}
 	case *t.InputType != "file":
-		return fmt.Errorf("InputType %s is not supported", *t.InputType)
+		return nil, fmt.Errorf("InputType %s is not supported", *t.InputType)
 	case *in.LogType == "common_reg_log":
-		return fmt.Errorf("LogType %s is not supported", *in.LogType)
+		return nil, fmt.Errorf("LogType %s is not supported", *in.LogType)
 	}
 
 	outputDetail := sdk.OutputDetail{
@@ -286,20 +386,36 @@ func (c *LogClient) CreateConfig(name string, t v1alpha1.LogtailParameters) erro
 	if t.LogSample != nil {
 		config.LogSample = *t.LogSample
 	}
-	err := c.Client.CreateConfig(t.OutputDetail.ProjectName, config)
-	return errors.Wrap(err, ErrFailedToCreateSLSStore)
+	return config, nil
+}
+
+// ValidateLogtailRegex validates a regular expression of a Logtail config, e.g.
+// its Regex or LogBeginRegex. Logtail supports the Perl syntax that the regular
+// expressions of Go don't, such as lookarounds and backreferences, so only the
+// malformed regular expressions are rejected.
+func ValidateLogtailRegex(regex string) error {
+	_, err := syntax.Parse(regex, syntax.Perl)
+	var serr *syntax.Error
+	if errors.As(err, &serr) && (serr.Code == syntax.ErrInvalidPerlOp || serr.Code == syntax.ErrInvalidEscape) {
+		return nil
+	}
+	return err
 }
 
 // UpdateConfig updates SLS Logtail config's description
-func (c *LogClient) UpdateConfig(project string, config *sdk.LogConfig) error {
-	err := c.Client.UpdateConfig(project, config)
+func (c *LogClient) UpdateConfig(ctx context.Context, project string, config *sdk.LogConfig) error {
+	err := c.caller.Do(ctx, "UpdateConfig", func() error {
+		return c.Client.UpdateConfig(project, config)
+	})
 	return errors.Wrap(err, ErrFailedToUpdateSLSStore)
 
 }
 
 // DeleteConfig deletes SLS Logtail config
-func (c *LogClient) DeleteConfig(project string, config string) error {
-	err := c.Client.DeleteConfig(project, config)
+func (c *LogClient) DeleteConfig(ctx context.Context, project string, config string) error {
+	err := c.caller.Do(ctx, "DeleteConfig", func() error {
+		return c.Client.DeleteConfig(project, config)
+	})
 	return errors.Wrap(err, ErrFailedToDeleteSLSStore)
 }
 
@@ -331,11 +447,3 @@ func IsLogtailUpdateToDate(cr *v1alpha1.Logtail, config *sdk.LogConfig) bool {
 	return true
 }
 
-// IsLogtailNotFoundError helper function to test for SLS Logtail not found error
-func IsLogtailNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
-	var slserr *sdk.Error
-	return errors.As(err, &slserr) && slserr.Code == ErrCodeLogtailNotExist
-}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sls
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// The SLS SDK sends all its requests with http.DefaultTransport, so the
+// transports configured in ProviderConfigs are applied by replacing it with a
+// router which picks the transport by the endpoint of the request. The
+// transport registered last wins if ProviderConfigs with different transport
+// settings share an endpoint.
+var (
+	endpointTransports = &endpointTransport{
+		transports: map[string]http.RoundTripper{},
+		fallback:   http.DefaultTransport,
+	}
+	installEndpointTransport sync.Once
+)
+
+// endpointTransport routes requests to the transports registered for their
+// endpoints, e.g. "cn-hangzhou.log.aliyuncs.com" for the requests sent to
+// "my-project.cn-hangzhou.log.aliyuncs.com".
+type endpointTransport struct {
+	mu         sync.RWMutex
+	transports map[string]http.RoundTripper
+	fallback   http.RoundTripper
+}
+
+func registerTransport(endpoint string, t http.RoundTripper) {
+	installEndpointTransport.Do(func() {
+		http.DefaultTransport = endpointTransports
+	})
+	endpointTransports.register(endpoint, t)
+}
+
+func (t *endpointTransport) register(endpoint string, rt http.RoundTripper) {
+	host := endpoint
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	if u, err := url.Parse(host); err == nil {
+		host = u.Hostname()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.transports[host] = rt
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transportFor(req.URL.Hostname()).RoundTrip(req)
+}
+
+func (t *endpointTransport) transportFor(host string) http.RoundTripper {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if rt, ok := t.transports[host]; ok {
+		return rt
+	}
+	// Requests for a project are sent to the endpoint prefixed with the
+	// project name.
+	if i := strings.Index(host, "."); i >= 0 {
+		if rt, ok := t.transports[host[i+1:]]; ok {
+			return rt
+		}
+	}
+	return t.fallback
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultEndpoint is the public endpoint of STS
+	DefaultEndpoint = "https://sts.aliyuncs.com"
+
+	apiVersion     = "2015-04-01"
+	requestTimeout = 10 * time.Second
+
+	errFmtAssumeRoleWithOIDCFailed = "AssumeRoleWithOIDC failed with code %s: %s (RequestId: %s)"
+	errFailedToParseResponse       = "failed to parse the response of STS"
+)
+
+// OIDCClient defines the STS operations which are authenticated by an OIDC
+// token instead of credentials
+type OIDCClient interface {
+	AssumeRoleWithOIDC(ctx context.Context, req *AssumeRoleWithOIDCRequest) (*Credentials, error)
+}
+
+// AssumeRoleWithOIDCRequest defines the request info to assume a RAM role with
+// an OIDC token
+type AssumeRoleWithOIDCRequest struct {
+	RoleARN         string
+	OIDCProviderARN string
+	OIDCToken       string
+	RoleSessionName string
+	DurationSeconds int
+}
+
+type oidcResponse struct {
+	RequestID   string `json:"RequestId"`
+	Code        string `json:"Code"`
+	Message     string `json:"Message"`
+	Credentials struct {
+		AccessKeyID     string `json:"AccessKeyId"`
+		AccessKeySecret string `json:"AccessKeySecret"`
+		SecurityToken   string `json:"SecurityToken"`
+		Expiration      string `json:"Expiration"`
+	} `json:"Credentials"`
+}
+
+type oidcClient struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewOIDCClient creates new STS client for OIDC federation. The default endpoint
+// is used if endpoint is empty.
+func NewOIDCClient(endpoint string) OIDCClient {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &oidcClient{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// AssumeRoleWithOIDC exchanges an OIDC token for the temporary credentials of a
+// RAM role. The request is anonymous, it's authenticated by the OIDC token.
+func (c *oidcClient) AssumeRoleWithOIDC(ctx context.Context, req *AssumeRoleWithOIDCRequest) (*Credentials, error) {
+	form := url.Values{}
+	form.Set("Action", "AssumeRoleWithOIDC")
+	form.Set("Format", "JSON")
+	form.Set("Version", apiVersion)
+	form.Set("Timestamp", time.Now().UTC().Format(time.RFC3339))
+	form.Set("RoleArn", req.RoleARN)
+	form.Set("OIDCProviderArn", req.OIDCProviderARN)
+	form.Set("OIDCToken", req.OIDCToken)
+	form.Set("RoleSessionName", req.RoleSessionName)
+	if req.DurationSeconds != 0 {
+		form.Set("DurationSeconds", strconv.Itoa(req.DurationSeconds))
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpResp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var resp oidcResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, errors.Wrap(err, errFailedToParseResponse)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf(errFmtAssumeRoleWithOIDCFailed, resp.Code, resp.Message, resp.RequestID)
+	}
+	return generateCredentials(resp.Credentials.AccessKeyID, resp.Credentials.AccessKeySecret,
+		resp.Credentials.SecurityToken, resp.Credentials.Expiration)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sts
+
+import (
+	"context"
+	"time"
+
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
+	alists "github.com/aliyun/alibaba-cloud-sdk-go/services/sts"
+	"github.com/pkg/errors"
+)
+
+const (
+	httpsScheme = "https"
+
+	errFailedToCreateSTSClient = "failed to create STS client"
+	errFailedToParseExpiration = "failed to parse the expiration of the temporary credentials"
+)
+
+// Client defines STS client operations
+type Client interface {
+	AssumeRole(req *AssumeRoleRequest) (*Credentials, error)
+	GetCallerIdentity() (*CallerIdentity, error)
+}
+
+// AssumeRoleRequest defines the request info to assume a RAM role
+type AssumeRoleRequest struct {
+	RoleARN         string
+	RoleSessionName string
+	ExternalID      string
+	DurationSeconds int
+}
+
+// Credentials are the temporary credentials issued by STS
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+// CallerIdentity is the identity of the caller of STS
+type CallerIdentity struct {
+	AccountID string
+	ARN       string
+	UserID    string
+}
+
+type client struct {
+	stsCli *alists.Client
+}
+
+// NewClient creates new STS client
+func NewClient(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (Client, error) {
+	var (
+		stsCli *alists.Client
+		err    error
+	)
+	if securityToken != "" {
+		stsCli, err = alists.NewClientWithStsToken(region, accessKeyID, accessKeySecret, securityToken)
+	} else {
+		stsCli, err = alists.NewClientWithAccessKey(region, accessKeyID, accessKeySecret)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToCreateSTSClient)
+	}
+	return &client{stsCli: stsCli}, nil
+}
+
+func (c *client) AssumeRole(req *AssumeRoleRequest) (*Credentials, error) {
+	request := alists.CreateAssumeRoleRequest()
+	request.Scheme = httpsScheme
+
+	request.RoleArn = req.RoleARN
+	request.RoleSessionName = req.RoleSessionName
+	if req.DurationSeconds != 0 {
+		request.DurationSeconds = requests.NewInteger(req.DurationSeconds)
+	}
+	if req.ExternalID != "" {
+		request.QueryParams["ExternalId"] = req.ExternalID
+	}
+
+	resp, err := c.stsCli.AssumeRole(request)
+	if err != nil {
+		return nil, err
+	}
+	return generateCredentials(resp.Credentials.AccessKeyId, resp.Credentials.AccessKeySecret,
+		resp.Credentials.SecurityToken, resp.Credentials.Expiration)
+}
+
+func (c *client) GetCallerIdentity() (*CallerIdentity, error) {
+	request := alists.CreateGetCallerIdentityRequest()
+	request.Scheme = httpsScheme
+
+	resp, err := c.stsCli.GetCallerIdentity(request)
+	if err != nil {
+		return nil, err
+	}
+	return &CallerIdentity{
+		AccountID: resp.AccountId,
+		ARN:       resp.Arn,
+		UserID:    resp.UserId,
+	}, nil
+}
+
+func generateCredentials(accessKeyID, accessKeySecret, securityToken, expiration string) (*Credentials, error) {
+	expiresAt, err := time.Parse(time.RFC3339, expiration)
+	if err != nil {
+		return nil, errors.Wrap(err, errFailedToParseExpiration)
+	}
+	return &Credentials{
+		AccessKeyID:     accessKeyID,
+		AccessKeySecret: accessKeySecret,
+		SecurityToken:   securityToken,
+		Expiration:      expiresAt,
+	}, nil
+}
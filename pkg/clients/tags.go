@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sort"
+	"strings"
+)
+
+// ManagedTagPrefix is the prefix of the keys of the tags that the provider
+// adds to the external resources, e.g. crossplane-kind.
+const ManagedTagPrefix = "crossplane-"
+
+// managedTag returns true if the tag with key k is one the provider manages,
+// i.e. it is desired or it has the ManagedTagPrefix. The other tags were added
+// by other tools, users or the cloud itself and are left alone.
+func managedTag(desired map[string]string, k string) bool {
+	_, ok := desired[k]
+	return ok || strings.HasPrefix(k, ManagedTagPrefix)
+}
+
+// DiffTags returns the tags of desired which are missing or have another
+// value in observed, and the sorted keys of the tags of observed which are
+// managed by the provider but missing in desired.
+func DiffTags(desired, observed map[string]string) (map[string]string, []string) {
+	add := map[string]string{}
+	for k, v := range desired {
+		if ov, ok := observed[k]; !ok || ov != v {
+			add[k] = v
+		}
+	}
+	var remove []string
+	for k := range observed {
+		if _, ok := desired[k]; !ok && managedTag(desired, k) {
+			remove = append(remove, k)
+		}
+	}
+	sort.Strings(remove)
+	return add, remove
+}
+
+// TagsUpToDate returns true if the observed tags managed by the provider are
+// the desired ones.
+func TagsUpToDate(desired, observed map[string]string) bool {
+	add, remove := DiffTags(desired, observed)
+	return len(add) == 0 && len(remove) == 0
+}
+
+// UpdateTags updates the observed tags of an external resource to the desired
+// ones, removing the tags with untag and adding or changing them with tag.
+func UpdateTags(desired, observed map[string]string, tag func(map[string]string) error, untag func([]string) error) error {
+	add, remove := DiffTags(desired, observed)
+	if len(remove) > 0 {
+		if err := untag(remove); err != nil {
+			return err
+		}
+	}
+	if len(add) > 0 {
+		return tag(add)
+	}
+	return nil
+}
+
+// MergeTags returns the desired tags plus the observed tags which are not
+// managed by the provider, for APIs which replace all the tags of an external
+// resource at once.
+func MergeTags(desired, observed map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range observed {
+		if !managedTag(desired, k) {
+			merged[k] = v
+		}
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	return merged
+}
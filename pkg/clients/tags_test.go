@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffTags(t *testing.T) {
+	type want struct {
+		add    map[string]string
+		remove []string
+	}
+	cases := map[string]struct {
+		desired  map[string]string
+		observed map[string]string
+		want     want
+	}{
+		"UpToDate": {
+			desired:  map[string]string{"team": "db"},
+			observed: map[string]string{"team": "db"},
+			want:     want{add: map[string]string{}},
+		},
+		"AddAndChange": {
+			desired:  map[string]string{"team": "db", "env": "prod"},
+			observed: map[string]string{"team": "web"},
+			want:     want{add: map[string]string{"team": "db", "env": "prod"}},
+		},
+		"Remove": {
+			desired:  nil,
+			observed: map[string]string{"crossplane-kind": "bucket", "crossplane-name": "b"},
+			want:     want{add: map[string]string{}, remove: []string{"crossplane-kind", "crossplane-name"}},
+		},
+		"KeepForeignTags": {
+			desired:  map[string]string{"team": "db"},
+			observed: map[string]string{"team": "db", "crossplane-name": "old", "acs:rm:rgId": "rg-1", "owner": "someone"},
+			want:     want{add: map[string]string{}, remove: []string{"crossplane-name"}},
+		},
+		"OnlyForeignTags": {
+			desired:  map[string]string{"team": "db"},
+			observed: map[string]string{"team": "db", "acs:rm:rgId": "rg-1"},
+			want:     want{add: map[string]string{}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			add, remove := DiffTags(tc.desired, tc.observed)
+			if diff := cmp.Diff(tc.want.add, add); diff != "" {
+				t.Errorf("\nDiffTags(...): -want add, +got add:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.remove, remove); diff != "" {
+				t.Errorf("\nDiffTags(...): -want remove, +got remove:\n%s\n", diff)
+			}
+			if got, want := TagsUpToDate(tc.desired, tc.observed), len(tc.want.add) == 0 && len(tc.want.remove) == 0; got != want {
+				t.Errorf("\nTagsUpToDate(...): want %t, got %t\n", want, got)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	desired := map[string]string{"team": "db", "crossplane-name": "new"}
+	observed := map[string]string{"team": "web", "crossplane-name": "old", "crossplane-kind": "gone", "acs:rm:rgId": "rg-1"}
+	want := map[string]string{"team": "db", "crossplane-name": "new", "acs:rm:rgId": "rg-1"}
+	if diff := cmp.Diff(want, MergeTags(desired, observed)); diff != "" {
+		t.Errorf("\nMergeTags(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	openapi "github.com/alibabacloud-go/darabonba-openapi/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/aliyun/alibaba-cloud-sdk-go/sdk"
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	// ErrInvalidCABundle is the error of the CA bundle containing no PEM encoded certificate
+	ErrInvalidCABundle = "CA bundle contains no valid PEM encoded certificate"
+	// ErrTLSSettingsNotSupported is the error of configuring the TLS settings which the SDK can't apply
+	ErrTLSSettingsNotSupported = "the SDK doesn't support a custom CA bundle or minimum TLS version"
+
+	defaultConnectTimeout = 30 * time.Second
+	defaultKeepAlive      = 30 * time.Second
+)
+
+// TransportConfig is the HTTP transport configuration of the SDK clients,
+// resolved from ProviderConfig. A nil TransportConfig leaves the SDK defaults
+// untouched.
+type TransportConfig struct {
+	// ProxyURL is the URL of the proxy for both HTTP and HTTPS requests. The
+	// proxy environment variables are used if it's empty.
+	ProxyURL string
+	// NoProxy is the comma-separated list of hosts excluded from the proxy,
+	// in the format of the NO_PROXY environment variable.
+	NoProxy string
+	// CABundle is the PEM encoded CA certificates trusted in addition to the
+	// system ones.
+	CABundle []byte
+	// ConnectTimeout limits the time to establish a connection.
+	ConnectTimeout time.Duration
+	// ReadTimeout limits the time to wait for a response.
+	ReadTimeout time.Duration
+	// TLSMinVersion is the minimum TLS version, e.g. tls.VersionTLS12. Zero
+	// leaves it to Go.
+	TLSMinVersion uint16
+}
+
+// Fingerprint identifies the configuration, so that the clients are
+// re-established when it changes.
+func (c *TransportConfig) Fingerprint() string {
+	if c == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%q/%q/%x/%d/%d/%d",
+		c.ProxyURL, c.NoProxy, c.CABundle, c.ConnectTimeout, c.ReadTimeout, c.TLSMinVersion)))
+	return hex.EncodeToString(sum[:])
+}
+
+// ReadTimeoutOr returns the configured read timeout, or d if it's not configured.
+func (c *TransportConfig) ReadTimeoutOr(d time.Duration) time.Duration {
+	if c == nil || c.ReadTimeout == 0 {
+		return d
+	}
+	return c.ReadTimeout
+}
+
+// Proxy returns the function that selects the proxy for a request.
+func (c *TransportConfig) Proxy() func(*http.Request) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+	if c.ProxyURL != "" {
+		cfg.HTTPProxy = c.ProxyURL
+		cfg.HTTPSProxy = c.ProxyURL
+	}
+	if c.NoProxy != "" {
+		cfg.NoProxy = c.NoProxy
+	}
+	proxy := cfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return proxy(req.URL)
+	}
+}
+
+// NewTransport creates an HTTP transport which applies the configuration.
+func (c *TransportConfig) NewTransport() (*http.Transport, error) {
+	tlsConfig := &tls.Config{MinVersion: c.TLSMinVersion} //nolint:gosec
+	if len(c.CABundle) != 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(c.CABundle) {
+			return nil, errors.New(ErrInvalidCABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	connectTimeout := c.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+	dialer := &net.Dialer{Timeout: connectTimeout, KeepAlive: defaultKeepAlive}
+	return &http.Transport{
+		Proxy:                 c.Proxy(),
+		DialContext:           dialer.DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: c.ReadTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}, nil
+}
+
+// sdkTransport hides the *http.Transport from alibaba-cloud-sdk-go, which
+// overwrites the TLS, proxy and dial settings of an *http.Transport on every
+// request.
+type sdkTransport struct {
+	transport *http.Transport
+}
+
+func (t sdkTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport.RoundTrip(req)
+}
+
+// ConfigureSDKClient applies the configuration to a client of
+// alibaba-cloud-sdk-go, e.g. the RDS and Redis clients.
+func (c *TransportConfig) ConfigureSDKClient(cli *sdk.Client) error {
+	if c == nil {
+		return nil
+	}
+	t, err := c.NewTransport()
+	if err != nil {
+		return err
+	}
+	cli.SetTransport(sdkTransport{transport: t})
+	if c.ConnectTimeout != 0 {
+		cli.SetConnectTimeout(c.ConnectTimeout)
+	}
+	if c.ReadTimeout != 0 {
+		cli.SetReadTimeout(c.ReadTimeout)
+	}
+	return nil
+}
+
+// ConfigureOpenAPIConfig applies the configuration to the config of a client
+// of the Darabonba SDKs, e.g. the NAS and SLB clients. These SDKs build their
+// own transports for every request, so a CA bundle or minimum TLS version is
+// refused rather than silently ignored.
+func (c *TransportConfig) ConfigureOpenAPIConfig(config *openapi.Config) error {
+	if c == nil {
+		return nil
+	}
+	if len(c.CABundle) != 0 || c.TLSMinVersion != 0 {
+		return errors.New(ErrTLSSettingsNotSupported)
+	}
+	if c.ProxyURL != "" {
+		config.HttpProxy = tea.String(c.ProxyURL)
+		config.HttpsProxy = tea.String(c.ProxyURL)
+	}
+	if c.NoProxy != "" {
+		config.NoProxy = tea.String(c.NoProxy)
+	}
+	if c.ConnectTimeout != 0 {
+		config.ConnectTimeout = tea.Int(int(c.ConnectTimeout.Milliseconds()))
+	}
+	if c.ReadTimeout != 0 {
+		config.ReadTimeout = tea.Int(int(c.ReadTimeout.Milliseconds()))
+	}
+	return nil
+}
@@ -17,9 +17,16 @@ limitations under the License.
 package controller
 
 import (
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
+	databasev1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	nasv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
+	ossv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	redisv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
+	slbv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
+	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/config"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/database"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/nas"
@@ -27,28 +34,40 @@ import (
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/redis"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/slb"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/controller/sls"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
 
-// Setup creates Alibaba controllers with the supplied logger and adds them to the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger) error{
-		config.Setup,
-		database.SetupRDSInstance,
-		redis.SetupRedisInstance,
-		sls.SetupProject,
-		sls.SetupStore,
-		sls.SetupLogtail,
-		sls.SetupIndex,
-		sls.SetupMachineGroup,
-		sls.SetupMachineGroupBinding,
-		oss.SetupBucket,
-		nas.SetupNASFileSystem,
-		nas.SetupNASMountTarget,
-		slb.SetupCLB,
+const errRegisterMetrics = "cannot register managed resource metrics"
+
+// Setup creates Alibaba controllers with the supplied options and adds them to the supplied manager.
+// Only the controllers of the API groups enabled by the options are created.
+func Setup(mgr ctrl.Manager, o util.ControllerOptions) error {
+	if err := config.Setup(mgr, o); err != nil {
+		return err
+	}
+	for _, c := range []struct {
+		group string
+		setup func(ctrl.Manager, util.ControllerOptions) error
+	}{
+		{databasev1alpha1.Group, database.SetupRDSInstance},
+		{redisv1alpha1.Group, redis.SetupRedisInstance},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupProject},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupStore},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupLogtail},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupIndex},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupMachineGroup},
+		{slsv1alpha1.GroupVersion.Group, sls.SetupMachineGroupBinding},
+		{ossv1alpha1.GroupVersion.Group, oss.SetupBucket},
+		{nasv1alpha1.GroupVersion.Group, nas.SetupNASFileSystem},
+		{nasv1alpha1.GroupVersion.Group, nas.SetupNASMountTarget},
+		{slbv1alpha1.GroupVersion.Group, slb.SetupCLB},
 	} {
-		if err := setup(mgr, l); err != nil {
+		if !o.Groups.Enabled(c.group) {
+			continue
+		}
+		if err := c.setup(mgr, o); err != nil {
 			return err
 		}
 	}
-	return nil
+	return errors.Wrap(metrics.Registry.Register(newManagedResourceCollector(mgr.GetClient(), mgr.GetScheme(), o.Logger)), errRegisterMetrics)
 }
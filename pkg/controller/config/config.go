@@ -17,20 +17,48 @@ limitations under the License.
 package config
 
 import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/providerconfig"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
+)
+
+const (
+	errGetProviderConfig = "cannot get ProviderConfig"
+	errUpdateStatus      = "cannot update ProviderConfig status"
+	errCreateSTSClient   = "cannot create STS client"
+	errGetCallerIdentity = "cannot get the caller identity of the credentials"
+	errValidate          = "cannot validate the credentials"
+
+	reasonInvalidCredentials event.Reason = "InvalidCredentials"
+
+	// validationInterval is the interval to validate healthy credentials, and
+	// retryInterval is the interval to validate unhealthy ones.
+	validationInterval = 10 * time.Minute
+	retryInterval      = 30 * time.Second
 )
 
 // Setup adds a controller that reconciles ProviderConfigs by accounting for
-// their current usage.
-func Setup(mgr ctrl.Manager, l logging.Logger) error {
+// their current usage and validating their credentials.
+func Setup(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := providerconfig.ControllerName(v1beta1.ProviderConfigGroupKind)
 
 	of := resource.ProviderConfigKinds{
@@ -38,11 +66,148 @@ func Setup(mgr ctrl.Manager, l logging.Logger) error {
 		UsageList: v1beta1.ProviderConfigUsageListGroupVersionKind,
 	}
 
+	log := o.Logger.WithValues("controller", name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1beta1.Group)).
 		For(&v1beta1.ProviderConfig{}).
 		Watches(&source.Kind{Type: &v1beta1.ProviderConfigUsage{}}, &resource.EnqueueRequestForProviderConfig{}).
-		Complete(providerconfig.NewReconciler(mgr, of,
-			providerconfig.WithLogger(l.WithValues("controller", name)),
-			providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+		Complete(&Reconciler{
+			client: mgr.GetClient(),
+			usage: providerconfig.NewReconciler(mgr, of,
+				providerconfig.WithLogger(log),
+				providerconfig.WithRecorder(recorder)),
+			newSTSClient: util.NewSTSClient,
+			log:          log,
+			record:       recorder,
+		})
+}
+
+// A Reconciler reconciles ProviderConfigs. It accounts for their usage by
+// delegating to the generic ProviderConfig reconciler, then validates their
+// credentials by calling STS GetCallerIdentity and publishes the result in
+// their status.
+type Reconciler struct {
+	client       client.Client
+	usage        reconcile.Reconciler
+	newSTSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error)
+	log          logging.Logger
+	record       event.Recorder
+}
+
+// Reconcile a ProviderConfig.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	if result, err := r.usage.Reconcile(ctx, req); err != nil || result.Requeue || result.RequeueAfter > 0 {
+		return result, err
+	}
+
+	pc := &v1beta1.ProviderConfig{}
+	if err := r.client.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+	if meta.WasDeleted(pc) {
+		return reconcile.Result{}, nil
+	}
+	// The usages of the ProviderConfig trigger reconciles too, STS isn't
+	// called until the next validation is due.
+	if wait := untilNextValidation(pc); wait > 0 {
+		return reconcile.Result{RequeueAfter: wait}, nil
+	}
+
+	// The status is patched rather than updated because the usage reconciler
+	// has just updated it, and the cached ProviderConfig may not be fresh.
+	orig := pc.DeepCopy()
+	requeueAfter := validationInterval
+	identity, cred, err := r.validate(ctx, pc)
+	if err != nil && isTransient(err) {
+		// Throttling or a network error doesn't make the credentials invalid,
+		// the validation is retried with backoff.
+		r.log.Debug("Cannot validate credentials", "request", req, "error", err)
+		return reconcile.Result{}, errors.Wrap(err, errValidate)
+	}
+	now := metav1.Now()
+	pc.Status.ObservedGeneration = pc.Generation
+	pc.Status.LastValidationTime = &now
+	if err != nil {
+		r.log.Debug("Credentials are invalid", "request", req, "error", err)
+		r.record.Event(pc, event.Warning(reasonInvalidCredentials, err))
+		pc.Status.SetConditions(xpv1.Unavailable().WithMessage(err.Error()))
+		pc.Status.AccountID = ""
+		pc.Status.PrincipalARN = ""
+		pc.Status.CredentialsExpiration = nil
+		requeueAfter = retryInterval
+	} else {
+		pc.Status.SetConditions(xpv1.Available())
+		pc.Status.AccountID = identity.AccountID
+		pc.Status.PrincipalARN = identity.ARN
+		pc.Status.CredentialsExpiration = nil
+		if !cred.Expiration.IsZero() {
+			expiration := metav1.NewTime(cred.Expiration)
+			pc.Status.CredentialsExpiration = &expiration
+			// Validate the refreshed credentials shortly after they expire.
+			if d := time.Until(cred.Expiration) + retryInterval; d < requeueAfter {
+				requeueAfter = d
+			}
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: requeueAfter}, errors.Wrap(r.client.Status().Patch(ctx, pc, client.MergeFrom(orig)), errUpdateStatus)
+}
+
+// untilNextValidation returns how long it is until the credentials of pc are
+// due to be validated again, or zero if they are due now, e.g. because its
+// spec changed.
+func untilNextValidation(pc *v1beta1.ProviderConfig) time.Duration {
+	if pc.Status.ObservedGeneration != pc.Generation || pc.Status.LastValidationTime == nil {
+		return 0
+	}
+	interval := validationInterval
+	if pc.Status.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue {
+		interval = retryInterval
+	}
+	due := pc.Status.LastValidationTime.Add(interval)
+	if e := pc.Status.CredentialsExpiration; e != nil && e.Add(retryInterval).Before(due) {
+		due = e.Add(retryInterval)
+	}
+	if wait := time.Until(due); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// isTransient checks whether validating the credentials failed because of an
+// error that might not recur, e.g. throttling or a network error, rather than
+// because they are invalid. The errors of resolving the credentials which
+// aren't returned by an API, e.g. a missing Secret, make them invalid.
+func isTransient(err error) bool {
+	switch errorclass.Classify(err) {
+	case errorclass.Forbidden:
+		return false
+	case errorclass.Unknown:
+		_, api := errorclass.GetDetails(err)
+		return api
+	}
+	return true
+}
+
+func (r *Reconciler) validate(ctx context.Context, pc *v1beta1.ProviderConfig) (*sts.CallerIdentity, *util.AlibabaCredentials, error) {
+	cred, err := util.GetCredentials(ctx, r.client, pc.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	region := pc.Spec.Region
+	if region == "" {
+		region = util.DefaultSTSRegion
+	}
+	stsClient, err := r.newSTSClient(ctx, cred.AccessKeyID, cred.AccessKeySecret, cred.SecurityToken, region)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errCreateSTSClient)
+	}
+	identity, err := stsClient.GetCallerIdentity()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errGetCallerIdentity)
+	}
+	return identity, cred, nil
 }
@@ -0,0 +1,255 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
+)
+
+type fakeSTSClient struct {
+	identity *sts.CallerIdentity
+	err      error
+}
+
+func (c *fakeSTSClient) AssumeRole(req *sts.AssumeRoleRequest) (*sts.Credentials, error) {
+	return nil, nil
+}
+
+func (c *fakeSTSClient) GetCallerIdentity() (*sts.CallerIdentity, error) {
+	return c.identity, c.err
+}
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	identity := &sts.CallerIdentity{AccountID: "123456", ARN: "acs:ram::123456:user/crossplane"}
+
+	validated := metav1.Now()
+	throttled := sdkerrors.NewServerError(400, `{"Code": "Throttling.User", "RequestId": "5E4F1A2B"}`, "")
+	forbidden := sdkerrors.NewServerError(400, `{"Code": "InvalidAccessKeyId.NotFound", "RequestId": "5E4F1A2C"}`, "")
+
+	type want struct {
+		stsRegion string
+		status    *v1beta1.ProviderConfigStatus
+		result    reconcile.Result
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		region    string
+		status    v1beta1.ProviderConfigStatus
+		stsClient *fakeSTSClient
+		want      want
+	}{
+		"ValidCredentials": {
+			reason:    "The identity of valid credentials should be published",
+			region:    "cn-beijing",
+			stsClient: &fakeSTSClient{identity: identity},
+			want: want{
+				stsRegion: "cn-beijing",
+				status: &v1beta1.ProviderConfigStatus{
+					ProviderConfigStatus: xpv1.ProviderConfigStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+					},
+					AccountID:          identity.AccountID,
+					PrincipalARN:       identity.ARN,
+					ObservedGeneration: 2,
+				},
+				result: reconcile.Result{RequeueAfter: validationInterval},
+			},
+		},
+		"DefaultRegion": {
+			reason:    "STS in the default region should validate the credentials if ProviderConfig doesn't specify a region",
+			stsClient: &fakeSTSClient{identity: identity},
+			want: want{
+				stsRegion: util.DefaultSTSRegion,
+				status: &v1beta1.ProviderConfigStatus{
+					ProviderConfigStatus: xpv1.ProviderConfigStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+					},
+					AccountID:          identity.AccountID,
+					PrincipalARN:       identity.ARN,
+					ObservedGeneration: 2,
+				},
+				result: reconcile.Result{RequeueAfter: validationInterval},
+			},
+		},
+		"InvalidCredentials": {
+			reason:    "Credentials failing validation should make the ProviderConfig unavailable",
+			region:    "cn-beijing",
+			stsClient: &fakeSTSClient{err: errBoom},
+			want: want{
+				stsRegion: "cn-beijing",
+				status: &v1beta1.ProviderConfigStatus{
+					ProviderConfigStatus: xpv1.ProviderConfigStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{
+							xpv1.Unavailable().WithMessage(errors.Wrap(errBoom, errGetCallerIdentity).Error()),
+						}},
+					},
+					ObservedGeneration: 2,
+				},
+				result: reconcile.Result{RequeueAfter: retryInterval},
+			},
+		},
+		"Forbidden": {
+			reason:    "Credentials rejected by STS should make the ProviderConfig unavailable",
+			region:    "cn-beijing",
+			stsClient: &fakeSTSClient{err: forbidden},
+			want: want{
+				stsRegion: "cn-beijing",
+				status: &v1beta1.ProviderConfigStatus{
+					ProviderConfigStatus: xpv1.ProviderConfigStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{
+							xpv1.Unavailable().WithMessage(errors.Wrap(forbidden, errGetCallerIdentity).Error()),
+						}},
+					},
+					ObservedGeneration: 2,
+				},
+				result: reconcile.Result{RequeueAfter: retryInterval},
+			},
+		},
+		"Throttled": {
+			reason:    "Throttling shouldn't make the ProviderConfig unavailable, the validation should be retried",
+			region:    "cn-beijing",
+			stsClient: &fakeSTSClient{err: throttled},
+			want: want{
+				stsRegion: "cn-beijing",
+				err:       errors.Wrap(errors.Wrap(throttled, errGetCallerIdentity), errValidate),
+			},
+		},
+		"RecentlyValidated": {
+			reason: "STS shouldn't be called again until the next validation is due",
+			region: "cn-beijing",
+			status: v1beta1.ProviderConfigStatus{
+				ProviderConfigStatus: xpv1.ProviderConfigStatus{
+					ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+				},
+				ObservedGeneration: 2,
+				LastValidationTime: &validated,
+			},
+			stsClient: &fakeSTSClient{err: errBoom},
+		},
+		"SpecChanged": {
+			reason: "The credentials should be validated again when the spec changes",
+			region: "cn-beijing",
+			status: v1beta1.ProviderConfigStatus{
+				ProviderConfigStatus: xpv1.ProviderConfigStatus{
+					ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+				},
+				ObservedGeneration: 1,
+				LastValidationTime: &validated,
+			},
+			stsClient: &fakeSTSClient{identity: identity},
+			want: want{
+				stsRegion: "cn-beijing",
+				status: &v1beta1.ProviderConfigStatus{
+					ProviderConfigStatus: xpv1.ProviderConfigStatus{
+						ConditionedStatus: xpv1.ConditionedStatus{Conditions: []xpv1.Condition{xpv1.Available()}},
+					},
+					AccountID:          identity.AccountID,
+					PrincipalARN:       identity.ARN,
+					ObservedGeneration: 2,
+				},
+				result: reconcile.Result{RequeueAfter: validationInterval},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var (
+				got       *v1beta1.ProviderConfigStatus
+				stsRegion string
+			)
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					switch o := obj.(type) {
+					case *v1beta1.ProviderConfig:
+						o.SetName("default")
+						o.SetGeneration(2)
+						o.Spec.Region = tc.region
+						tc.status.DeepCopyInto(&o.Status)
+						o.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+						o.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{Key: "credentials"}
+					case *corev1.Secret:
+						o.Data = map[string][]byte{"credentials": []byte("accessKeyId: ak\naccessKeySecret: sk\n")}
+					}
+					return nil
+				}),
+				MockStatusPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+					got = obj.(*v1beta1.ProviderConfig).Status.DeepCopy()
+					return nil
+				},
+			}
+			r := &Reconciler{
+				client: kube,
+				usage: reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+					return reconcile.Result{}, nil
+				}),
+				newSTSClient: func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error) {
+					stsRegion = region
+					return tc.stsClient, nil
+				},
+				log:    logging.NewNopLogger(),
+				record: event.NewNopRecorder(),
+			}
+
+			result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "default"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.result.RequeueAfter == 0 && tc.want.err == nil {
+				// The next validation is due in at most the validation interval.
+				if result.RequeueAfter <= 0 || result.RequeueAfter > validationInterval {
+					t.Errorf("\n%s\nr.Reconcile(...): want requeue after at most %s, got %s", tc.reason, validationInterval, result.RequeueAfter)
+				}
+			} else if diff := cmp.Diff(tc.want.result, result); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s", tc.reason, diff)
+			}
+			if got != nil {
+				if got.LastValidationTime == nil {
+					t.Errorf("\n%s\nr.Reconcile(...): want the time of the validation", tc.reason)
+				}
+				got.LastValidationTime = nil
+			}
+			if stsRegion != tc.want.stsRegion {
+				t.Errorf("\n%s\nr.Reconcile(...): want STS in region %q, got %q", tc.reason, tc.want.stsRegion, stsRegion)
+			}
+			if diff := cmp.Diff(tc.want.status, got, test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want status, +got status:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,20 +19,22 @@ package database
 import (
 	"context"
 
-	sdkerror "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/password"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/rds"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -45,32 +47,42 @@ const (
 	errCreateAccountFailed      = "cannot create RDS database account"
 	errDeleteFailed             = "cannot delete RDS instance"
 	errDescribeFailed           = "cannot describe RDS instance"
+	errAdoptFailed              = "cannot adopt RDS instance"
+	errRecoverFailed            = "cannot recover the created RDS instance"
+	errListTagsFailed           = "cannot list the tags of RDS instance"
+	errUpdateTagsFailed         = "cannot update the tags of RDS instance"
 	errFmtUnsupportedCredSource = "no extraction handler registered for source: %s"
 	errGetCredentials           = "cannot get credentials"
 )
 
 // SetupRDSInstance adds a controller that reconciles RDSInstances.
-func SetupRDSInstance(mgr ctrl.Manager, l logging.Logger) error {
+func SetupRDSInstance(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.RDSInstanceGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.RDSInstanceGroupVersionKind.Group)).
 		For(&v1alpha1.RDSInstance{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.RDSInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&connector{
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&connector{
 				client:       mgr.GetClient(),
 				usage:        resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				newRDSClient: rds.NewClient,
-			}),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			}), o.DryRun, recorder)))),
+			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), managed.NewNameAsExternalName(mgr.GetClient()), util.NewTagger(mgr.GetClient(), v1alpha1.RDSInstanceGroupKind)),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder)))))
 }
 
 type connector struct {
 	client       client.Client
 	usage        resource.Tracker
-	newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error)
+	newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (rds.Client, error)
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
@@ -86,13 +98,19 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	rdsClient, err := c.newRDSClient(ctx, clientEstablishmentInfo.AccessKeyID, clientEstablishmentInfo.AccessKeySecret,
-		clientEstablishmentInfo.SecurityToken, clientEstablishmentInfo.Region)
-	return &external{client: rdsClient}, errors.Wrap(err, errCreateRDSClient)
+	rdsClient, err := util.GetOrCreateClient(clientEstablishmentInfo, util.ServiceRDS, func() (interface{}, error) {
+		return c.newRDSClient(ctx, clientEstablishmentInfo.AccessKeyID, clientEstablishmentInfo.AccessKeySecret,
+			clientEstablishmentInfo.SecurityToken, clientEstablishmentInfo.Region, clientEstablishmentInfo.Endpoint, clientEstablishmentInfo.Transport, clientEstablishmentInfo.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateRDSClient)
+	}
+	return &external{client: rdsClient.(rds.Client), kube: c.client}, nil
 }
 
 type external struct {
 	client rds.Client
+	kube   client.Client
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -101,22 +119,59 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotRDSInstance)
 	}
 
-	if cr.Status.AtProvider.DBInstanceID == "" {
+	id := cr.Status.AtProvider.DBInstanceID
+	if id == "" && util.IsObserveOnly(cr) {
+		// An existing instance is observed by its ID.
+		id = meta.GetExternalName(cr)
+	}
+	if id == "" && util.IsCreatePending(cr) {
+		// The instance may have been created without its ID being recorded.
+		recovered, err := util.RecoverCreated(ctx, cr, meta.GetExternalName(cr), e.findDBInstances)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errRecoverFailed)
+		}
+		id = recovered
+	}
+	if id == "" && !util.IsObserveOnly(cr) {
+		// The ID of an existing instance is lost if the status isn't
+		// restored, e.g. by a backup, in which case the instance with the
+		// crossplane tags of the resource is adopted.
+		adopted, err := util.Adopt(ctx, cr, e.findDBInstances)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptFailed)
+		}
+		id = adopted
+	}
+	if id == "" {
 		return managed.ExternalObservation{}, nil
 	}
 
-	instance, err := e.client.DescribeDBInstance(cr.Status.AtProvider.DBInstanceID)
+	instance, err := e.client.DescribeDBInstance(ctx, id)
 	if err != nil {
-		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(rds.IsErrorNotFound, err), errDescribeFailed)
+		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(errorclass.IsNotFound, err), errDescribeFailed)
 	}
 
 	cr.Status.AtProvider = rds.GenerateObservation(instance)
+	if err := util.UnsetCreatePending(ctx, e.kube, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	rds.LateInitialize(&cr.Spec.ForProvider, instance)
+
+	tags, err := e.client.ListTags(ctx, instance.ID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListTagsFailed)
+	}
 
 	var pw string
 	switch cr.Status.AtProvider.DBInstanceStatus {
 	case v1alpha1.RDSInstanceStateRunning:
 		cr.Status.SetConditions(xpv1.Available())
-		pw, err = e.createAccountIfNeeded(cr)
+		if util.IsObserveOnly(cr) || util.IsDryRun(ctx) {
+			break
+		}
+		pw, err = e.createAccountIfNeeded(ctx, cr)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errCreateAccountFailed)
 		}
@@ -124,14 +179,36 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.Status.SetConditions(xpv1.Unavailable())
 	}
 
+	// Only the tags are updated, the drift of the parameters is reported
+	// when the instance is only observed.
+	upToDate := clients.TagsUpToDate(cr.Spec.ForProvider.Tags, tags)
+	if util.IsObserveOnly(cr) {
+		upToDate = upToDate && rds.IsUpToDate(cr.Spec.ForProvider, instance)
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
-		ConnectionDetails: getConnectionDetails(pw, cr, instance),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       getConnectionDetails(pw, cr, instance),
 	}, nil
 }
 
-func (e *external) createAccountIfNeeded(cr *v1alpha1.RDSInstance) (string, error) {
+// findDBInstances finds the IDs of the instances named name and tagged with
+// tags.
+func (e *external) findDBInstances(ctx context.Context, name string, tags map[string]string) ([]string, error) {
+	instances, err := e.client.FindDBInstances(ctx, name, tags)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(instances))
+	for _, in := range instances {
+		ids = append(ids, in.ID)
+	}
+	return ids, nil
+}
+
+func (e *external) createAccountIfNeeded(ctx context.Context, cr *v1alpha1.RDSInstance) (string, error) {
 	if cr.Status.AtProvider.AccountReady {
 		return "", nil
 	}
@@ -139,20 +216,13 @@ func (e *external) createAccountIfNeeded(cr *v1alpha1.RDSInstance) (string, erro
 	if err != nil {
 		return "", err
 	}
-	err = e.client.CreateAccount(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
+	err = e.client.CreateAccount(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
-		var sdkerr sdkerror.Error
-
-		if !errors.As(err, &sdkerr) {
-			return "", err
-		}
-
-		if sdkerr.ErrorCode() == "InvalidAccountName.Duplicate" {
+		if errorclass.IsAlreadyExists(err) {
 			cr.Status.AtProvider.AccountReady = true
 			return "", nil
 		}
-
 		return "", err
 	}
 	cr.Status.AtProvider.AccountReady = true
@@ -170,9 +240,17 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	req := rds.MakeCreateDBInstanceRequest(meta.GetExternalName(cr), &cr.Spec.ForProvider)
-	instance, err := e.client.CreateDBInstance(req)
+	// The request retried after a lost response returns the same instance.
+	req.ClientToken = util.ClientToken(cr)
+	if util.Plan(ctx, "CreateDBInstance", req) {
+		return managed.ExternalCreation{}, nil
+	}
+	if err := util.SetCreatePending(ctx, e.kube, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	instance, err := e.client.CreateDBInstance(ctx, req)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+		return managed.ExternalCreation{}, errors.Wrap(util.CreateFailed(ctx, e.kube, cr, err), errCreateFailed)
 	}
 
 	// The crossplane runtime will send status update back to apiserver.
@@ -183,7 +261,30 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 }
 
 func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+	cr, ok := mg.(*v1alpha1.RDSInstance)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotRDSInstance)
+	}
+
+	id := cr.Status.AtProvider.DBInstanceID
+	tags, err := e.client.ListTags(ctx, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errListTagsFailed)
+	}
+	err = clients.UpdateTags(cr.Spec.ForProvider.Tags, tags,
+		func(add map[string]string) error {
+			if util.Plan(ctx, "TagResources", add) {
+				return nil
+			}
+			return e.client.TagResources(ctx, id, add)
+		},
+		func(remove []string) error {
+			if util.Plan(ctx, "UntagResources", remove) {
+				return nil
+			}
+			return e.client.UntagResources(ctx, id, remove)
+		})
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateTagsFailed)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -195,8 +296,12 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return nil
 	}
 
-	err := e.client.DeleteDBInstance(cr.Status.AtProvider.DBInstanceID)
-	return errors.Wrap(resource.Ignore(rds.IsErrorNotFound, err), errDeleteFailed)
+	id := cr.Status.AtProvider.DBInstanceID
+	if util.Plan(ctx, "DeleteDBInstance", id) {
+		return nil
+	}
+	err := e.client.DeleteDBInstance(ctx, id)
+	return errors.Wrap(resource.Ignore(errorclass.IsNotFound, err), errDeleteFailed)
 }
 
 func getConnectionDetails(password string, cr *v1alpha1.RDSInstance, instance *rds.DBInstance) managed.ConnectionDetails {
@@ -17,6 +17,8 @@ import (
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/rds"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -33,7 +35,7 @@ func TestConnector(t *testing.T) {
 	type fields struct {
 		client       client.Client
 		usage        resource.Tracker
-		newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error)
+		newRDSClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (rds.Client, error)
 	}
 
 	type args struct {
@@ -221,7 +223,7 @@ func TestConnector(t *testing.T) {
 					}),
 				},
 				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-				newRDSClient: func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (rds.Client, error) {
+				newRDSClient: func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (rds.Client, error) {
 					return nil, errBoom
 				},
 			},
@@ -255,6 +257,7 @@ func TestExternalClientObserve(t *testing.T) {
 		Spec: v1alpha1.RDSInstanceSpec{
 			ForProvider: v1alpha1.RDSInstanceParameters{
 				MasterUsername: testName,
+				Tags:           map[string]string{"team": "db", "owner": "alice"},
 			},
 		},
 		Status: v1alpha1.RDSInstanceStatus{
@@ -276,12 +279,165 @@ func TestExternalClientObserve(t *testing.T) {
 	if string(ob.ConnectionDetails[xpv1.ResourceCredentialsSecretUserKey]) != testName {
 		t.Error("ConnectionDetails should include username=test")
 	}
+	if !ob.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true when the tags are up to date")
+	}
+
+	obj.Spec.ForProvider.Tags = map[string]string{"team": "web"}
+	ob, err = e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ob.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false when the tags differ")
+	}
 }
 
-func TestExternalClientCreate(t *testing.T) {
+func TestExternalClientObserveOnly(t *testing.T) {
 	e := &external{client: &fakeRDSClient{}}
+	obj := &v1alpha1.RDSInstance{}
+	crossplanemeta.SetExternalName(obj, testName)
+	crossplanemeta.AddAnnotations(obj, map[string]string{util.AnnotationManagementPolicy: util.ManagementPolicyObserveOnly})
+
+	ob, err := e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ob.ResourceExists {
+		t.Error("The RDS instance of the external name should exist")
+	}
+	if obj.Status.AtProvider.DBInstanceID != testName {
+		t.Errorf("DBInstanceID (%v) should be %v", obj.Status.AtProvider.DBInstanceID, testName)
+	}
+	if obj.Status.AtProvider.AccountReady {
+		t.Error("AccountReady should be false, the account isn't created when observing only")
+	}
+	if !ob.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be true when the parameters aren't set")
+	}
+
+	obj.Spec.ForProvider.DBInstanceStorageInGB = 40
+	ob, err = e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ob.ResourceUpToDate {
+		t.Error("ResourceUpToDate should be false when the storage of the observed instance differs")
+	}
+}
+
+func TestExternalClientObserveAdopt(t *testing.T) {
+	tags := map[string]string{util.TagKind: "rdsinstance.database.alibaba.crossplane.io", util.TagName: testName, util.TagProviderConfig: "default"}
+	e := &external{client: &fakeRDSClient{found: []*rds.DBInstance{{ID: testName}}}}
+	obj := &v1alpha1.RDSInstance{Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{MasterUsername: testName, Tags: tags}}}
+	crossplanemeta.SetExternalName(obj, testName)
+
+	ob, err := e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ob.ResourceExists {
+		t.Error("The RDS instance with the crossplane tags should be adopted")
+	}
+	if obj.Status.AtProvider.DBInstanceID != testName {
+		t.Errorf("DBInstanceID (%v) should be %v", obj.Status.AtProvider.DBInstanceID, testName)
+	}
+
+	obj = &v1alpha1.RDSInstance{Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{MasterUsername: testName}}}
+	crossplanemeta.SetExternalName(obj, testName)
+
+	ob, err = e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ob.ResourceExists {
+		t.Error("The RDS instance described by the external name alone should not be adopted")
+	}
+
+	e = &external{client: &fakeRDSClient{found: []*rds.DBInstance{{ID: "rm-1"}, {ID: "rm-2"}}}}
+	obj = &v1alpha1.RDSInstance{Spec: v1alpha1.RDSInstanceSpec{ForProvider: v1alpha1.RDSInstanceParameters{Tags: tags}}}
+	crossplanemeta.SetExternalName(obj, testName)
+
+	if _, err := e.Observe(context.Background(), obj); err == nil {
+		t.Error("Observe should fail when several RDS instances have the crossplane tags")
+	}
+	if obj.Status.AtProvider.DBInstanceID != "" {
+		t.Errorf("DBInstanceID (%v) should be empty, no RDS instance should be adopted", obj.Status.AtProvider.DBInstanceID)
+	}
+	if r := obj.GetCondition(xpv1.TypeReady).Reason; r != util.ReasonAmbiguousExternalResource {
+		t.Errorf("The reason (%v) of the Ready condition should be %v", r, util.ReasonAmbiguousExternalResource)
+	}
+}
+
+func TestExternalClientObserveCreatePending(t *testing.T) {
+	pending := func() map[string]string {
+		return map[string]string{util.AnnotationCreatePending: "2021-01-01T00:00:00Z"}
+	}
+	kube := &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)}
+	e := &external{client: &fakeRDSClient{found: []*rds.DBInstance{{ID: testName}}}, kube: kube}
+	obj := &v1alpha1.RDSInstance{ObjectMeta: metav1.ObjectMeta{Annotations: pending()}}
+	crossplanemeta.SetExternalName(obj, testName)
+
+	ob, err := e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ob.ResourceExists {
+		t.Error("The RDS instance which may have been created should be recovered")
+	}
+	if obj.Status.AtProvider.DBInstanceID != testName {
+		t.Errorf("DBInstanceID (%v) should be %v", obj.Status.AtProvider.DBInstanceID, testName)
+	}
+	if util.IsCreatePending(obj) {
+		t.Error("The create pending annotation should be removed once the instance is recovered")
+	}
+
+	// The create request timed out and the instance isn't found, so it's
+	// sent again with the same client token.
+	c := &fakeRDSClient{}
+	e = &external{client: c, kube: kube}
+	obj = &v1alpha1.RDSInstance{
+		ObjectMeta: metav1.ObjectMeta{UID: "d3b07384-d9a0-4c6b-9b1d-7f3c1e1f0a2b", Annotations: pending()},
+		Spec: v1alpha1.RDSInstanceSpec{
+			ForProvider: v1alpha1.RDSInstanceParameters{
+				MasterUsername:        testName,
+				Engine:                "PostgreSQL",
+				EngineVersion:         "10.0",
+				SecurityIPList:        "0.0.0.0/0",
+				DBInstanceClass:       "rds.pg.s1.small",
+				DBInstanceStorageInGB: 20,
+			},
+		},
+	}
+	crossplanemeta.SetExternalName(obj, testName)
+
+	ob, err = e.Observe(context.Background(), obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ob.ResourceExists {
+		t.Error("The RDS instance which isn't found should not exist, so that it is created again")
+	}
+	if !util.IsCreatePending(obj) {
+		t.Error("The create pending annotation should be kept until the instance is created again")
+	}
+	if _, err := e.Create(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+	if c.clientToken != string(obj.UID) {
+		t.Errorf("The create request should be sent again with the same client token, got %q", c.clientToken)
+	}
+	if obj.Status.AtProvider.DBInstanceID != testName {
+		t.Errorf("DBInstanceID (%v) should be %v", obj.Status.AtProvider.DBInstanceID, testName)
+	}
+}
+
+func TestExternalClientCreate(t *testing.T) {
+	c := &fakeRDSClient{}
+	e := &external{client: c, kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)}}
 	obj := &v1alpha1.RDSInstance{
 		ObjectMeta: metav1.ObjectMeta{
+			UID: "d3b07384-d9a0-4c6b-9b1d-7f3c1e1f0a2b",
 			Annotations: map[string]string{
 				crossplanemeta.AnnotationKeyExternalName: testName,
 			},
@@ -304,12 +460,44 @@ func TestExternalClientCreate(t *testing.T) {
 	if obj.Status.AtProvider.DBInstanceID != testName {
 		t.Error("DBInstanceID should be set to 'test'")
 	}
+	if c.clientToken != string(obj.UID) {
+		t.Errorf("ClientToken should be derived from the UID, got %q", c.clientToken)
+	}
+	if !util.IsCreatePending(obj) {
+		t.Error("The create pending annotation should be set before creating the instance")
+	}
 	if string(ob.ConnectionDetails[xpv1.ResourceCredentialsSecretEndpointKey]) != "172.0.0.1" ||
 		string(ob.ConnectionDetails[xpv1.ResourceCredentialsSecretPortKey]) != "8888" {
 		t.Error("ConnectionDetails should include endpoint=172.0.0.1 and port=8888")
 	}
 }
 
+func TestExternalClientUpdate(t *testing.T) {
+	c := &fakeRDSClient{}
+	e := &external{client: c}
+	obj := &v1alpha1.RDSInstance{
+		Spec: v1alpha1.RDSInstanceSpec{
+			ForProvider: v1alpha1.RDSInstanceParameters{
+				Tags: map[string]string{"team": "web", "env": "prod"},
+			},
+		},
+		Status: v1alpha1.RDSInstanceStatus{
+			AtProvider: v1alpha1.RDSInstanceObservation{
+				DBInstanceID: testName,
+			},
+		},
+	}
+	if _, err := e.Update(context.Background(), obj); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "web", "env": "prod"}, c.tagged); diff != "" {
+		t.Errorf("TagResources(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"owner"}, c.untagged); diff != "" {
+		t.Errorf("UntagResources(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestExternalClientDelete(t *testing.T) {
 	e := &external{client: &fakeRDSClient{}}
 	obj := &v1alpha1.RDSInstance{
@@ -441,9 +629,13 @@ func TestGetConnectionDetails(t *testing.T) {
 }
 
 type fakeRDSClient struct {
+	tagged      map[string]string
+	untagged    []string
+	clientToken string
+	found       []*rds.DBInstance
 }
 
-func (c *fakeRDSClient) DescribeDBInstance(id string) (*rds.DBInstance, error) {
+func (c *fakeRDSClient) DescribeDBInstance(_ context.Context, id string) (*rds.DBInstance, error) {
 	if id != testName {
 		return nil, errors.New("DescribeDBInstance: client doesn't work")
 	}
@@ -453,10 +645,18 @@ func (c *fakeRDSClient) DescribeDBInstance(id string) (*rds.DBInstance, error) {
 	}, nil
 }
 
-func (c *fakeRDSClient) CreateDBInstance(req *rds.CreateDBInstanceRequest) (*rds.DBInstance, error) {
+func (c *fakeRDSClient) FindDBInstances(_ context.Context, description string, tags map[string]string) ([]*rds.DBInstance, error) {
+	if description != testName && tags[util.TagName] != testName {
+		return nil, nil
+	}
+	return c.found, nil
+}
+
+func (c *fakeRDSClient) CreateDBInstance(_ context.Context, req *rds.CreateDBInstanceRequest) (*rds.DBInstance, error) {
 	if req.Name != testName || req.Engine != "PostgreSQL" {
 		return nil, errors.New("CreateDBInstance: client doesn't work")
 	}
+	c.clientToken = req.ClientToken
 	return &rds.DBInstance{
 		ID: testName,
 		Endpoint: &v1alpha1.Endpoint{
@@ -466,16 +666,39 @@ func (c *fakeRDSClient) CreateDBInstance(req *rds.CreateDBInstanceRequest) (*rds
 	}, nil
 }
 
-func (c *fakeRDSClient) CreateAccount(id, user, pw string) error {
+func (c *fakeRDSClient) CreateAccount(_ context.Context, id, user, pw string) error {
 	if id != testName {
 		return errors.New("CreateAccount: client doesn't work")
 	}
 	return nil
 }
 
-func (c *fakeRDSClient) DeleteDBInstance(id string) error {
+func (c *fakeRDSClient) DeleteDBInstance(_ context.Context, id string) error {
 	if id != testName {
 		return errors.New("DeleteDBInstance: client doesn't work")
 	}
 	return nil
 }
+
+func (c *fakeRDSClient) ListTags(_ context.Context, id string) (map[string]string, error) {
+	if id != testName {
+		return nil, errors.New("ListTags: client doesn't work")
+	}
+	return map[string]string{"team": "db", "owner": "alice"}, nil
+}
+
+func (c *fakeRDSClient) TagResources(_ context.Context, id string, tags map[string]string) error {
+	if id != testName {
+		return errors.New("TagResources: client doesn't work")
+	}
+	c.tagged = tags
+	return nil
+}
+
+func (c *fakeRDSClient) UntagResources(_ context.Context, id string, keys []string) error {
+	if id != testName {
+		return errors.New("UntagResources: client doesn't work")
+	}
+	c.untagged = keys
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// collectTimeout is the timeout of listing the managed resources of all the
+// kinds when the metrics are scraped.
+const collectTimeout = 10 * time.Second
+
+var managedResourcesDesc = prometheus.NewDesc("alibaba_managed_resources",
+	"Number of managed resources per kind and readiness.",
+	[]string{"kind", "ready"}, nil)
+
+// managedResourceCollector collects the number of managed resources per kind
+// and the status of their Ready condition.
+type managedResourceCollector struct {
+	client client.Reader
+	scheme *runtime.Scheme
+	log    logging.Logger
+
+	// lists are the list kinds of the managed resources, by the kinds of
+	// the managed resources.
+	lists map[string]schema.GroupVersionKind
+}
+
+// newManagedResourceCollector returns a collector of the managed resources of
+// the Alibaba Cloud API groups registered in s.
+func newManagedResourceCollector(c client.Reader, s *runtime.Scheme, l logging.Logger) *managedResourceCollector {
+	lists := map[string]schema.GroupVersionKind{}
+	for gvk := range s.AllKnownTypes() {
+		if !strings.HasSuffix(gvk.Group, "alibaba.crossplane.io") || !strings.HasSuffix(gvk.Kind, "List") {
+			continue
+		}
+		obj, err := s.New(gvk)
+		if err != nil {
+			continue
+		}
+		if _, ok := obj.(resource.ManagedList); !ok {
+			continue
+		}
+		kind := schema.GroupKind{Group: gvk.Group, Kind: strings.TrimSuffix(gvk.Kind, "List")}
+		lists[kind.String()] = gvk
+	}
+	return &managedResourceCollector{client: c, scheme: s, log: l, lists: lists}
+}
+
+// Describe implements prometheus.Collector.
+func (c *managedResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- managedResourcesDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *managedResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	kinds := make([]string, 0, len(c.lists))
+	for kind := range c.lists {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for _, kind := range kinds {
+		obj, err := c.scheme.New(c.lists[kind])
+		if err != nil {
+			continue
+		}
+		list := obj.(resource.ManagedList)
+		if err := c.client.List(ctx, list); err != nil {
+			c.log.Debug("Cannot list managed resources", "kind", kind, "error", err)
+			continue
+		}
+		counts := map[corev1.ConditionStatus]int{}
+		for _, mg := range list.GetItems() {
+			counts[mg.GetCondition(xpv1.TypeReady).Status]++
+		}
+		for _, ready := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown} {
+			ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(counts[ready]), kind, string(ready))
+		}
+	}
+}
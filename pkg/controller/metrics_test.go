@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis"
+	"github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestManagedResourceCollector(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+
+	ready := v1alpha1.RDSInstance{}
+	ready.SetConditions(xpv1.Available())
+	creating := v1alpha1.RDSInstance{}
+	creating.SetConditions(xpv1.Creating())
+	c := &test.MockClient{MockList: func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		if l, ok := list.(*v1alpha1.RDSInstanceList); ok {
+			l.Items = []v1alpha1.RDSInstance{ready, creating, {}}
+		}
+		return nil
+	}}
+
+	ch := make(chan prometheus.Metric, 1000)
+	newManagedResourceCollector(c, s, logging.NewNopLogger()).Collect(ch)
+	close(ch)
+
+	got := map[string]float64{}
+	for m := range ch {
+		pb := &dto.Metric{}
+		if err := m.Write(pb); err != nil {
+			t.Fatal(err)
+		}
+		labels := map[string]string{}
+		for _, l := range pb.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["kind"] == v1alpha1.RDSInstanceGroupKind {
+			got[labels["ready"]] = pb.GetGauge().GetValue()
+		}
+	}
+	want := map[string]float64{"True": 1, "False": 1, "Unknown": 1}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Collect(...): -want, +got:\n%s", diff)
+	}
+}
@@ -21,16 +21,19 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	nasclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/nas"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -43,27 +46,32 @@ const (
 )
 
 // SetupNASMountTarget adds a controller that reconciles NASMountTarget.
-func SetupNASMountTarget(mgr ctrl.Manager, l logging.Logger) error {
+func SetupNASMountTarget(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.NASMountTargetGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.NASMountTargetGroupVersionKind.Group)).
 		For(&v1alpha1.NASMountTarget{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.NASMountTargetGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&mtConnector{
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&mtConnector{
 				Client:      mgr.GetClient(),
 				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				NewClientFn: nasclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // mtConnector stores Kubernetes client and NAS client
 type mtConnector struct {
 	Client      client.Client
 	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*nasclient.SDKClient, error)
+	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*nasclient.SDKClient, error)
 }
 
 // Connect initials cloud resource client
@@ -78,8 +86,13 @@ func (c *mtConnector) Connect(ctx context.Context, mg resource.Managed) (managed
 		return nil, err
 	}
 
-	client, err := c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken)
-	return &mountTargetExternal{ExternalClient: client}, errors.Wrap(err, errCreateClient)
+	client, err := util.GetOrCreateClient(info, util.ServiceNAS, func() (interface{}, error) {
+		return c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateClient)
+	}
+	return &mountTargetExternal{ExternalClient: client.(*nasclient.SDKClient)}, nil
 }
 
 // mountTargetExternal includes external NAS client
@@ -100,24 +113,28 @@ func (e *mountTargetExternal) Observe(ctx context.Context, mg resource.Managed)
 		}, nil
 	}
 
-	mountTarget, err := e.ExternalClient.DescribeMountTargets(cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain)
+	mountTarget, err := e.ExternalClient.DescribeMountTargets(ctx, cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain)
 	if err != nil {
 		// Managed resource `NASMountTarget` is special, the identifier of if `name` is different to the cloud resource identifier `MountTargetDomain`
-		if nasclient.IsMountTargetNotFoundError(err) {
+		if errorclass.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeNASMountTarget)
 	}
 
+	current := cr.Spec.ForProvider.DeepCopy()
+	nasclient.LateInitializeMountTarget(&cr.Spec.ForProvider, mountTarget)
+
 	var upToDate = nasclient.IsMountTargetUpdateToDate(cr, mountTarget)
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetMountTargetConnectionDetails(cr),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       GetMountTargetConnectionDetails(cr),
 	}, nil
 }
 
@@ -128,7 +145,10 @@ func (e *mountTargetExternal) Create(ctx context.Context, mg resource.Managed) (
 		return managed.ExternalCreation{}, errors.New(errNotNASMountTarget)
 	}
 	cr.SetConditions(xpv1.Creating())
-	res, err := e.ExternalClient.CreateMountTarget(cr.Spec.ForProvider)
+	if util.Plan(ctx, "CreateMountTarget", nasclient.MakeCreateMountTargetRequest(cr.Spec.ForProvider)) {
+		return managed.ExternalCreation{}, nil
+	}
+	res, err := e.ExternalClient.CreateMountTarget(ctx, cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateNASMountTarget)
 	}
@@ -148,7 +168,10 @@ func (e *mountTargetExternal) Delete(ctx context.Context, mg resource.Managed) e
 		return errors.New(errNotNASMountTarget)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteMountTarget(cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain); err != nil {
+	if util.Plan(ctx, "DeleteMountTarget", cr.Status.AtProvider.MountTargetDomain) {
+		return nil
+	}
+	if err := e.ExternalClient.DeleteMountTarget(ctx, cr.Spec.ForProvider.FileSystemID, cr.Status.AtProvider.MountTargetDomain); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errFailedToDeleteNASMountTarget)
 	}
 	return nil
@@ -33,7 +33,7 @@ import (
 	"github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
 )
 
-func (c *fakeSDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
+func (c *fakeSDKClient) DescribeMountTargets(_ context.Context, fileSystemID, mountTargetDomain *string) (*sdk.DescribeMountTargetsResponse, error) {
 	switch *fileSystemID {
 	case "123":
 		return nil, errors.New("unknown error")
@@ -48,12 +48,12 @@ func (c *fakeSDKClient) DescribeMountTargets(fileSystemID, mountTargetDomain *st
 	}
 }
 
-func (c *fakeSDKClient) CreateMountTarget(fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
+func (c *fakeSDKClient) CreateMountTarget(_ context.Context, fs v1alpha1.NASMountTargetParameter) (*sdk.CreateMountTargetResponse, error) {
 	res := &sdk.CreateMountTargetResponse{Body: &sdk.CreateMountTargetResponseBody{MountTargetDomain: pointer.StringPtr("abc.com")}}
 	return res, nil
 }
 
-func (c *fakeSDKClient) DeleteMountTarget(fileSystemID, mountTargetDomain *string) error {
+func (c *fakeSDKClient) DeleteMountTarget(_ context.Context, fileSystemID, mountTargetDomain *string) error {
 	return nil
 }
 
@@ -21,16 +21,19 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	nasclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/nas"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -40,31 +43,44 @@ const (
 	errFailedToCreateNASFileSystem   = "failed to create NAS filesystem"
 	errFailedToDeleteNASFileSystem   = "failed to delete NAS filesystem"
 	errFailedToDescribeNASFileSystem = "failed to describe NAS filesystem"
+	errFailedToRecoverNASFileSystem  = "failed to recover the created NAS filesystem"
+	errFailedToAdoptNASFileSystem    = "failed to adopt NAS filesystem"
+	errFailedToListTags              = "failed to list the tags of NAS filesystem"
+	errFailedToUpdateTags            = "failed to update the tags of NAS filesystem"
 	errNotNASFileSystem              = "managed resource is not a NASFileSystem custom resource"
 )
 
 // SetupNASFileSystem adds a controller that reconciles NASFileSystem.
-func SetupNASFileSystem(mgr ctrl.Manager, l logging.Logger) error {
+func SetupNASFileSystem(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.NASFileSystemGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.NASFileSystemGroupVersionKind.Group)).
 		For(&v1alpha1.NASFileSystem{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.NASFileSystemGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&Connector{
+			managed.WithInitializers(
+				managed.NewDefaultProviderConfig(mgr.GetClient()),
+				managed.NewNameAsExternalName(mgr.GetClient()),
+				util.NewTagger(mgr.GetClient(), v1alpha1.NASFileSystemGroupKind)),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&Connector{
 				Client:      mgr.GetClient(),
 				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				NewClientFn: nasclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // Connector stores Kubernetes client and NAS client
 type Connector struct {
 	Client      client.Client
 	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*nasclient.SDKClient, error)
+	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*nasclient.SDKClient, error)
 }
 
 // Connect initials cloud resource client
@@ -79,13 +95,19 @@ func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	client, err := c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken)
-	return &External{ExternalClient: client}, errors.Wrap(err, errCreateClient)
+	client, err := util.GetOrCreateClient(info, util.ServiceNAS, func() (interface{}, error) {
+		return c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateClient)
+	}
+	return &External{ExternalClient: client.(*nasclient.SDKClient), Kube: c.Client}, nil
 }
 
 // External includes external NAS client
 type External struct {
 	ExternalClient nasclient.ClientInterface
+	Kube           client.Client
 }
 
 // Observe managed resource NAS filesystem
@@ -95,32 +117,74 @@ func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotNASFileSystem)
 	}
 
-	fsID := cr.Status.AtProvider.FileSystemID
 	if meta.GetExternalName(mg) == "" {
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
 	}
 
-	filesystem, err := e.ExternalClient.DescribeFileSystems(&fsID, cr.Spec.FileSystemType, cr.Spec.VpcID)
+	fsID := cr.Status.AtProvider.FileSystemID
+	if fsID == "" && util.IsObserveOnly(cr) {
+		// An existing file system is observed by its ID.
+		fsID = meta.GetExternalName(cr)
+	}
+	if fsID == "" && util.IsCreatePending(cr) {
+		// The file system may have been created without its ID being
+		// recorded.
+		recovered, err := util.RecoverCreated(ctx, cr, meta.GetExternalName(cr), e.ExternalClient.FindFileSystems)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToRecoverNASFileSystem)
+		}
+		fsID = recovered
+	}
+	if fsID == "" && !util.IsObserveOnly(cr) {
+		// The ID of an existing file system is lost if the status isn't
+		// restored, e.g. by a backup, in which case the file system with the
+		// crossplane tags of the resource is adopted.
+		adopted, err := util.Adopt(ctx, cr, e.ExternalClient.FindFileSystems)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToAdoptNASFileSystem)
+		}
+		fsID = adopted
+	}
+	if fsID == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	filesystem, err := e.ExternalClient.DescribeFileSystems(ctx, &fsID, cr.Spec.FileSystemType, cr.Spec.VpcID)
 	if err != nil {
 		// Managed resource `NASFileSystem` is special, the identifier of if `name` is different to the cloud resource identifier `FileSystemID`
-		if nasclient.IsNotFoundError(err) {
+		if errorclass.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeNASFileSystem)
 	}
 
 	cr.Status.AtProvider = nasclient.GenerateObservation(&fsID, filesystem)
+	if err := util.UnsetCreatePending(ctx, e.Kube, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	current := cr.Spec.NASFileSystemParameter.DeepCopy()
+	nasclient.LateInitialize(&cr.Spec.NASFileSystemParameter, filesystem)
+
 	var upToDate = nasclient.IsUpdateToDate(cr, filesystem)
+	if upToDate {
+		tags, err := e.ExternalClient.ListFileSystemTags(ctx, fsID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToListTags)
+		}
+		upToDate = clients.TagsUpToDate(cr.Spec.Tags, tags)
+	}
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetConnectionDetails(&fsID, cr),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.NASFileSystemParameter),
+		ConnectionDetails:       GetConnectionDetails(&fsID, cr),
 	}, nil
 }
 
@@ -139,11 +203,18 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		VpcID:          cr.Spec.VpcID,
 		VSwitchID:      cr.Spec.VSwitchID,
 	}
-	res, err := e.ExternalClient.CreateFileSystem(filesystemParameter)
+	// The request retried after a lost response returns the same file system.
+	if util.Plan(ctx, "CreateFileSystem", nasclient.MakeCreateFileSystemRequest(filesystemParameter, meta.GetExternalName(cr), util.ClientToken(cr))) {
+		return managed.ExternalCreation{}, nil
+	}
+	if err := util.SetCreatePending(ctx, e.Kube, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	res, err := e.ExternalClient.CreateFileSystem(ctx, filesystemParameter, meta.GetExternalName(cr), util.ClientToken(cr))
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateNASFileSystem)
+		return managed.ExternalCreation{}, errors.Wrap(util.CreateFailed(ctx, e.Kube, cr, err), errFailedToCreateNASFileSystem)
 	}
-	fsRes, err := e.ExternalClient.DescribeFileSystems(res.Body.FileSystemId, cr.Spec.FileSystemType, cr.Spec.VpcID)
+	fsRes, err := e.ExternalClient.DescribeFileSystems(ctx, res.Body.FileSystemId, cr.Spec.FileSystemType, cr.Spec.VpcID)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToDescribeNASFileSystem)
 	}
@@ -153,7 +224,32 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 // Update managed resource NASFilesystem
 func (e *External) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+	cr, ok := mg.(*v1alpha1.NASFileSystem)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotNASFileSystem)
+	}
+	fsID := cr.Status.AtProvider.FileSystemID
+	if fsID == "" {
+		return managed.ExternalUpdate{}, nil
+	}
+	tags, err := e.ExternalClient.ListFileSystemTags(ctx, fsID)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToListTags)
+	}
+	err = clients.UpdateTags(cr.Spec.Tags, tags,
+		func(add map[string]string) error {
+			if util.Plan(ctx, "TagResources", add) {
+				return nil
+			}
+			return e.ExternalClient.TagFileSystem(ctx, fsID, add)
+		},
+		func(remove []string) error {
+			if util.Plan(ctx, "UntagResources", remove) {
+				return nil
+			}
+			return e.ExternalClient.UntagFileSystem(ctx, fsID, remove)
+		})
+	return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToUpdateTags)
 }
 
 // Delete managed resource NASFilesystem
@@ -163,7 +259,10 @@ func (e *External) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotNASFileSystem)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteFileSystem(cr.Status.AtProvider.FileSystemID); err != nil {
+	if util.Plan(ctx, "DeleteFileSystem", cr.Status.AtProvider.FileSystemID) {
+		return nil
+	}
+	if err := e.ExternalClient.DeleteFileSystem(ctx, cr.Status.AtProvider.FileSystemID); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errFailedToDeleteNASFileSystem)
 	}
 	return nil
@@ -31,15 +31,34 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
 
 type fakeSDKClient struct {
+	tagged      map[string]string
+	untagged    []string
+	found       []string
+	described   bool
+	clientToken string
 }
 
-func (c *fakeSDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
+func (c *fakeSDKClient) DescribeFileSystems(_ context.Context, fileSystemID, fileSystemType, vpcID *string) (*sdk.DescribeFileSystemsResponse, error) {
+	c.described = true
 	switch *fileSystemID {
 	case "123":
 		return nil, errors.New("unknown error")
+	case "789":
+		body := &sdk.DescribeFileSystemsResponseBody{
+			FileSystems: &sdk.DescribeFileSystemsResponseBodyFileSystems{
+				FileSystem: []*sdk.DescribeFileSystemsResponseBodyFileSystemsFileSystem{{
+					FileSystemId:   pointer.StringPtr("789"),
+					FileSystemType: pointer.StringPtr("standard"),
+					ChargeType:     pointer.StringPtr("PayAsYouGo"),
+					StorageType:    pointer.StringPtr("Performance"),
+					ProtocolType:   pointer.StringPtr("NFS"),
+					MountTargets:   &sdk.DescribeFileSystemsResponseBodyFileSystemsFileSystemMountTargets{},
+				}}}, TotalCount: pointer.Int32Ptr(1)}
+		return &sdk.DescribeFileSystemsResponse{Body: body}, nil
 	default:
 		body := &sdk.DescribeFileSystemsResponseBody{
 			FileSystems: &sdk.DescribeFileSystemsResponseBodyFileSystems{
@@ -51,12 +70,34 @@ func (c *fakeSDKClient) DescribeFileSystems(fileSystemID, fileSystemType, vpcID
 	}
 }
 
-func (c *fakeSDKClient) CreateFileSystem(fs v1alpha1.NASFileSystemParameter) (*sdk.CreateFileSystemResponse, error) {
+func (c *fakeSDKClient) FindFileSystems(_ context.Context, description string, tags map[string]string) ([]string, error) {
+	if description != "def" && tags[util.TagName] != "def" {
+		return nil, nil
+	}
+	return c.found, nil
+}
+
+func (c *fakeSDKClient) CreateFileSystem(_ context.Context, fs v1alpha1.NASFileSystemParameter, description, clientToken string) (*sdk.CreateFileSystemResponse, error) {
+	c.clientToken = clientToken
 	res := &sdk.CreateFileSystemResponse{Body: &sdk.CreateFileSystemResponseBody{FileSystemId: pointer.StringPtr("123456")}}
 	return res, nil
 }
 
-func (c *fakeSDKClient) DeleteFileSystem(fileSystemID string) error {
+func (c *fakeSDKClient) DeleteFileSystem(_ context.Context, fileSystemID string) error {
+	return nil
+}
+
+func (c *fakeSDKClient) ListFileSystemTags(_ context.Context, fileSystemID string) (map[string]string, error) {
+	return map[string]string{"team": "storage", "owner": "alice"}, nil
+}
+
+func (c *fakeSDKClient) TagFileSystem(_ context.Context, fileSystemID string, tags map[string]string) error {
+	c.tagged = tags
+	return nil
+}
+
+func (c *fakeSDKClient) UntagFileSystem(_ context.Context, fileSystemID string, keys []string) error {
+	c.untagged = keys
 	return nil
 }
 
@@ -135,6 +176,206 @@ func TestObserve(t *testing.T) {
 	}
 }
 
+func TestObserveLateInitialize(t *testing.T) {
+	var ctx = context.Background()
+
+	cases := map[string]struct {
+		reason    string
+		id        string
+		want      *string
+		described bool
+	}{
+		"RecordedID": {
+			reason:    "The file system looked up by the recorded ID should late initialize the parameters",
+			id:        "789",
+			want:      pointer.StringPtr("PayAsYouGo"),
+			described: true,
+		},
+		"NoRecordedID": {
+			reason: "The file system should not be looked up and the parameters should not be late initialized without the recorded ID",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.NASFileSystem{}
+			cr.ObjectMeta.Annotations = map[string]string{meta.AnnotationKeyExternalName: "def"}
+			cr.Spec.StorageType = pointer.StringPtr("Performance")
+			cr.Spec.ProtocolType = pointer.StringPtr("NFS")
+			cr.Status.AtProvider.FileSystemID = tc.id
+			spec := cr.Spec.DeepCopy()
+			client := &fakeSDKClient{}
+			external := &External{ExternalClient: client}
+			got, err := external.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v\n", tc.reason, err)
+			}
+			if client.described != tc.described {
+				t.Errorf("\n%s\ne.Observe(...): want described %t, got %t\n", tc.reason, tc.described, client.described)
+			}
+			if diff := cmp.Diff(tc.want, cr.Spec.ChargeType); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want charge type, +got charge type:\n%s\n", tc.reason, diff)
+			}
+			if tc.want == nil {
+				if diff := cmp.Diff(spec, &cr.Spec); diff != "" {
+					t.Errorf("\n%s\ne.Observe(...): -want spec, +got spec:\n%s\n", tc.reason, diff)
+				}
+			}
+			if got.ResourceLateInitialized != (tc.want != nil) {
+				t.Errorf("\n%s\ne.Observe(...): want late initialized %t, got %t\n", tc.reason, tc.want != nil, got.ResourceLateInitialized)
+			}
+		})
+	}
+}
+
+func TestObserveObserveOnly(t *testing.T) {
+	var ctx = context.Background()
+
+	cases := map[string]struct {
+		reason      string
+		observeOnly bool
+		want        string
+	}{
+		"ObserveOnly": {
+			reason:      "An existing file system should be observed by the ID in the external name",
+			observeOnly: true,
+			want:        "789",
+		},
+		"Managed": {
+			reason: "A file system without a recorded ID should not be looked up by the external name",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.NASFileSystem{}
+			meta.SetExternalName(cr, "789")
+			if tc.observeOnly {
+				meta.AddAnnotations(cr, map[string]string{util.AnnotationManagementPolicy: util.ManagementPolicyObserveOnly})
+			}
+			external := &External{ExternalClient: &fakeSDKClient{}}
+			got, err := external.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v\n", tc.reason, err)
+			}
+			if got.ResourceExists != (tc.want != "") {
+				t.Errorf("\n%s\ne.Observe(...): want exists %t, got %t\n", tc.reason, tc.want != "", got.ResourceExists)
+			}
+			if cr.Status.AtProvider.FileSystemID != tc.want {
+				t.Errorf("\n%s\ne.Observe(...): want file system ID %q, got %q\n", tc.reason, tc.want, cr.Status.AtProvider.FileSystemID)
+			}
+		})
+	}
+}
+
+func TestObserveCreatePending(t *testing.T) {
+	var ctx = context.Background()
+
+	cases := map[string]struct {
+		reason string
+		found  []string
+		want   string
+	}{
+		"Recovered": {
+			reason: "The file system which may have been created should be recovered",
+			found:  []string{"789"},
+			want:   "789",
+		},
+		"NotFound": {
+			reason: "The file system which isn't found after a timed out create should be created again with the same client token",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.NASFileSystem{}
+			cr.ObjectMeta.UID = "d3b07384-d9a0-4c6b-9b1d-7f3c1e1f0a2b"
+			cr.ObjectMeta.Annotations = map[string]string{
+				meta.AnnotationKeyExternalName: "def",
+				util.AnnotationCreatePending:   "2021-01-01T00:00:00Z",
+			}
+			client := &fakeSDKClient{found: tc.found}
+			external := &External{
+				ExternalClient: client,
+				Kube:           &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			}
+			got, err := external.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v\n", tc.reason, err)
+			}
+			if got.ResourceExists != (tc.want != "") {
+				t.Errorf("\n%s\ne.Observe(...): want exists %t, got %t\n", tc.reason, tc.want != "", got.ResourceExists)
+			}
+			if cr.Status.AtProvider.FileSystemID != tc.want {
+				t.Errorf("\n%s\ne.Observe(...): want file system ID %q, got %q\n", tc.reason, tc.want, cr.Status.AtProvider.FileSystemID)
+			}
+			if got.ResourceExists {
+				return
+			}
+			if !util.IsCreatePending(cr) {
+				t.Errorf("\n%s\ne.Observe(...): want the create pending annotation kept\n", tc.reason)
+			}
+			if _, err := external.Create(ctx, cr); err != nil {
+				t.Fatalf("\n%s\ne.Create(...): %v\n", tc.reason, err)
+			}
+			if client.clientToken != string(cr.UID) {
+				t.Errorf("\n%s\ne.Create(...): want client token %q, got %q\n", tc.reason, cr.UID, client.clientToken)
+			}
+		})
+	}
+}
+
+func TestObserveAdopt(t *testing.T) {
+	var ctx = context.Background()
+
+	cases := map[string]struct {
+		reason string
+		tags   map[string]string
+		found  []string
+		want   string
+		err    bool
+	}{
+		"Adopted": {
+			reason: "The file system with the crossplane tags of the resource should be adopted",
+			tags:   map[string]string{util.TagKind: "nasfilesystem.nas.alibaba.crossplane.io", util.TagName: "def"},
+			found:  []string{"789"},
+			want:   "789",
+		},
+		"NoCrossplaneTags": {
+			reason: "The file system described by the external name alone should not be adopted",
+			found:  []string{"789"},
+		},
+		"Ambiguous": {
+			reason: "No file system should be adopted when several have the crossplane tags of the resource",
+			tags:   map[string]string{util.TagKind: "nasfilesystem.nas.alibaba.crossplane.io", util.TagName: "def"},
+			found:  []string{"789", "790"},
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.NASFileSystem{}
+			cr.Spec.Tags = tc.tags
+			cr.ObjectMeta.Annotations = map[string]string{meta.AnnotationKeyExternalName: "def"}
+			external := &External{
+				ExternalClient: &fakeSDKClient{found: tc.found},
+				Kube:           &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+			}
+			got, err := external.Observe(ctx, cr)
+			if (err != nil) != tc.err {
+				t.Errorf("\n%s\ne.Observe(...): want error %t, got %v\n", tc.reason, tc.err, err)
+			}
+			if got.ResourceExists != (tc.want != "") {
+				t.Errorf("\n%s\ne.Observe(...): want exists %t, got %t\n", tc.reason, tc.want != "", got.ResourceExists)
+			}
+			if cr.Status.AtProvider.FileSystemID != tc.want {
+				t.Errorf("\n%s\ne.Observe(...): want file system ID %q, got %q\n", tc.reason, tc.want, cr.Status.AtProvider.FileSystemID)
+			}
+		})
+	}
+}
+
 func TestCreate(t *testing.T) {
 	var ctx = context.Background()
 
@@ -174,7 +415,7 @@ func TestCreate(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			external := &External{ExternalClient: &fakeSDKClient{}}
+			external := &External{ExternalClient: &fakeSDKClient{}, Kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)}}
 			got, err := external.Create(ctx, tc.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -186,6 +427,24 @@ func TestCreate(t *testing.T) {
 	}
 }
 
+func TestUpdate(t *testing.T) {
+	cr := &v1alpha1.NASFileSystem{}
+	cr.Spec.Tags = map[string]string{"team": "web"}
+	cr.Status.AtProvider.FileSystemID = "456"
+
+	c := &fakeSDKClient{}
+	external := &External{ExternalClient: c}
+	if _, err := external.Update(context.Background(), cr); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]string{"team": "web"}, c.tagged); diff != "" {
+		t.Errorf("TagFileSystem(...): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"owner"}, c.untagged); diff != "" {
+		t.Errorf("UntagFileSystem(...): -want, +got:\n%s", diff)
+	}
+}
+
 func TestDelete(t *testing.T) {
 	var ctx = context.Background()
 
@@ -21,16 +21,19 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	ossclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/oss"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -41,31 +44,42 @@ const (
 	errFailedToUpdateBucket   = "failed to update OSS bucket"
 	errFailedToDeleteBucket   = "failed to delete OSS bucket"
 	errFailedToDescribeBucket = "failed to describe OSS bucket"
+	errFailedToGetTags        = "failed to get the tags of OSS bucket"
+	errFailedToSetTags        = "failed to set the tags of OSS bucket"
 	errNotBucket              = "managed resource is not a Bucket custom resource"
 )
 
 // SetupBucket adds a controller that reconciles Bucket.
-func SetupBucket(mgr ctrl.Manager, l logging.Logger) error {
+func SetupBucket(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.BucketGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.BucketGroupVersionKind.Group)).
 		For(&v1alpha1.Bucket{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.BucketGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&Connector{
+			managed.WithInitializers(
+				managed.NewDefaultProviderConfig(mgr.GetClient()),
+				managed.NewNameAsExternalName(mgr.GetClient()),
+				util.NewTagger(mgr.GetClient(), v1alpha1.BucketGroupKind)),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&Connector{
 				Client:      mgr.GetClient(),
 				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				NewClientFn: ossclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // Connector stores Kubernetes client and oss client
 type Connector struct {
 	Client      client.Client
 	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*ossclient.SDKClient, error)
+	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*ossclient.SDKClient, error)
 }
 
 // Connect initials cloud resource client
@@ -80,8 +94,13 @@ func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	ossClient, err := c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken)
-	return &External{ExternalClient: ossClient}, errors.Wrap(err, errCreateClient)
+	ossClient, err := util.GetOrCreateClient(info, util.ServiceOSS, func() (interface{}, error) {
+		return c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateClient)
+	}
+	return &External{ExternalClient: ossClient.(*ossclient.SDKClient)}, nil
 }
 
 // External includes external OSS client
@@ -96,8 +115,8 @@ func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotBucket)
 	}
 
-	bucket, err := e.ExternalClient.Describe(meta.GetExternalName(cr))
-	if ossclient.IsNotFoundError(err) {
+	bucket, err := e.ExternalClient.Describe(ctx, meta.GetExternalName(cr))
+	if errorclass.IsNotFound(err) {
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
@@ -114,15 +133,24 @@ func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if cr.Spec.DataRedundancyType != "" && cr.Spec.DataRedundancyType != bucket.BucketInfo.RedundancyType {
 		cr.Status.AtProvider.Message += "[Warning] DataRedundancyType is not allowed to update after creation; "
 	}
-	var upToDate = ossclient.IsUpdateToDate(cr, bucket)
+
+	current := cr.Spec.BucketParameter.DeepCopy()
+	ossclient.LateInitialize(&cr.Spec.BucketParameter, bucket)
+
+	tags, err := e.ExternalClient.GetTags(ctx, meta.GetExternalName(cr))
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errFailedToGetTags)
+	}
+	var upToDate = ossclient.IsUpdateToDate(cr, bucket) && clients.TagsUpToDate(cr.Spec.Tags, tags)
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetConnectionDetails(cr),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.BucketParameter),
+		ConnectionDetails:       GetConnectionDetails(cr),
 	}, nil
 }
 
@@ -139,7 +167,10 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		DataRedundancyType: cr.Spec.DataRedundancyType,
 	}
 	name := meta.GetExternalName(cr)
-	if err := e.ExternalClient.Create(name, bucketParameter); err != nil {
+	if util.Plan(ctx, "CreateBucket", map[string]interface{}{"Bucket": name, "Parameters": bucketParameter}) {
+		return managed.ExternalCreation{}, nil
+	}
+	if err := e.ExternalClient.Create(ctx, name, bucketParameter); err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateBucket)
 	}
 	return managed.ExternalCreation{ConnectionDetails: GetConnectionDetails(cr)}, nil
@@ -152,17 +183,28 @@ func (e *External) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotBucket)
 	}
 	cr.Status.SetConditions(xpv1.Creating())
-	got, err := e.ExternalClient.Describe(meta.GetExternalName(cr))
+	got, err := e.ExternalClient.Describe(ctx, meta.GetExternalName(cr))
 	if err != nil {
 		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToDescribeBucket)
 	}
 
-	if cr.Spec.ACL != "" && cr.Spec.ACL != got.BucketInfo.ACL {
-		if err := e.ExternalClient.Update(meta.GetExternalName(cr), cr.Spec.ACL); err != nil {
+	if cr.Spec.ACL != "" && cr.Spec.ACL != got.BucketInfo.ACL && !util.Plan(ctx, "SetBucketACL", cr.Spec.ACL) {
+		if err := e.ExternalClient.Update(ctx, meta.GetExternalName(cr), cr.Spec.ACL); err != nil {
 			return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToUpdateBucket)
 		}
 	}
 
+	tags, err := e.ExternalClient.GetTags(ctx, meta.GetExternalName(cr))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToGetTags)
+	}
+	merged := clients.MergeTags(cr.Spec.Tags, tags)
+	if !clients.TagsUpToDate(cr.Spec.Tags, tags) && !util.Plan(ctx, "SetBucketTagging", merged) {
+		if err := e.ExternalClient.SetTags(ctx, meta.GetExternalName(cr), merged); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToSetTags)
+		}
+	}
+
 	return managed.ExternalUpdate{}, nil
 }
 
@@ -173,7 +215,10 @@ func (e *External) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotBucket)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.Delete(meta.GetExternalName(cr)); err != nil && !ossclient.IsNotFoundError(err) {
+	if util.Plan(ctx, "DeleteBucket", meta.GetExternalName(cr)) {
+		return nil
+	}
+	if err := e.ExternalClient.Delete(ctx, meta.GetExternalName(cr)); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errFailedToDeleteBucket)
 	}
 	return nil
@@ -35,7 +35,7 @@ import (
 type fakeSDKClient struct {
 }
 
-func (c *fakeSDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error) {
+func (c *fakeSDKClient) Describe(_ context.Context, name string) (*sdk.GetBucketInfoResult, error) {
 	switch name {
 	case "":
 		return nil, sdk.ServiceError{Code: ossclient.ErrCodeNoSuchBucket}
@@ -53,11 +53,11 @@ func (c *fakeSDKClient) Describe(name string) (*sdk.GetBucketInfoResult, error)
 	}
 }
 
-func (c *fakeSDKClient) Create(name string, bucket ossv1alpha1.BucketParameter) error {
+func (c *fakeSDKClient) Create(_ context.Context, name string, bucket ossv1alpha1.BucketParameter) error {
 	return nil
 }
 
-func (c *fakeSDKClient) Update(name string, aclStr string) error {
+func (c *fakeSDKClient) Update(_ context.Context, name string, aclStr string) error {
 	_, err := ossclient.ValidateOSSAcl(aclStr)
 	if err != nil {
 		return err
@@ -65,7 +65,15 @@ func (c *fakeSDKClient) Update(name string, aclStr string) error {
 	return nil
 }
 
-func (c *fakeSDKClient) Delete(name string) error {
+func (c *fakeSDKClient) Delete(_ context.Context, name string) error {
+	return nil
+}
+
+func (c *fakeSDKClient) GetTags(_ context.Context, name string) (map[string]string, error) {
+	return map[string]string{"team": "storage"}, nil
+}
+
+func (c *fakeSDKClient) SetTags(_ context.Context, name string, tags map[string]string) error {
 	return nil
 }
 
@@ -74,6 +82,16 @@ func TestObserve(t *testing.T) {
 
 	validCR := &ossv1alpha1.Bucket{}
 	validCR.ObjectMeta.Annotations = map[string]string{meta.AnnotationKeyExternalName: "def"}
+	validCR.Spec.Tags = map[string]string{"team": "storage"}
+	validCR.Spec.ACL = "private"
+	validCR.Spec.StorageClass = "Standard"
+
+	driftedCR := validCR.DeepCopy()
+	driftedCR.Spec.Tags = map[string]string{"team": "web"}
+
+	defaultedCR := &ossv1alpha1.Bucket{}
+	defaultedCR.ObjectMeta.Annotations = map[string]string{meta.AnnotationKeyExternalName: "def"}
+	defaultedCR.Spec.Tags = map[string]string{"team": "storage"}
 
 	invalidCR := &ossv1alpha1.Bucket{}
 	invalidCR.ObjectMeta.Annotations = map[string]string{meta.AnnotationKeyExternalName: "abc"}
@@ -125,6 +143,29 @@ func TestObserve(t *testing.T) {
 				err: nil,
 			},
 		},
+		"TagsDrifted": {
+			reason: "A Bucket whose tags differ should not be up to date",
+			mg:     driftedCR,
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:    true,
+					ResourceUpToDate:  false,
+					ConnectionDetails: GetConnectionDetails(driftedCR)},
+				err: nil,
+			},
+		},
+		"LateInitialized": {
+			reason: "The empty parameters of a Bucket should be late initialized from the bucket",
+			mg:     defaultedCR,
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       GetConnectionDetails(defaultedCR)},
+				err: nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
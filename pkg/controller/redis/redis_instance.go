@@ -18,124 +18,105 @@ package redis
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 
-	sdkerror "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/password"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/redis"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
 
 const (
 	// Fall to connection instance error description
 	errCreateInstanceConnectionFailed = "cannot instance connection"
 
-	errNotInstance         = "managed resource is not an instance custom resource"
-	errNoProvider          = "no provider config or provider specified"
-	errCreateClient        = "cannot create redis client"
-	errGetProviderConfig   = "cannot get provider config"
-	errTrackUsage          = "cannot track provider config usage"
-	errNoConnectionSecret  = "no connection secret specified"
-	errGetConnectionSecret = "cannot get connection secret"
+	errNotInstance  = "managed resource is not an instance custom resource"
+	errNoProvider   = "no provider config or provider specified"
+	errCreateClient = "cannot create redis client"
 
 	errCreateFailed        = "cannot create redis instance"
 	errCreateAccountFailed = "cannot create redis account"
 	errDeleteFailed        = "cannot delete redis instance"
 	errDescribeFailed      = "cannot describe redis instance"
-
-	errFmtUnsupportedCredSource = "credentials source %q is not currently supported"
-	errDuplicateConnectionPort  = "InvalidConnectionStringOrPort.Duplicate"
-	errAccountNameDuplicate     = "InvalidAccountName.Duplicate"
+	errAdoptFailed         = "cannot adopt redis instance"
+	errRecoverFailed       = "cannot recover the created redis instance"
+	errListTagsFailed      = "cannot list the tags of redis instance"
+	errUpdateTagsFailed    = "cannot update the tags of redis instance"
 
 	// Default port of redis database
 	defaultRedisPort = "6379"
 )
 
 // SetupRedisInstance adds a controller that reconciles RedisInstances.
-func SetupRedisInstance(mgr ctrl.Manager, l logging.Logger) error {
+func SetupRedisInstance(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.RedisInstanceGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.RedisInstanceGroupVersionKind.Group)).
 		For(&v1alpha1.RedisInstance{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.RedisInstanceGroupVersionKind),
-			managed.WithExternalConnecter(&redisConnector{
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&redisConnector{
 				client:         mgr.GetClient(),
 				usage:          resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				newRedisClient: redis.NewClient,
-			}),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name)))))
+			}), o.DryRun, recorder)))),
+			managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient()), managed.NewNameAsExternalName(mgr.GetClient()), util.NewTagger(mgr.GetClient(), v1alpha1.RedisInstanceGroupKind)),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder)))))
 }
 
 type redisConnector struct {
 	client         client.Client
 	usage          resource.Tracker
-	newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error)
+	newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (redis.Client, error)
 }
 
-func (c *redisConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
-	// account for the deprecated Provider type.
+func (c *redisConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
 	cr, ok := mg.(*v1alpha1.RedisInstance)
 	if !ok {
 		return nil, errors.New(errNotInstance)
 	}
-
-	// provider has more than one kind of managed resource.
-	var (
-		sel    *xpv1.SecretKeySelector
-		region string
-	)
-	switch {
-	case cr.GetProviderConfigReference() != nil:
-		if err := c.usage.Track(ctx, mg); err != nil {
-			return nil, errors.Wrap(err, errTrackUsage)
-		}
-
-		pc := &aliv1beta1.ProviderConfig{}
-		if err := c.client.Get(ctx, types.NamespacedName{Name: cr.Spec.ProviderConfigReference.Name}, pc); err != nil {
-			return nil, errors.Wrap(err, errGetProviderConfig)
-		}
-		if s := pc.Spec.Credentials.Source; s != xpv1.CredentialsSourceSecret {
-			return nil, errors.Errorf(errFmtUnsupportedCredSource, s)
-		}
-		sel = pc.Spec.Credentials.SecretRef
-		region = pc.Spec.Region
-	default:
+	if cr.GetProviderConfigReference() == nil {
 		return nil, errors.New(errNoProvider)
 	}
 
-	if sel == nil {
-		return nil, errors.New(errNoConnectionSecret)
+	info, err := util.PrepareClient(ctx, mg, cr, c.client, c.usage, cr.Spec.ProviderConfigReference.Name)
+	if err != nil {
+		return nil, err
 	}
 
-	s := &corev1.Secret{}
-	nn := types.NamespacedName{Namespace: sel.Namespace, Name: sel.Name}
-	if err := c.client.Get(ctx, nn, s); err != nil {
-		return nil, errors.Wrap(err, errGetConnectionSecret)
+	redisClient, err := util.GetOrCreateClient(info, util.ServiceRedis, func() (interface{}, error) {
+		return c.newRedisClient(ctx, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Region, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateClient)
 	}
-
-	redisClient, err := c.newRedisClient(ctx, string(s.Data["accessKeyId"]), string(s.Data["accessKeySecret"]), region)
-	return &external{client: redisClient}, errors.Wrap(err, errCreateClient)
+	return &external{client: redisClient.(redis.Client), kube: c.client}, nil
 }
 
 type external struct {
 	client redis.Client
+	kube   client.Client
 }
 
 func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -144,22 +125,58 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotInstance)
 	}
 
-	if cr.Status.AtProvider.DBInstanceID == "" {
+	id := cr.Status.AtProvider.DBInstanceID
+	if id == "" && util.IsObserveOnly(cr) {
+		// An existing instance is observed by its ID.
+		id = meta.GetExternalName(cr)
+	}
+	if id == "" && util.IsCreatePending(cr) {
+		// The instance may have been created without its ID being recorded.
+		recovered, err := util.RecoverCreated(ctx, cr, meta.GetExternalName(cr), e.findDBInstances)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errRecoverFailed)
+		}
+		id = recovered
+	}
+	if id == "" && !util.IsObserveOnly(cr) {
+		// The ID of an existing instance is lost if the status isn't
+		// restored, e.g. by a backup, in which case the instance with the
+		// crossplane tags of the resource is adopted.
+		adopted, err := util.Adopt(ctx, cr, e.findDBInstances)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdoptFailed)
+		}
+		id = adopted
+	}
+	if id == "" {
 		return managed.ExternalObservation{}, nil
 	}
 
-	instance, err := e.client.DescribeDBInstance(cr.Status.AtProvider.DBInstanceID)
+	instance, err := e.client.DescribeDBInstance(ctx, id)
 	if err != nil {
-		fmt.Print(err.Error(), resource.Ignore(redis.IsErrorNotFound, err))
-		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(redis.IsErrorNotFound, err), errDescribeFailed)
+		return managed.ExternalObservation{}, errors.Wrap(resource.Ignore(errorclass.IsNotFound, err), errDescribeFailed)
 	}
 
 	cr.Status.AtProvider = redis.GenerateObservation(instance)
+	if err := util.UnsetCreatePending(ctx, e.kube, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	redis.LateInitialize(&cr.Spec.ForProvider, instance)
+
+	tags, err := e.client.ListTags(ctx, instance.ID)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrap(err, errListTagsFailed)
+	}
 	var pw string
 	switch cr.Status.AtProvider.DBInstanceStatus {
 	case v1alpha1.RedisInstanceStateRunning:
 		cr.Status.SetConditions(xpv1.Available())
-		address, port, err := e.createConnectionIfNeeded(cr)
+		if util.IsObserveOnly(cr) || util.IsDryRun(ctx) {
+			break
+		}
+		address, port, err := e.createConnectionIfNeeded(ctx, cr)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errCreateInstanceConnectionFailed)
 		}
@@ -168,7 +185,7 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 			Port:    port,
 		}
 
-		pw, err = e.createAccountIfNeeded(cr)
+		pw, err = e.createAccountIfNeeded(ctx, cr)
 		if err != nil {
 			return managed.ExternalObservation{}, errors.Wrap(err, errCreateAccountFailed)
 		}
@@ -180,21 +197,29 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		cr.Status.SetConditions(xpv1.Unavailable())
 	}
 
+	// Only the tags are updated, the drift of the parameters is reported
+	// when the instance is only observed.
+	upToDate := clients.TagsUpToDate(cr.Spec.ForProvider.Tags, tags)
+	if util.IsObserveOnly(cr) {
+		upToDate = upToDate && redis.IsUpToDate(cr.Spec.ForProvider, instance)
+	}
+
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  true,
-		ConnectionDetails: getConnectionDetails(pw, cr, instance),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       getConnectionDetails(pw, cr, instance),
 	}, nil
 }
 
-func (e *external) createConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func (e *external) createConnectionIfNeeded(ctx context.Context, cr *v1alpha1.RedisInstance) (string, string, error) {
 	if cr.Spec.ForProvider.PubliclyAccessible {
-		return e.createPublicConnectionIfNeeded(cr)
+		return e.createPublicConnectionIfNeeded(ctx, cr)
 	}
-	return e.createPrivateConnectionIfNeeded(cr)
+	return e.createPrivateConnectionIfNeeded(ctx, cr)
 }
 
-func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func (e *external) createPrivateConnectionIfNeeded(ctx context.Context, cr *v1alpha1.RedisInstance) (string, string, error) {
 	domain := cr.Status.AtProvider.DBInstanceID + ".redis.rds.aliyuncs.com"
 	if cr.Spec.ForProvider.InstancePort == 0 {
 		return domain, defaultRedisPort, nil
@@ -203,15 +228,12 @@ func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (
 	if cr.Status.AtProvider.ConnectionReady {
 		return domain, port, nil
 	}
-	connectionDomain, err := e.client.ModifyDBInstanceConnectionString(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
+	connectionDomain, err := e.client.ModifyDBInstanceConnectionString(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
-		var sdkerr sdkerror.Error
-		if errors.As(err, &sdkerr) {
-			if sdkerr.ErrorCode() == errDuplicateConnectionPort {
-				cr.Status.AtProvider.ConnectionReady = true
-				return domain, port, nil
-			}
+		if errorclass.IsAlreadyExists(err) {
+			cr.Status.AtProvider.ConnectionReady = true
+			return domain, port, nil
 		}
 		return "", "", err
 	}
@@ -220,7 +242,7 @@ func (e *external) createPrivateConnectionIfNeeded(cr *v1alpha1.RedisInstance) (
 	return connectionDomain, port, nil
 }
 
-func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (string, string, error) {
+func (e *external) createPublicConnectionIfNeeded(ctx context.Context, cr *v1alpha1.RedisInstance) (string, string, error) {
 	domain := cr.Status.AtProvider.DBInstanceID + redis.PubilConnectionDomain
 	if cr.Status.AtProvider.ConnectionReady {
 		return domain, "", nil
@@ -229,15 +251,12 @@ func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (s
 	if cr.Spec.ForProvider.InstancePort != 0 {
 		port = strconv.Itoa(cr.Spec.ForProvider.InstancePort)
 	}
-	_, err := e.client.AllocateInstancePublicConnection(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
+	_, err := e.client.AllocateInstancePublicConnection(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.InstancePort)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
-		var sdkerr sdkerror.Error
-		if errors.As(err, &sdkerr) {
-			if sdkerr.ErrorCode() == errDuplicateConnectionPort || sdkerr.ErrorCode() == "NetTypeExists" {
-				cr.Status.AtProvider.ConnectionReady = true
-				return domain, port, nil
-			}
+		if errorclass.IsAlreadyExists(err) {
+			cr.Status.AtProvider.ConnectionReady = true
+			return domain, port, nil
 		}
 		return "", "", err
 	}
@@ -246,7 +265,21 @@ func (e *external) createPublicConnectionIfNeeded(cr *v1alpha1.RedisInstance) (s
 	return domain, port, nil
 }
 
-func (e *external) createAccountIfNeeded(cr *v1alpha1.RedisInstance) (string, error) {
+// findDBInstances finds the IDs of the instances named name and tagged with
+// tags.
+func (e *external) findDBInstances(ctx context.Context, name string, tags map[string]string) ([]string, error) {
+	instances, err := e.client.FindDBInstances(ctx, name, tags)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(instances))
+	for _, in := range instances {
+		ids = append(ids, in.ID)
+	}
+	return ids, nil
+}
+
+func (e *external) createAccountIfNeeded(ctx context.Context, cr *v1alpha1.RedisInstance) (string, error) {
 	if cr.Status.AtProvider.AccountReady {
 		return "", nil
 	}
@@ -260,15 +293,12 @@ func (e *external) createAccountIfNeeded(cr *v1alpha1.RedisInstance) (string, er
 		return pw, nil
 	}
 
-	err = e.client.CreateAccount(cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
+	err = e.client.CreateAccount(ctx, cr.Status.AtProvider.DBInstanceID, cr.Spec.ForProvider.MasterUsername, pw)
 	if err != nil {
 		// The previous request might fail due to timeout. That's fine we will eventually reconcile it.
-		var sdkerr sdkerror.Error
-		if errors.As(err, &sdkerr) {
-			if sdkerr.ErrorCode() == errAccountNameDuplicate {
-				cr.Status.AtProvider.AccountReady = true
-				return "", nil
-			}
+		if errorclass.IsAlreadyExists(err) {
+			cr.Status.AtProvider.AccountReady = true
+			return "", nil
 		}
 		return "", err
 	}
@@ -289,9 +319,17 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 
 	req := redis.MakeCreateDBInstanceRequest(meta.GetExternalName(cr), &cr.Spec.ForProvider)
-	instance, err := e.client.CreateDBInstance(req)
+	// The request retried after a lost response returns the same instance.
+	req.ClientToken = util.ClientToken(cr)
+	if util.Plan(ctx, "CreateInstance", req) {
+		return managed.ExternalCreation{}, nil
+	}
+	if err := util.SetCreatePending(ctx, e.kube, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	instance, err := e.client.CreateDBInstance(ctx, req)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errCreateFailed)
+		return managed.ExternalCreation{}, errors.Wrap(util.CreateFailed(ctx, e.kube, cr, err), errCreateFailed)
 	}
 
 	// The Crossplane runtime will send status update back to apiserver.
@@ -306,14 +344,26 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotInstance)
 	}
-	name := meta.GetExternalName(cr)
-	description := cr.Spec.ForProvider
-	modifyReq := &redis.ModifyRedisInstanceRequest{
-		InstanceClass: description.InstanceClass,
+
+	id := cr.Status.AtProvider.DBInstanceID
+	tags, err := e.client.ListTags(ctx, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errListTagsFailed)
 	}
-	cr.Status.SetConditions(xpv1.Creating())
-	err := e.client.Update(name, modifyReq)
-	return managed.ExternalUpdate{}, err
+	err = clients.UpdateTags(cr.Spec.ForProvider.Tags, tags,
+		func(add map[string]string) error {
+			if util.Plan(ctx, "TagResources", add) {
+				return nil
+			}
+			return e.client.TagResources(ctx, id, add)
+		},
+		func(remove []string) error {
+			if util.Plan(ctx, "UntagResources", remove) {
+				return nil
+			}
+			return e.client.UntagResources(ctx, id, remove)
+		})
+	return managed.ExternalUpdate{}, errors.Wrap(err, errUpdateTagsFailed)
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -326,8 +376,12 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return nil
 	}
 
-	err := e.client.DeleteDBInstance(cr.Status.AtProvider.DBInstanceID)
-	return errors.Wrap(resource.Ignore(redis.IsErrorNotFound, err), errDeleteFailed)
+	id := cr.Status.AtProvider.DBInstanceID
+	if util.Plan(ctx, "DeleteInstance", id) {
+		return nil
+	}
+	err := e.client.DeleteDBInstance(ctx, id)
+	return errors.Wrap(resource.Ignore(errorclass.IsNotFound, err), errDeleteFailed)
 }
 
 func getConnectionDetails(password string, cr *v1alpha1.RedisInstance, instance *redis.DBInstance) managed.ConnectionDetails {
@@ -18,10 +18,20 @@ import (
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/redis"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
 
-const testName = "test"
+const (
+	testName = "test"
+
+	errTrackUsage               = "cannot track provider config usage"
+	errFmtUnsupportedCredSource = "no extraction handler registered for source: %s"
+	errExtractSecretKey         = "cannot extract from secret key when none specified"
+	errGetCredentialsSecret     = "cannot get credentials secret"
+)
 
 func TestConnector(t *testing.T) {
 	errBoom := errors.New("boom")
@@ -29,7 +39,7 @@ func TestConnector(t *testing.T) {
 	type fields struct {
 		client         client.Client
 		usage          resource.Tracker
-		newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error)
+		newRedisClient func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (redis.Client, error)
 	}
 
 	type args struct {
@@ -83,7 +93,7 @@ func TestConnector(t *testing.T) {
 					},
 				},
 			},
-			want: errors.Wrap(errBoom, errGetProviderConfig),
+			want: errors.Wrap(errors.Wrap(errBoom, util.ErrGetProviderConfig), util.ErrPrepareClientEstablishmentInfo),
 		},
 		"UnsupportedCredentialsError": {
 			reason: "An error should be returned if the selected credentials source is unsupported",
@@ -112,7 +122,7 @@ func TestConnector(t *testing.T) {
 					},
 				},
 			},
-			want: errors.Errorf(errFmtUnsupportedCredSource, "wat"),
+			want: errors.Wrap(errors.Wrap(errors.Errorf(errFmtUnsupportedCredSource, "wat"), util.ErrGetCredentials), util.ErrPrepareClientEstablishmentInfo),
 		},
 		"GetProviderError": {
 			reason: "Errors getting a Provider should be returned",
@@ -160,7 +170,7 @@ func TestConnector(t *testing.T) {
 					},
 				},
 			},
-			want: errors.New(errNoConnectionSecret),
+			want: errors.Wrap(errors.Wrap(errors.New(errExtractSecretKey), util.ErrGetCredentials), util.ErrPrepareClientEstablishmentInfo),
 		},
 		"GetConnectionSecretError": {
 			reason: "Errors getting a secret should be returned",
@@ -198,14 +208,17 @@ func TestConnector(t *testing.T) {
 					},
 				},
 			},
-			want: errors.Wrap(errBoom, errGetConnectionSecret),
+			want: errors.Wrap(errors.Wrap(errors.Wrap(errBoom, errGetCredentialsSecret), util.ErrGetCredentials), util.ErrPrepareClientEstablishmentInfo),
 		},
 		"NewRedisClientError": {
-			reason: "Errors getting a secret should be returned",
+			reason: "Errors creating a Redis client should be returned",
 			fields: fields{
 				client: &test.MockClient{
 					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
-						if t, ok := obj.(*aliv1beta1.ProviderConfig); ok {
+						switch t := obj.(type) {
+						case *corev1.Secret:
+							t.Data = map[string][]byte{"credentials": []byte("accessKeyId: ak\naccessKeySecret: sk\n")}
+						case *aliv1beta1.ProviderConfig:
 							*t = aliv1beta1.ProviderConfig{
 								Spec: aliv1beta1.ProviderConfigSpec{
 									Credentials: aliv1beta1.ProviderCredentials{
@@ -217,13 +230,14 @@ func TestConnector(t *testing.T) {
 								SecretReference: xpv1.SecretReference{
 									Name: "coolsecret",
 								},
+								Key: "credentials",
 							}
 						}
 						return nil
 					}),
 				},
 				usage: resource.TrackerFn(func(ctx context.Context, mg resource.Managed) error { return nil }),
-				newRedisClient: func(ctx context.Context, accessKeyID, accessKeySecret, region string) (redis.Client, error) {
+				newRedisClient: func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (redis.Client, error) {
 					return nil, errBoom
 				},
 			},
@@ -298,6 +312,24 @@ func TestObserve(t *testing.T) {
 				ResourceExists: true, ResourceUpToDate: true, err: nil,
 			},
 		},
+		"Tags are not up to date": {
+			mg: &v1alpha1.RedisInstance{
+				Spec: v1alpha1.RedisInstanceSpec{
+					ForProvider: v1alpha1.RedisInstanceParameters{
+						MasterUsername: testName,
+						Tags:           map[string]string{"team": "db"},
+					},
+				},
+				Status: v1alpha1.RedisInstanceStatus{
+					AtProvider: v1alpha1.RedisInstanceObservation{
+						DBInstanceID: testName,
+					},
+				},
+			},
+			want: want{
+				ResourceExists: true, ResourceUpToDate: false, err: nil,
+			},
+		},
 		"PubliclyAccessible is set": {
 			mg: &v1alpha1.RedisInstance{
 				Spec: v1alpha1.RedisInstanceSpec{
@@ -317,6 +349,19 @@ func TestObserve(t *testing.T) {
 				ResourceExists: true, ResourceUpToDate: true, err: nil,
 			},
 		},
+		"Adopted by tags": {
+			mg: &v1alpha1.RedisInstance{
+				Spec: v1alpha1.RedisInstanceSpec{
+					ForProvider: v1alpha1.RedisInstanceParameters{
+						MasterUsername: testName,
+						Tags:           map[string]string{util.TagKind: "redisinstance.redis.alibaba.crossplane.io", util.TagName: testName},
+					},
+				},
+			},
+			want: want{
+				ResourceExists: true, ResourceUpToDate: false, err: nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -336,7 +381,7 @@ func TestObserve(t *testing.T) {
 }
 
 func TestCreate(t *testing.T) {
-	e := &external{client: &fakeRedisClient{}}
+	e := &external{client: &fakeRedisClient{}, kube: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)}}
 	type want struct {
 		u   managed.ExternalCreation
 		err error
@@ -432,6 +477,12 @@ func TestUpdate(t *testing.T) {
 				Spec: v1alpha1.RedisInstanceSpec{
 					ForProvider: v1alpha1.RedisInstanceParameters{
 						InstanceClass: "class-test",
+						Tags:          map[string]string{"team": "db"},
+					},
+				},
+				Status: v1alpha1.RedisInstanceStatus{
+					AtProvider: v1alpha1.RedisInstanceObservation{
+						DBInstanceID: testName,
 					},
 				},
 			},
@@ -626,7 +677,7 @@ func TestGetConnectionDetails(t *testing.T) {
 
 type fakeRedisClient struct{}
 
-func (c *fakeRedisClient) DescribeDBInstance(id string) (*redis.DBInstance, error) {
+func (c *fakeRedisClient) DescribeDBInstance(_ context.Context, id string) (*redis.DBInstance, error) {
 	if id != testName {
 		return nil, errors.New("DescribeRedisInstance: client doesn't work")
 	}
@@ -636,7 +687,14 @@ func (c *fakeRedisClient) DescribeDBInstance(id string) (*redis.DBInstance, erro
 	}, nil
 }
 
-func (c *fakeRedisClient) CreateDBInstance(req *redis.CreateRedisInstanceRequest) (*redis.DBInstance, error) {
+func (c *fakeRedisClient) FindDBInstances(_ context.Context, name string, tags map[string]string) ([]*redis.DBInstance, error) {
+	if tags[util.TagName] != testName {
+		return nil, nil
+	}
+	return []*redis.DBInstance{{ID: testName}}, nil
+}
+
+func (c *fakeRedisClient) CreateDBInstance(_ context.Context, req *redis.CreateRedisInstanceRequest) (*redis.DBInstance, error) {
 	if req.Name != testName {
 		return nil, errors.New("CreateRedisInstance: client doesn't work")
 	}
@@ -649,37 +707,58 @@ func (c *fakeRedisClient) CreateDBInstance(req *redis.CreateRedisInstanceRequest
 	}, nil
 }
 
-func (c *fakeRedisClient) CreateAccount(id, user, pw string) error {
+func (c *fakeRedisClient) CreateAccount(_ context.Context, id, user, pw string) error {
 	if id != testName {
 		return errors.New("CreateAccount: client doesn't work")
 	}
 	return nil
 }
 
-func (c *fakeRedisClient) DeleteDBInstance(id string) error {
+func (c *fakeRedisClient) DeleteDBInstance(_ context.Context, id string) error {
 	if id != testName {
 		return errors.New("DeleteRedisInstance: client doesn't work")
 	}
 	return nil
 }
 
-func (c *fakeRedisClient) AllocateInstancePublicConnection(id string, port int) (string, error) {
+func (c *fakeRedisClient) AllocateInstancePublicConnection(_ context.Context, id string, port int) (string, error) {
 	if id != testName {
 		return "nil", errors.New("AllocateInstancePublicConnection: client doesn't work")
 	}
 	return "", nil
 }
 
-func (c *fakeRedisClient) ModifyDBInstanceConnectionString(id string, port int) (string, error) {
+func (c *fakeRedisClient) ModifyDBInstanceConnectionString(_ context.Context, id string, port int) (string, error) {
 	if id != testName {
 		return "nil", errors.New("ModifyDBInstanceConnectionString: client doesn't work")
 	}
 	return "", nil
 }
 
-func (c *fakeRedisClient) Update(id string, req *redis.ModifyRedisInstanceRequest) error {
+func (c *fakeRedisClient) Update(_ context.Context, id string, req *redis.ModifyRedisInstanceRequest) error {
 	if id != testName {
 		return errors.New("Update: client doesn't work")
 	}
 	return nil
 }
+
+func (c *fakeRedisClient) ListTags(_ context.Context, id string) (map[string]string, error) {
+	if id != testName {
+		return nil, errors.New("ListTags: client doesn't work")
+	}
+	return map[string]string{}, nil
+}
+
+func (c *fakeRedisClient) TagResources(_ context.Context, id string, tags map[string]string) error {
+	if id != testName {
+		return errors.New("TagResources: client doesn't work")
+	}
+	return nil
+}
+
+func (c *fakeRedisClient) UntagResources(_ context.Context, id string, keys []string) error {
+	if id != testName {
+		return errors.New("UntagResources: client doesn't work")
+	}
+	return nil
+}
@@ -19,18 +19,22 @@ package slb
 import (
 	"context"
 
+	"github.com/alibabacloud-go/tea/tea"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slbclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/slb"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -40,31 +44,44 @@ const (
 	errFailedToCreateSLB   = "failed to create SLB"
 	errFailedToDeleteSLB   = "failed to delete SLB"
 	errFailedToDescribeSLB = "failed to describe SLB"
+	errFailedToRecoverSLB  = "failed to recover the created SLB"
+	errFailedToAdoptSLB    = "failed to adopt SLB"
+	errFailedToListTags    = "failed to list the tags of SLB"
+	errFailedToUpdateTags  = "failed to update the tags of SLB"
 	errNotCLB              = "managed resource is not a CLB custom resource"
 )
 
 // SetupCLB adds a controller that reconciles CLB
-func SetupCLB(mgr ctrl.Manager, l logging.Logger) error {
+func SetupCLB(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(v1alpha1.CLBGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(v1alpha1.CLBGroupVersionKind.Group)).
 		For(&v1alpha1.CLB{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(v1alpha1.CLBGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&Connector{
+			managed.WithInitializers(
+				managed.NewDefaultProviderConfig(mgr.GetClient()),
+				managed.NewNameAsExternalName(mgr.GetClient()),
+				util.NewTagger(mgr.GetClient(), v1alpha1.CLBGroupKind)),
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&Connector{
 				Client:      mgr.GetClient(),
 				Usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &aliv1beta1.ProviderConfigUsage{}),
 				NewClientFn: slbclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // Connector stores Kubernetes client and SLB client
 type Connector struct {
 	Client      client.Client
 	Usage       resource.Tracker
-	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string) (*slbclient.SDKClient, error)
+	NewClientFn func(ctx context.Context, endpoint, accessKeyID, accessKeySecret, stsToken string, transport *clients.TransportConfig, caller *apicall.Caller) (*slbclient.SDKClient, error)
 }
 
 // Connect initials cloud resource client
@@ -79,13 +96,19 @@ func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	client, err := c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken)
-	return &External{ExternalClient: client}, errors.Wrap(err, errCreateClient)
+	client, err := util.GetOrCreateClient(info, util.ServiceSLB, func() (interface{}, error) {
+		return c.NewClientFn(ctx, info.Endpoint, info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateClient)
+	}
+	return &External{ExternalClient: client.(*slbclient.SDKClient), Kube: c.Client}, nil
 }
 
 // External includes external SLB client
 type External struct {
 	ExternalClient slbclient.ClientInterface
+	Kube           client.Client
 }
 
 // Observe managed resource CLB
@@ -101,7 +124,42 @@ func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		}, nil
 	}
 
-	slb, err := e.ExternalClient.DescribeLoadBalancers(cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID, cr.Spec.ForProvider.VpcID,
+	find := func(ctx context.Context, name string, tags map[string]string) ([]string, error) {
+		return e.ExternalClient.FindLoadBalancers(ctx, cr.Spec.ForProvider.Region, name, tags)
+	}
+	id := cr.Status.AtProvider.LoadBalancerID
+	if id == nil && util.IsObserveOnly(cr) {
+		// An existing load balancer is observed by its ID.
+		id = tea.String(meta.GetExternalName(cr))
+	}
+	if id == nil && util.IsCreatePending(cr) {
+		// The load balancer may have been created without its ID being
+		// recorded.
+		recovered, err := util.RecoverCreated(ctx, cr, cr.Name, find)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToRecoverSLB)
+		}
+		if recovered != "" {
+			id = &recovered
+		}
+	}
+	if id == nil && !util.IsObserveOnly(cr) {
+		// The ID of an existing load balancer is lost if the status isn't
+		// restored, e.g. by a backup, in which case the load balancer with
+		// the crossplane tags of the resource is adopted.
+		adopted, err := util.Adopt(ctx, cr, find)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToAdoptSLB)
+		}
+		if adopted != "" {
+			id = &adopted
+		}
+	}
+	if id == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	slb, err := e.ExternalClient.DescribeLoadBalancers(ctx, cr.Spec.ForProvider.Region, id, cr.Spec.ForProvider.VpcID,
 		cr.Spec.ForProvider.VSwitchID)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errFailedToDescribeSLB)
@@ -110,16 +168,31 @@ func (e *External) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 	}
 
+	if err := util.UnsetCreatePending(ctx, e.Kube, cr); err != nil {
+		return managed.ExternalObservation{}, err
+	}
 	cr.Status.AtProvider = slbclient.GenerateObservation(slb)
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	slbclient.LateInitialize(&cr.Spec.ForProvider, slb)
+
 	var upToDate = slbclient.IsUpdateToDate(cr, slb)
+	if upToDate {
+		tags, err := e.ExternalClient.ListLoadBalancerTags(ctx, cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errFailedToListTags)
+		}
+		upToDate = clients.TagsUpToDate(cr.Spec.ForProvider.Tags, tags)
+	}
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: GetConnectionDetails(cr),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       GetConnectionDetails(cr),
 	}, nil
 }
 
@@ -130,11 +203,23 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotCLB)
 	}
 	cr.SetConditions(xpv1.Creating())
-	res, err := e.ExternalClient.CreateLoadBalancer(cr.Name, cr.Spec.ForProvider)
+	// The request retried after a lost response returns the same load
+	// balancer, unless the client token is specified.
+	params := *cr.Spec.ForProvider.DeepCopy()
+	if token := util.ClientToken(cr); params.ClientToken == nil && token != "" {
+		params.ClientToken = tea.String(token)
+	}
+	if util.Plan(ctx, "CreateLoadBalancer", slbclient.MakeCreateLoadBalancerRequest(cr.Name, params)) {
+		return managed.ExternalCreation{}, nil
+	}
+	if err := util.SetCreatePending(ctx, e.Kube, cr); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	res, err := e.ExternalClient.CreateLoadBalancer(ctx, cr.Name, params)
 	if err != nil {
-		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToCreateSLB)
+		return managed.ExternalCreation{}, errors.Wrap(util.CreateFailed(ctx, e.Kube, cr, err), errFailedToCreateSLB)
 	}
-	lb, err := e.ExternalClient.DescribeLoadBalancers(cr.Spec.ForProvider.Region, res.Body.LoadBalancerId,
+	lb, err := e.ExternalClient.DescribeLoadBalancers(ctx, cr.Spec.ForProvider.Region, res.Body.LoadBalancerId,
 		cr.Spec.ForProvider.VpcID, cr.Spec.ForProvider.VSwitchID)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errFailedToDescribeSLB)
@@ -145,7 +230,32 @@ func (e *External) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 
 // Update managed resource CLB
 func (e *External) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
-	return managed.ExternalUpdate{}, nil
+	cr, ok := mg.(*v1alpha1.CLB)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotCLB)
+	}
+	region, id := cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID
+	if id == nil {
+		return managed.ExternalUpdate{}, nil
+	}
+	tags, err := e.ExternalClient.ListLoadBalancerTags(ctx, region, id)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToListTags)
+	}
+	err = clients.UpdateTags(cr.Spec.ForProvider.Tags, tags,
+		func(add map[string]string) error {
+			if util.Plan(ctx, "TagResources", add) {
+				return nil
+			}
+			return e.ExternalClient.TagLoadBalancer(ctx, region, id, add)
+		},
+		func(remove []string) error {
+			if util.Plan(ctx, "UntagResources", remove) {
+				return nil
+			}
+			return e.ExternalClient.UntagLoadBalancer(ctx, region, id, remove)
+		})
+	return managed.ExternalUpdate{}, errors.Wrap(err, errFailedToUpdateTags)
 }
 
 // Delete managed resource CLB
@@ -155,7 +265,10 @@ func (e *External) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotCLB)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.ExternalClient.DeleteLoadBalancer(cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID); err != nil {
+	if util.Plan(ctx, "DeleteLoadBalancer", cr.Status.AtProvider.LoadBalancerID) {
+		return nil
+	}
+	if err := e.ExternalClient.DeleteLoadBalancer(ctx, cr.Spec.ForProvider.Region, cr.Status.AtProvider.LoadBalancerID); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errFailedToDeleteSLB)
 	}
 	return nil
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slb
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/alibabacloud-go/slb-20140515/v2/client"
+	"github.com/alibabacloud-go/tea/tea"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
+)
+
+type fakeSDKClient struct {
+	described bool
+}
+
+func (c *fakeSDKClient) DescribeLoadBalancers(_ context.Context, region, loadBalancerID, vpcID, vSwitchID *string) (*sdk.DescribeLoadBalancersResponse, error) {
+	c.described = true
+	body := &sdk.DescribeLoadBalancersResponseBody{
+		LoadBalancers: &sdk.DescribeLoadBalancersResponseBodyLoadBalancers{},
+		TotalCount:    tea.Int32(0),
+	}
+	if tea.StringValue(loadBalancerID) == "lb-1" {
+		body.LoadBalancers.LoadBalancer = []*sdk.DescribeLoadBalancersResponseBodyLoadBalancersLoadBalancer{{
+			LoadBalancerId: tea.String("lb-1"),
+			RegionId:       region,
+			Address:        tea.String("192.168.0.1"),
+		}}
+		body.TotalCount = tea.Int32(1)
+	}
+	return &sdk.DescribeLoadBalancersResponse{Body: body}, nil
+}
+
+func (c *fakeSDKClient) FindLoadBalancers(_ context.Context, region *string, name string, tags map[string]string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *fakeSDKClient) CreateLoadBalancer(_ context.Context, name string, clb v1alpha1.CLBParameter) (*sdk.CreateLoadBalancerResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeSDKClient) DeleteLoadBalancer(_ context.Context, region, loadBalancerID *string) error {
+	return nil
+}
+
+func (c *fakeSDKClient) ListLoadBalancerTags(_ context.Context, region, loadBalancerID *string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (c *fakeSDKClient) TagLoadBalancer(_ context.Context, region, loadBalancerID *string, tags map[string]string) error {
+	return nil
+}
+
+func (c *fakeSDKClient) UntagLoadBalancer(_ context.Context, region, loadBalancerID *string, keys []string) error {
+	return nil
+}
+
+func TestObserveObserveOnly(t *testing.T) {
+	var ctx = context.Background()
+
+	cases := map[string]struct {
+		reason      string
+		observeOnly bool
+		want        *string
+	}{
+		"ObserveOnly": {
+			reason:      "An existing load balancer should be observed by the ID in the external name",
+			observeOnly: true,
+			want:        tea.String("lb-1"),
+		},
+		"Managed": {
+			reason: "A load balancer without a recorded ID should not be looked up by the external name",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.CLB{}
+			cr.Spec.ForProvider.Region = tea.String("cn-hangzhou")
+			meta.SetExternalName(cr, "lb-1")
+			if tc.observeOnly {
+				meta.AddAnnotations(cr, map[string]string{util.AnnotationManagementPolicy: util.ManagementPolicyObserveOnly})
+			}
+			external := &External{ExternalClient: &fakeSDKClient{}}
+			got, err := external.Observe(ctx, cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): %v\n", tc.reason, err)
+			}
+			if got.ResourceExists != (tc.want != nil) {
+				t.Errorf("\n%s\ne.Observe(...): want exists %t, got %t\n", tc.reason, tc.want != nil, got.ResourceExists)
+			}
+			if tea.StringValue(cr.Status.AtProvider.LoadBalancerID) != tea.StringValue(tc.want) {
+				t.Errorf("\n%s\ne.Observe(...): want load balancer ID %q, got %q\n", tc.reason, tea.StringValue(tc.want), tea.StringValue(cr.Status.AtProvider.LoadBalancerID))
+			}
+		})
+	}
+}
+
+func TestObserveCreatePending(t *testing.T) {
+	var ctx = context.Background()
+
+	cr := &v1alpha1.CLB{}
+	cr.Spec.ForProvider.Region = tea.String("cn-hangzhou")
+	meta.SetExternalName(cr, "lb")
+	meta.AddAnnotations(cr, map[string]string{util.AnnotationCreatePending: "2021-01-01T00:00:00Z"})
+	client := &fakeSDKClient{}
+	external := &External{ExternalClient: client}
+	got, err := external.Observe(ctx, cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): %v", err)
+	}
+	if got.ResourceExists {
+		t.Error("e.Observe(...): the load balancer which isn't found should not exist, so that it is created again")
+	}
+	if client.described {
+		t.Error("e.Observe(...): the load balancers should not be described without an ID")
+	}
+}
@@ -23,7 +23,6 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -33,6 +32,9 @@ import (
 
 	aliv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -45,27 +47,32 @@ const (
 )
 
 // SetupIndex adds a controller that reconciles Index.
-func SetupIndex(mgr ctrl.Manager, l logging.Logger) error {
+func SetupIndex(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(aliv1alpha1.IndexGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(aliv1alpha1.IndexGroupVersionKind.Group)).
 		For(&aliv1alpha1.LogstoreIndex{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(aliv1alpha1.IndexGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&indexConnector{
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&indexConnector{
 				client:      mgr.GetClient(),
 				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 				NewClientFn: slsclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // indexConnector stores Kubernetes client and SLS client
 type indexConnector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 // Connect initials cloud resource client
@@ -80,9 +87,14 @@ func (c *indexConnector) Connect(ctx context.Context, mg resource.Managed) (mana
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
-		info.SecurityToken, info.Region)
-	return &external{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(info, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
+			info.SecurityToken, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &indexExternal{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 // indexExternal includes external SLS client
@@ -103,9 +115,9 @@ func (e *indexExternal) Observe(ctx context.Context, mg resource.Managed) (manag
 		}, nil
 	}
 
-	index, err := e.client.DescribeIndex(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName)
+	index, err := e.client.DescribeIndex(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName)
 	if err != nil {
-		if slsclient.IsIndexNotFoundError(err) {
+		if errorclass.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeIndex)
@@ -131,8 +143,12 @@ func (e *indexExternal) Create(ctx context.Context, mg resource.Managed) (manage
 		return managed.ExternalCreation{}, errors.New(errNotIndex)
 	}
 	cr.SetConditions(xpv1.Creating())
+	if util.IsDryRun(ctx) {
+		util.Plan(ctx, "CreateIndex", slsclient.MakeIndex(cr.Spec.ForProvider))
+		return managed.ExternalCreation{}, nil
+	}
 
-	err := e.client.CreateIndex(cr.Spec.ForProvider)
+	err := e.client.CreateIndex(ctx, cr.Spec.ForProvider)
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateIndex)
 }
 
@@ -149,7 +165,10 @@ func (e *indexExternal) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotIndex)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.DeleteIndex(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName); err != nil {
+	if util.Plan(ctx, "DeleteIndex", cr.Spec.ForProvider.LogstoreName) {
+		return nil
+	}
+	if err := e.client.DeleteIndex(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.LogstoreName); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errDeleteIndex)
 	}
 	return nil
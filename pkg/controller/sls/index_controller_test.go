@@ -51,7 +51,7 @@ var validIndexCR = &slsv1alpha1.LogstoreIndex{
 	},
 }
 
-func (c *fakeSDKClient) DescribeIndex(project, logstore *string) (*sdk.Index, error) {
+func (c *fakeSDKClient) DescribeIndex(_ context.Context, project, logstore *string) (*sdk.Index, error) {
 	switch *project {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeLogstoreIndexNotExist, HTTPCode: int32(0)}
@@ -62,15 +62,15 @@ func (c *fakeSDKClient) DescribeIndex(project, logstore *string) (*sdk.Index, er
 	}
 }
 
-func (c *fakeSDKClient) CreateIndex(param slsv1alpha1.LogstoreIndexParameters) error {
+func (c *fakeSDKClient) CreateIndex(_ context.Context, param slsv1alpha1.LogstoreIndexParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateIndex(project, logstore *string, index *sdk.Index) error {
+func (c *fakeSDKClient) UpdateIndex(_ context.Context, project, logstore *string, index *sdk.Index) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteIndex(project, logstore *string) error {
+func (c *fakeSDKClient) DeleteIndex(_ context.Context, project, logstore *string) error {
 	return nil
 }
 
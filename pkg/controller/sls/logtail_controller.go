@@ -21,7 +21,6 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -31,6 +30,9 @@ import (
 
 	aliv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -43,27 +45,32 @@ const (
 )
 
 // SetupLogtail adds a controller that reconciles Logtail.
-func SetupLogtail(mgr ctrl.Manager, l logging.Logger) error {
+func SetupLogtail(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(aliv1alpha1.LogtailGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(aliv1alpha1.LogtailGroupVersionKind.Group)).
 		For(&aliv1alpha1.Logtail{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(aliv1alpha1.LogtailGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&logtailConnector{
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&logtailConnector{
 				client:      mgr.GetClient(),
 				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 				NewClientFn: slsclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // logtailConnector stores Kubernetes client and SLS client
 type logtailConnector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 // Connect initials cloud resource client
@@ -78,9 +85,14 @@ func (c *logtailConnector) Connect(ctx context.Context, mg resource.Managed) (ma
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
-		info.SecurityToken, info.Region)
-	return &external{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(info, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
+			info.SecurityToken, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &logtailExternal{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 // logtailExternal includes external SLS client
@@ -101,9 +113,9 @@ func (e *logtailExternal) Observe(ctx context.Context, mg resource.Managed) (man
 		}, nil
 	}
 
-	logtail, err := e.client.DescribeConfig(cr.Spec.ForProvider.OutputDetail.ProjectName, meta.GetExternalName(mg))
+	logtail, err := e.client.DescribeConfig(ctx, cr.Spec.ForProvider.OutputDetail.ProjectName, meta.GetExternalName(mg))
 	if err != nil {
-		if slsclient.IsLogtailNotFoundError(err) {
+		if errorclass.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeLogtail)
@@ -129,8 +141,16 @@ func (e *logtailExternal) Create(ctx context.Context, mg resource.Managed) (mana
 		return managed.ExternalCreation{}, errors.New(errNotLogtail)
 	}
 	cr.SetConditions(xpv1.Creating())
+	if util.IsDryRun(ctx) {
+		config, err := slsclient.MakeLogConfig(meta.GetExternalName(mg), cr.Spec.ForProvider)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errCreateLogtail)
+		}
+		util.Plan(ctx, "CreateConfig", config)
+		return managed.ExternalCreation{}, nil
+	}
 
-	err := e.client.CreateConfig(meta.GetExternalName(mg), cr.Spec.ForProvider)
+	err := e.client.CreateConfig(ctx, meta.GetExternalName(mg), cr.Spec.ForProvider)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateLogtail)
 	}
@@ -151,7 +171,10 @@ func (e *logtailExternal) Delete(ctx context.Context, mg resource.Managed) error
 		return errors.New(errNotLogtail)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.DeleteConfig(cr.Spec.ForProvider.OutputDetail.ProjectName, meta.GetExternalName(mg)); err != nil {
+	if util.Plan(ctx, "DeleteConfig", meta.GetExternalName(mg)) {
+		return nil
+	}
+	if err := e.client.DeleteConfig(ctx, cr.Spec.ForProvider.OutputDetail.ProjectName, meta.GetExternalName(mg)); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errDeleteLogtail)
 	}
 	return nil
@@ -49,7 +49,7 @@ var validLogtailCR = &slsv1alpha1.Logtail{
 	},
 }
 
-func (c *fakeSDKClient) DescribeConfig(logtail string, config string) (*sdk.LogConfig, error) {
+func (c *fakeSDKClient) DescribeConfig(_ context.Context, logtail string, config string) (*sdk.LogConfig, error) {
 	switch config {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeLogtailNotExist, HTTPCode: int32(0)}
@@ -63,15 +63,15 @@ func (c *fakeSDKClient) DescribeConfig(logtail string, config string) (*sdk.LogC
 	}
 }
 
-func (c *fakeSDKClient) CreateConfig(name string, config slsv1alpha1.LogtailParameters) error {
+func (c *fakeSDKClient) CreateConfig(_ context.Context, name string, config slsv1alpha1.LogtailParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateConfig(logtail string, config *sdk.LogConfig) error {
+func (c *fakeSDKClient) UpdateConfig(_ context.Context, logtail string, config *sdk.LogConfig) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteConfig(logtail string, config string) error {
+func (c *fakeSDKClient) DeleteConfig(_ context.Context, logtail string, config string) error {
 	return nil
 }
 
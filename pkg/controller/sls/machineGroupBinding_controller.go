@@ -22,7 +22,6 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -32,6 +31,9 @@ import (
 
 	aliv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -44,27 +46,32 @@ const (
 )
 
 // SetupMachineGroupBinding adds a controller that reconciles MachineGroupBinding
-func SetupMachineGroupBinding(mgr ctrl.Manager, l logging.Logger) error {
+func SetupMachineGroupBinding(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(aliv1alpha1.MachineGroupBindingGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(aliv1alpha1.MachineGroupBindingGroupVersionKind.Group)).
 		For(&aliv1alpha1.MachineGroupBinding{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(aliv1alpha1.MachineGroupBindingGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&machineGroupBindingConnector{
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&machineGroupBindingConnector{
 				client:      mgr.GetClient(),
 				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 				NewClientFn: slsclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // machineGroupBindingConnector stores Kubernetes client and SLS client
 type machineGroupBindingConnector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 // Connect initials cloud resource client
@@ -79,9 +86,14 @@ func (c *machineGroupBindingConnector) Connect(ctx context.Context, mg resource.
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
-		info.SecurityToken, info.Region)
-	return &machineGroupBindingExternal{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(info, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
+			info.SecurityToken, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &machineGroupBindingExternal{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 // machineGroupBindingExternal includes external SLS client
@@ -102,7 +114,7 @@ func (e *machineGroupBindingExternal) Observe(ctx context.Context, mg resource.M
 		}, nil
 	}
 
-	configs, err := e.client.GetAppliedConfigs(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName)
+	configs, err := e.client.GetAppliedConfigs(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName)
 	if err != nil {
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeMachineGroupBinding)
 	}
@@ -126,8 +138,11 @@ func (e *machineGroupBindingExternal) Create(ctx context.Context, mg resource.Ma
 		return managed.ExternalCreation{}, errors.New(errNotMachineGroupBinding)
 	}
 	cr.SetConditions(xpv1.Creating())
+	if util.Plan(ctx, "ApplyConfigToMachineGroup", cr.Spec.ForProvider) {
+		return managed.ExternalCreation{}, nil
+	}
 
-	err := e.client.ApplyConfigToMachineGroup(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName,
+	err := e.client.ApplyConfigToMachineGroup(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName,
 		cr.Spec.ForProvider.ConfigName)
 	if err != nil {
 		return managed.ExternalCreation{}, errors.Wrap(err, errCreateMachineGroupBinding)
@@ -149,8 +164,11 @@ func (e *machineGroupBindingExternal) Delete(ctx context.Context, mg resource.Ma
 		return errors.New(errNotMachineGroupBinding)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.RemoveConfigFromMachineGroup(cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName,
-		cr.Spec.ForProvider.ConfigName); err != nil {
+	if util.Plan(ctx, "RemoveConfigFromMachineGroup", cr.Spec.ForProvider) {
+		return nil
+	}
+	if err := e.client.RemoveConfigFromMachineGroup(ctx, cr.Spec.ForProvider.ProjectName, cr.Spec.ForProvider.GroupName,
+		cr.Spec.ForProvider.ConfigName); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errDeleteMachineGroupBinding)
 	}
 	return nil
@@ -60,7 +60,7 @@ var (
 	}
 )
 
-func (c *fakeSDKClient) GetAppliedConfigs(projectName *string, groupName *string) ([]string, error) {
+func (c *fakeSDKClient) GetAppliedConfigs(_ context.Context, projectName *string, groupName *string) ([]string, error) {
 	switch *projectName {
 	case mgbProject:
 		return []string{mgbConfig}, nil
@@ -73,11 +73,11 @@ func (c *fakeSDKClient) GetAppliedConfigs(projectName *string, groupName *string
 	}
 }
 
-func (c *fakeSDKClient) ApplyConfigToMachineGroup(projectName, groupName, confName *string) error {
+func (c *fakeSDKClient) ApplyConfigToMachineGroup(_ context.Context, projectName, groupName, confName *string) error {
 	return nil
 }
 
-func (c *fakeSDKClient) RemoveConfigFromMachineGroup(projectName, groupName, confName *string) error {
+func (c *fakeSDKClient) RemoveConfigFromMachineGroup(_ context.Context, projectName, groupName, confName *string) error {
 	return nil
 }
 
@@ -21,7 +21,6 @@ import (
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -31,6 +30,9 @@ import (
 
 	aliv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -43,27 +45,32 @@ const (
 )
 
 // SetupMachineGroup adds a controller that reconciles MachineGroup.
-func SetupMachineGroup(mgr ctrl.Manager, l logging.Logger) error {
+func SetupMachineGroup(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(aliv1alpha1.MachineGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(aliv1alpha1.MachineGroupVersionKind.Group)).
 		For(&aliv1alpha1.MachineGroup{}).
-		Complete(managed.NewReconciler(mgr,
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
 			resource.ManagedKind(aliv1alpha1.MachineGroupVersionKind),
-			managed.WithLogger(l.WithValues("controller", name)),
-			managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-			managed.WithExternalConnecter(&machineGroupConnector{
+			managed.WithLogger(o.Logger.WithValues("controller", name)),
+			managed.WithPollInterval(o.PollInterval),
+			managed.WithRecorder(recorder),
+			managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&machineGroupConnector{
 				client:      mgr.GetClient(),
 				usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 				NewClientFn: slsclient.NewClient,
-			})))
+			}), o.DryRun, recorder))))))))
 }
 
 // machineGroupConnector stores Kubernetes client and SLS client
 type machineGroupConnector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 // Connect initials cloud resource client
@@ -78,9 +85,14 @@ func (c *machineGroupConnector) Connect(ctx context.Context, mg resource.Managed
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
-		info.SecurityToken, info.Region)
-	return &machineGroupExternal{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(info, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(info.AccessKeyID, info.AccessKeySecret,
+			info.SecurityToken, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &machineGroupExternal{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 // machineGroupExternal includes external SLS client
@@ -101,9 +113,9 @@ func (e *machineGroupExternal) Observe(ctx context.Context, mg resource.Managed)
 		}, nil
 	}
 
-	machineGroup, err := e.client.DescribeMachineGroup(cr.Spec.ForProvider.Project, meta.GetExternalName(mg))
+	machineGroup, err := e.client.DescribeMachineGroup(ctx, cr.Spec.ForProvider.Project, meta.GetExternalName(mg))
 	if err != nil {
-		if slsclient.IsMachineGroupNotFoundError(err) {
+		if errorclass.IsNotFound(err) {
 			return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}, nil
 		}
 		return managed.ExternalObservation{ResourceExists: false}, errors.Wrap(err, errDescribeMachineGroup)
@@ -129,8 +141,12 @@ func (e *machineGroupExternal) Create(ctx context.Context, mg resource.Managed)
 		return managed.ExternalCreation{}, errors.New(errNotMachineGroup)
 	}
 	cr.SetConditions(xpv1.Creating())
+	if util.IsDryRun(ctx) {
+		util.Plan(ctx, "CreateMachineGroup", slsclient.MakeMachineGroup(meta.GetExternalName(mg), cr.Spec.ForProvider))
+		return managed.ExternalCreation{}, nil
+	}
 
-	err := e.client.CreateMachineGroup(meta.GetExternalName(mg), cr.Spec.ForProvider)
+	err := e.client.CreateMachineGroup(ctx, meta.GetExternalName(mg), cr.Spec.ForProvider)
 	return managed.ExternalCreation{}, errors.Wrap(err, errCreateMachineGroup)
 }
 
@@ -147,7 +163,10 @@ func (e *machineGroupExternal) Delete(ctx context.Context, mg resource.Managed)
 		return errors.New(errNotMachineGroup)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	if err := e.client.DeleteMachineGroup(cr.Spec.ForProvider.Project, meta.GetExternalName(mg)); err != nil {
+	if util.Plan(ctx, "DeleteMachineGroup", meta.GetExternalName(mg)) {
+		return nil
+	}
+	if err := e.client.DeleteMachineGroup(ctx, cr.Spec.ForProvider.Project, meta.GetExternalName(mg)); err != nil && !errorclass.IsNotFound(err) {
 		return errors.Wrap(err, errDeleteMachineGroup)
 	}
 	return nil
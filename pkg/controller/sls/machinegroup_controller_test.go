@@ -58,7 +58,7 @@ var validMachineGroupCR = &slsv1alpha1.MachineGroup{
 	}},
 }
 
-func (c *fakeSDKClient) DescribeMachineGroup(project *string, name string) (*sdk.MachineGroup, error) {
+func (c *fakeSDKClient) DescribeMachineGroup(_ context.Context, project *string, name string) (*sdk.MachineGroup, error) {
 	switch name {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeMachineGroupNotExist, HTTPCode: int32(0)}
@@ -78,15 +78,15 @@ func (c *fakeSDKClient) DescribeMachineGroup(project *string, name string) (*sdk
 	}
 }
 
-func (c *fakeSDKClient) CreateMachineGroup(name string, param slsv1alpha1.MachineGroupParameters) error {
+func (c *fakeSDKClient) CreateMachineGroup(_ context.Context, name string, param slsv1alpha1.MachineGroupParameters) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateMachineGroup(project, logstore *string, machineGroup *sdk.MachineGroup) error {
+func (c *fakeSDKClient) UpdateMachineGroup(_ context.Context, project, logstore *string, machineGroup *sdk.MachineGroup) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteMachineGroup(project *string, logstore string) error {
+func (c *fakeSDKClient) DeleteMachineGroup(_ context.Context, project *string, logstore string) error {
 	return nil
 }
 
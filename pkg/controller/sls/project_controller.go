@@ -24,7 +24,6 @@ import (
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -34,6 +33,9 @@ import (
 
 	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -41,28 +43,37 @@ import (
 const errNotProject = "managed resource is not a SLS project custom resource"
 
 // SetupProject adds a controller that reconciles SLSProjects.
-func SetupProject(mgr ctrl.Manager, l logging.Logger) error {
+func SetupProject(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(slsv1alpha1.ProjectGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&connector{
+		managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&connector{
 			client:      mgr.GetClient(),
 			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 			NewClientFn: slsclient.NewClient,
-		}),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		}), o.DryRun, recorder)))),
+		managed.WithInitializers(
+			managed.NewDefaultProviderConfig(mgr.GetClient()),
+			managed.NewNameAsExternalName(mgr.GetClient()),
+			util.NewTagger(mgr.GetClient(), slsv1alpha1.ProjectGroupKind)),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(slsv1alpha1.ProjectGroupVersionKind.Group)).
 		For(&slsv1alpha1.Project{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(slsv1alpha1.ProjectGroupVersionKind), options...))
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
+			resource.ManagedKind(slsv1alpha1.ProjectGroupVersionKind), options...))))
 }
 
 type connector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
@@ -76,9 +87,14 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(clientEstablishmentInfo.AccessKeyID, clientEstablishmentInfo.AccessKeySecret,
-		clientEstablishmentInfo.SecurityToken, clientEstablishmentInfo.Region)
-	return &external{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(clientEstablishmentInfo, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(clientEstablishmentInfo.AccessKeyID, clientEstablishmentInfo.AccessKeySecret,
+			clientEstablishmentInfo.SecurityToken, clientEstablishmentInfo.Endpoint, clientEstablishmentInfo.Transport, clientEstablishmentInfo.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &external{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 type external struct {
@@ -91,8 +107,8 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotProject)
 	}
 	projectName := meta.GetExternalName(cr)
-	project, err := e.client.Describe(projectName)
-	if slsclient.IsNotFoundError(err) {
+	project, err := e.client.Describe(ctx, projectName)
+	if errorclass.IsNotFound(err) {
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
@@ -103,8 +119,13 @@ func (e *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	cr.Status.AtProvider = slsclient.GenerateObservation(project)
+	tags, err := e.client.ListTags(ctx, projectName)
+	if err != nil {
+		return managed.ExternalObservation{}, err
+	}
 	var upToDate bool
-	if (projectName == project.Name) && (cr.Spec.ForProvider.Description == project.Description) {
+	if (projectName == project.Name) && (cr.Spec.ForProvider.Description == project.Description) &&
+		clients.TagsUpToDate(cr.Spec.ForProvider.Tags, tags) {
 		upToDate = true
 		cr.SetConditions(xpv1.Available())
 	}
@@ -123,7 +144,10 @@ func (e *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 	name := meta.GetExternalName(cr)
 	description := cr.Spec.ForProvider.Description
-	project, err := e.client.Create(name, description)
+	if util.Plan(ctx, "CreateProject", map[string]string{"projectName": name, "description": description}) {
+		return managed.ExternalCreation{}, nil
+	}
+	project, err := e.client.Create(ctx, name, description)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
@@ -137,15 +161,38 @@ func (e *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 	}
 	name := meta.GetExternalName(cr)
 	description := cr.Spec.ForProvider.Description
-	got, err := e.client.Update(name, description)
+	got, err := e.client.Describe(ctx, name)
 	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
+	if got.Description != description && !util.Plan(ctx, "UpdateProject", map[string]string{"projectName": name, "description": description}) {
+		got, err := e.client.Update(ctx, name, description)
+		if err != nil {
+			return managed.ExternalUpdate{}, err
+		}
+		if got.Description != description {
+			return managed.ExternalUpdate{}, nil
+		}
+	}
 
-	if got.Description != description {
+	tags, err := e.client.ListTags(ctx, name)
+	if err != nil {
 		return managed.ExternalUpdate{}, err
 	}
-	return managed.ExternalUpdate{}, nil
+	err = clients.UpdateTags(cr.Spec.ForProvider.Tags, tags,
+		func(add map[string]string) error {
+			if util.Plan(ctx, "TagResources", add) {
+				return nil
+			}
+			return e.client.TagProject(ctx, name, add)
+		},
+		func(remove []string) error {
+			if util.Plan(ctx, "UnTagResources", remove) {
+				return nil
+			}
+			return e.client.UntagProject(ctx, name, remove)
+		})
+	return managed.ExternalUpdate{}, err
 }
 
 func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -154,7 +201,10 @@ func (e *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotProject)
 	}
 	name := meta.GetExternalName(cr)
-	if err := e.client.Delete(name); err != nil && !slsclient.IsNotFoundError(err) {
+	if util.Plan(ctx, "DeleteProject", name) {
+		return nil
+	}
+	if err := e.client.Delete(ctx, name); err != nil && !errorclass.IsNotFound(err) {
 		return err
 	}
 	return nil
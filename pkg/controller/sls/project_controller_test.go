@@ -56,7 +56,7 @@ type fakeSDKClient struct {
 }
 
 // Describe describes SLS project
-func (c *fakeSDKClient) Describe(name string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Describe(_ context.Context, name string) (*sdk.LogProject, error) {
 	switch name {
 	case "":
 		return nil, sdk.Error{Code: slsclient.ErrCodeProjectNotExist, HTTPCode: int32(0)}
@@ -73,17 +73,32 @@ func (c *fakeSDKClient) Describe(name string) (*sdk.LogProject, error) {
 }
 
 // Create creates SLS project
-func (c *fakeSDKClient) Create(name, description string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Create(_ context.Context, name, description string) (*sdk.LogProject, error) {
 	return validProject, nil
 }
 
 // Update sets SLS project description
-func (c *fakeSDKClient) Update(name, description string) (*sdk.LogProject, error) {
+func (c *fakeSDKClient) Update(_ context.Context, name, description string) (*sdk.LogProject, error) {
 	return validProject, nil
 }
 
 // Delete deletes SLS project
-func (c *fakeSDKClient) Delete(name string) error {
+func (c *fakeSDKClient) Delete(_ context.Context, name string) error {
+	return nil
+}
+
+// ListTags lists the tags of SLS project
+func (c *fakeSDKClient) ListTags(_ context.Context, name string) (map[string]string, error) {
+	return map[string]string{}, nil
+}
+
+// TagProject adds or changes the tags of SLS project
+func (c *fakeSDKClient) TagProject(_ context.Context, name string, tags map[string]string) error {
+	return nil
+}
+
+// UntagProject removes the tags of SLS project
+func (c *fakeSDKClient) UntagProject(_ context.Context, name string, keys []string) error {
 	return nil
 }
 
@@ -24,16 +24,19 @@ import (
 	sdk "github.com/aliyun/aliyun-log-go-sdk"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
-	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
 	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
 )
@@ -44,28 +47,33 @@ const (
 )
 
 // SetupStore adds a controller that reconciles SLSStores.
-func SetupStore(mgr ctrl.Manager, l logging.Logger) error {
+func SetupStore(mgr ctrl.Manager, o util.ControllerOptions) error {
 	name := managed.ControllerName(slsv1alpha1.StoreGroupKind)
+	errs := util.NewErrorClassifier(o.Logger.WithValues("controller", name))
+	tracer := util.NewTracer(name)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
 	options := []managed.ReconcilerOption{
-		managed.WithExternalConnecter(&logStoreConnector{
+		managed.WithExternalConnecter(tracer.Connecter(errs.Connecter(util.DryRun(util.ObserveOnly(&logStoreConnector{
 			client:      mgr.GetClient(),
 			usage:       resource.NewProviderConfigUsageTracker(mgr.GetClient(), &v1beta1.ProviderConfigUsage{}),
 			NewClientFn: slsclient.NewClient,
-		}),
-		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		}), o.DryRun, recorder)))),
+		managed.WithLogger(o.Logger.WithValues("controller", name)),
+		managed.WithPollInterval(o.PollInterval),
+		managed.WithRecorder(recorder),
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
+		WithOptions(o.ForGroup(slsv1alpha1.StoreGroupVersionKind.Group)).
 		For(&slsv1alpha1.LogStore{}).
-		Complete(managed.NewReconciler(mgr,
-			resource.ManagedKind(slsv1alpha1.StoreGroupVersionKind), options...))
+		Complete(tracer.Reconciler(errs.Reconciler(managed.NewReconciler(mgr,
+			resource.ManagedKind(slsv1alpha1.StoreGroupVersionKind), options...))))
 }
 
 type logStoreConnector struct {
 	client      client.Client
 	usage       resource.Tracker
-	NewClientFn func(accessKeyID, accessKeySecret, securityToken, region string) *slsclient.LogClient
+	NewClientFn func(accessKeyID, accessKeySecret, securityToken, endpoint string, transport *clients.TransportConfig, caller *apicall.Caller) (*slsclient.LogClient, error)
 }
 
 func (c *logStoreConnector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) { //nolint:gocyclo
@@ -79,8 +87,13 @@ func (c *logStoreConnector) Connect(ctx context.Context, mg resource.Managed) (m
 		return nil, err
 	}
 
-	slsClient := c.NewClientFn(info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Endpoint)
-	return &storeExternal{client: slsClient}, nil
+	slsClient, err := util.GetOrCreateClient(info, util.ServiceSLS, func() (interface{}, error) {
+		return c.NewClientFn(info.AccessKeyID, info.AccessKeySecret, info.SecurityToken, info.Endpoint, info.Transport, info.Caller)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &storeExternal{client: slsClient.(*slsclient.LogClient)}, nil
 }
 
 type storeExternal struct {
@@ -96,8 +109,8 @@ func (e *storeExternal) Observe(ctx context.Context, mg resource.Managed) (manag
 	storeName := meta.GetExternalName(cr)
 	project := cr.Spec.ForProvider.ProjectName
 
-	store, err := e.client.DescribeStore(project, storeName)
-	if slsclient.IsStoreNotFoundError(err) {
+	store, err := e.client.DescribeStore(ctx, project, storeName)
+	if errorclass.IsNotFound(err) {
 		return managed.ExternalObservation{
 			ResourceExists: false,
 		}, nil
@@ -107,15 +120,20 @@ func (e *storeExternal) Observe(ctx context.Context, mg resource.Managed) (manag
 	}
 
 	cr.Status.AtProvider = slsclient.GenerateStoreObservation(store)
+
+	current := cr.Spec.ForProvider.DeepCopy()
+	slsclient.LateInitializeStore(&cr.Spec.ForProvider, store)
+
 	upToDate := slsclient.IsStoreUpdateToDate(cr, store)
 	if upToDate {
 		cr.SetConditions(xpv1.Available())
 	}
 
 	return managed.ExternalObservation{
-		ResourceExists:    true,
-		ResourceUpToDate:  upToDate,
-		ConnectionDetails: getStoreConnectionDetails(project, storeName),
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate,
+		ResourceLateInitialized: !cmp.Equal(current, &cr.Spec.ForProvider),
+		ConnectionDetails:       getStoreConnectionDetails(project, storeName),
 	}, nil
 }
 
@@ -140,7 +158,10 @@ func (e *storeExternal) Create(ctx context.Context, mg resource.Managed) (manage
 		store.MaxSplitShard = *cr.Spec.ForProvider.MaxSplitShard
 	}
 	cr.SetConditions(xpv1.Creating())
-	err := e.client.CreateStore(cr.Spec.ForProvider.ProjectName, store)
+	if util.Plan(ctx, "CreateLogStoreV2", store) {
+		return managed.ExternalCreation{}, nil
+	}
+	err := e.client.CreateStore(ctx, cr.Spec.ForProvider.ProjectName, store)
 	if err != nil {
 		return managed.ExternalCreation{}, err
 	}
@@ -153,7 +174,10 @@ func (e *storeExternal) Update(ctx context.Context, mg resource.Managed) (manage
 		return managed.ExternalUpdate{}, errors.New(errNotStore)
 	}
 	cr.Status.SetConditions(xpv1.Creating())
-	err := e.client.UpdateStore(cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr), cr.Spec.ForProvider.TTL)
+	if util.Plan(ctx, "UpdateLogStore", map[string]interface{}{"logstoreName": meta.GetExternalName(cr), "ttl": cr.Spec.ForProvider.TTL}) {
+		return managed.ExternalUpdate{}, nil
+	}
+	err := e.client.UpdateStore(ctx, cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr), cr.Spec.ForProvider.TTL)
 	return managed.ExternalUpdate{}, err
 }
 
@@ -163,7 +187,10 @@ func (e *storeExternal) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotStore)
 	}
 	cr.SetConditions(xpv1.Deleting())
-	return e.client.DeleteStore(cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr))
+	if util.Plan(ctx, "DeleteLogStore", meta.GetExternalName(cr)) {
+		return nil
+	}
+	return resource.Ignore(errorclass.IsNotFound, e.client.DeleteStore(ctx, cr.Spec.ForProvider.ProjectName, meta.GetExternalName(cr)))
 }
 
 func getStoreConnectionDetails(project, store string) managed.ConnectionDetails {
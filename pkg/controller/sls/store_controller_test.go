@@ -30,6 +30,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
 	slsclient "github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
@@ -50,6 +51,7 @@ var (
 				ProjectName: project,
 				TTL:         1,
 				ShardCount:  2,
+				AutoSplit:   pointer.BoolPtr(false),
 			},
 		},
 		Status: slsv1alpha1.LogStoreStatus{
@@ -62,7 +64,7 @@ var (
 	validStore = &sdk.LogStore{Name: store, TTL: 1, ShardCount: 2}
 )
 
-func (c *fakeSDKClient) DescribeStore(project string, logstore string) (*sdk.LogStore, error) {
+func (c *fakeSDKClient) DescribeStore(_ context.Context, project string, logstore string) (*sdk.LogStore, error) {
 	switch logstore {
 	case "":
 		return nil, errors.Wrap(&sdk.Error{Code: slsclient.ErrCodeStoreNotExist}, "xxx")
@@ -73,15 +75,15 @@ func (c *fakeSDKClient) DescribeStore(project string, logstore string) (*sdk.Log
 	}
 }
 
-func (c *fakeSDKClient) CreateStore(project string, logstore *sdk.LogStore) error {
+func (c *fakeSDKClient) CreateStore(_ context.Context, project string, logstore *sdk.LogStore) error {
 	return nil
 }
 
-func (c *fakeSDKClient) UpdateStore(project string, logstore string, ttl int) error {
+func (c *fakeSDKClient) UpdateStore(_ context.Context, project string, logstore string, ttl int) error {
 	return nil
 }
 
-func (c *fakeSDKClient) DeleteStore(project string, logstore string) error {
+func (c *fakeSDKClient) DeleteStore(_ context.Context, project string, logstore string) error {
 	return nil
 }
 
@@ -146,6 +148,22 @@ func TestStoreObserve(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SLSStoreLateInitialized": {
+			reason: "The empty parameters of SLS store should be late initialized from the store",
+			mg: func() *slsv1alpha1.LogStore {
+				cr := validStoreCR.DeepCopy()
+				cr.Spec.ForProvider.AutoSplit = nil
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{
+					ResourceExists:          true,
+					ResourceUpToDate:        true,
+					ResourceLateInitialized: true,
+					ConnectionDetails:       getStoreConnectionDetails(project, store)},
+				err: nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
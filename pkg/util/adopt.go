@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReasonAmbiguousExternalResource is the reason of the Ready condition of a
+// managed resource matching several external resources, none of which is
+// adopted.
+const ReasonAmbiguousExternalResource xpv1.ConditionReason = "AmbiguousExternalResource"
+
+const errFmtAmbiguousExternalResource = "cannot adopt an external resource, %d external resources match the managed resource: %s"
+
+// FindFn finds the IDs of the external resources whose name or description is
+// name, if it's not empty, and which have all of tags.
+type FindFn func(ctx context.Context, name string, tags map[string]string) ([]string, error)
+
+// Adopt finds the ID of the external resource of a managed resource whose ID
+// isn't recorded in its status, e.g. when the status was lost in a backup
+// restore, so that another external resource isn't created. Only an external
+// resource with the crossplane tags of the kind, the name and the
+// ProviderConfig of the managed resource is adopted. Unlike RecoverCreated, it
+// doesn't look up the external resources by their name or description, e.g.
+// the external name: an external resource merely named after the managed
+// resource might not be its own, and adopting it would update or delete
+// another's. Hence nothing is adopted when the crossplane tags are disabled or
+// missing from the external resource, e.g. when it was created before they
+// were added. The external resources whose create is pending are recovered by
+// RecoverCreated instead. It returns an empty ID if the crossplane tags aren't
+// set or no external resource is found. If several are, none is adopted: the
+// Ready condition of the managed resource reports them and an error is
+// returned.
+func Adopt(ctx context.Context, mg resource.Managed, find FindFn) (string, error) {
+	tags := identifyingTags(mg)
+	if tags == nil {
+		return "", nil
+	}
+	ids, err := find(ctx, "", tags)
+	if err != nil {
+		return "", err
+	}
+	if len(ids) == 0 {
+		return "", nil
+	}
+	return pick(mg, ids)
+}
+
+// pick returns the only one of ids of the external resources matching a
+// managed resource. If there are several, the Ready condition of the managed
+// resource reports them and an error is returned.
+func pick(mg resource.Managed, ids []string) (string, error) {
+	if len(ids) == 1 {
+		return ids[0], nil
+	}
+	sort.Strings(ids)
+	err := errors.Errorf(errFmtAmbiguousExternalResource, len(ids), strings.Join(ids, ", "))
+	mg.SetConditions(xpv1.Condition{
+		Type:               xpv1.TypeReady,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAmbiguousExternalResource,
+		Message:            err.Error(),
+	})
+	return "", err
+}
+
+// identifyingTags returns the crossplane tags of the kind, the name and the
+// ProviderConfig of a managed resource, which identify its external resource,
+// or nil if the kind or the name isn't set. The ProviderConfig tag, if set,
+// tells apart the managed resources of the same kind and name, e.g. in several
+// namespaces or clusters sharing an account.
+func identifyingTags(mg resource.Managed) map[string]string {
+	tags, _ := GetResourceTags(mg)
+	kind, ok := tags[TagKind]
+	if !ok {
+		return nil
+	}
+	name, ok := tags[TagName]
+	if !ok {
+		return nil
+	}
+	identifying := map[string]string{TagKind: kind, TagName: name}
+	if pc, ok := tags[TagProviderConfig]; ok {
+		identifying[TagProviderConfig] = pc
+	}
+	return identifying
+}
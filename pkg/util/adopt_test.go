@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	database "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+)
+
+func TestAdopt(t *testing.T) {
+	errBoom := errors.New("boom")
+	tags := map[string]string{TagKind: "rdsinstance.database.alibaba.crossplane.io", TagName: "db", "team": "db"}
+
+	named := func(tags map[string]string) resource.Managed {
+		mg := &database.RDSInstance{Spec: database.RDSInstanceSpec{ForProvider: database.RDSInstanceParameters{Tags: tags}}}
+		meta.SetExternalName(mg, "db")
+		return mg
+	}
+	find := func(byTags []string) FindFn {
+		return func(_ context.Context, name string, tags map[string]string) ([]string, error) {
+			if name == "" && cmp.Equal(tags, map[string]string{TagKind: "rdsinstance.database.alibaba.crossplane.io", TagName: "db"}) {
+				return byTags, nil
+			}
+			return nil, errors.Errorf("unexpected lookup of %q with tags %v", name, tags)
+		}
+	}
+
+	type want struct {
+		id     string
+		err    error
+		reason xpv1.ConditionReason
+	}
+	cases := map[string]struct {
+		reason string
+		mg     resource.Managed
+		find   FindFn
+		want   want
+	}{
+		"ByTags": {
+			reason: "The only external resource with the crossplane tags should be adopted",
+			mg:     named(tags),
+			find:   find([]string{"rm-2"}),
+			want:   want{id: "rm-2"},
+		},
+		"ByTagsAndProviderConfig": {
+			reason: "The external resource should be found by the crossplane tag of the ProviderConfig too, if set",
+			mg:     named(map[string]string{TagKind: "rdsinstance.database.alibaba.crossplane.io", TagName: "db", TagProviderConfig: "default", "team": "db"}),
+			find: func(_ context.Context, name string, tags map[string]string) ([]string, error) {
+				if name == "" && cmp.Equal(tags, map[string]string{TagKind: "rdsinstance.database.alibaba.crossplane.io", TagName: "db", TagProviderConfig: "default"}) {
+					return []string{"rm-3"}, nil
+				}
+				return nil, errors.Errorf("unexpected lookup of %q with tags %v", name, tags)
+			},
+			want: want{id: "rm-3"},
+		},
+		"NotFound": {
+			reason: "No external resource should be adopted when none matches",
+			mg:     named(tags),
+			find:   find(nil),
+		},
+		"NotByName": {
+			reason: "The external resources shouldn't be found by the external name alone when the crossplane tags aren't set",
+			mg:     named(map[string]string{"team": "db"}),
+			find: func(context.Context, string, map[string]string) ([]string, error) {
+				return []string{"rm-1"}, nil
+			},
+		},
+		"Ambiguous": {
+			reason: "No external resource should be adopted when several match",
+			mg:     named(tags),
+			find:   find([]string{"rm-2", "rm-1"}),
+			want: want{
+				err:    errors.Errorf(errFmtAmbiguousExternalResource, 2, "rm-1, rm-2"),
+				reason: ReasonAmbiguousExternalResource,
+			},
+		},
+		"FindFailed": {
+			reason: "Failing to find the external resources should return an error",
+			mg:     named(tags),
+			find: func(context.Context, string, map[string]string) ([]string, error) {
+				return nil, errBoom
+			},
+			want: want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			id, err := Adopt(context.Background(), tc.mg, tc.find)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAdopt(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if id != tc.want.id {
+				t.Errorf("\n%s\nAdopt(...): want ID %q, got %q", tc.reason, tc.want.id, id)
+			}
+			if r := tc.mg.GetCondition(xpv1.TypeReady).Reason; r != tc.want.reason {
+				t.Errorf("\n%s\nAdopt(...): want reason %q of the Ready condition, got %q", tc.reason, tc.want.reason, r)
+			}
+		})
+	}
+}
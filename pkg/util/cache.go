@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// sdkClients caches the SDK clients shared by all the controllers
+var sdkClients = newClientCache()
+
+// clientCache caches SDK clients by ProviderConfig, service, region and
+// endpoint, so that the controllers reuse them and their HTTP connections.
+type clientCache struct {
+	mu      sync.Mutex
+	entries map[string]*clientEntry
+}
+
+type clientEntry struct {
+	client interface{}
+	// fingerprint identifies the ProviderConfigs, the version of the static
+	// credentials and the transport configuration the client was
+	// established with.
+	fingerprint string
+	// expiration is when the temporary credentials of the client expire.
+	// It's zero for long-lived credentials.
+	expiration time.Time
+}
+
+func newClientCache() *clientCache {
+	return &clientCache{entries: map[string]*clientEntry{}}
+}
+
+// GetOrCreateClient returns the cached SDK client of service established with
+// info, or creates one with newFn. The cached client is replaced when the
+// ProviderConfig, its source ProviderConfigs, the Secret or the file of its
+// static credentials or its transport configuration change, or when its
+// temporary credentials are about to expire.
+func GetOrCreateClient(info *ClientEstablishmentInfo, service string, newFn func() (interface{}, error)) (interface{}, error) {
+	if info.client != nil {
+		return info.client, nil
+	}
+	return sdkClients.getOrCreate(info, service, newFn)
+}
+
+func cacheKey(info *ClientEstablishmentInfo, service string) string {
+	return strings.Join([]string{info.ProviderConfigName, service, info.Region, info.Endpoint}, "/")
+}
+
+// get returns the cached client of service established with info, unless its
+// credentials are about to expire.
+func (c *clientCache) get(info *ClientEstablishmentInfo, service string) (interface{}, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[cacheKey(info, service)]
+	c.mu.Unlock()
+	if !ok || e.fingerprint != info.fingerprint() || isExpiring(e.expiration) {
+		return nil, false
+	}
+	return e.client, true
+}
+
+func (c *clientCache) getOrCreate(info *ClientEstablishmentInfo, service string, newFn func() (interface{}, error)) (interface{}, error) {
+	if cli, ok := c.get(info, service); ok {
+		return cli, nil
+	}
+
+	cli, err := newFn()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[cacheKey(info, service)] = &clientEntry{client: cli, fingerprint: info.fingerprint(), expiration: info.Expiration}
+	return cli, nil
+}
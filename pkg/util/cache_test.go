@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClientCache(t *testing.T) {
+	base := ClientEstablishmentInfo{
+		AlibabaCredentials:       AlibabaCredentials{AccessKeyID: "ak", AccessKeySecret: "sk"},
+		Region:                   "cn-beijing",
+		ProviderConfigName:       "default",
+		ProviderConfigGeneration: 1,
+	}
+
+	cases := map[string]struct {
+		reason string
+		cached func(info *ClientEstablishmentInfo)
+		modify func(info *ClientEstablishmentInfo)
+		reuse  bool
+	}{
+		"SameInfo": {
+			reason: "The cached client should be reused if nothing changes",
+			reuse:  true,
+		},
+		"CredentialsNotResolved": {
+			reason: "The cached client should be reused without resolving the credentials again",
+			modify: func(info *ClientEstablishmentInfo) { info.AlibabaCredentials = AlibabaCredentials{} },
+			reuse:  true,
+		},
+		"ProviderConfigChanged": {
+			reason: "A new client should be created if ProviderConfig changes",
+			modify: func(info *ClientEstablishmentInfo) { info.ProviderConfigGeneration = 2 },
+		},
+		"SourceProviderConfigChanged": {
+			reason: "A new client should be created if the source ProviderConfig changes",
+			cached: func(info *ClientEstablishmentInfo) { info.sourceGenerations = []int64{1} },
+			modify: func(info *ClientEstablishmentInfo) { info.sourceGenerations = []int64{2} },
+		},
+		"CredentialsSecretChanged": {
+			reason: "A new client should be created if the Secret of the static credentials changes",
+			cached: func(info *ClientEstablishmentInfo) { info.credentialsVersion = "1" },
+			modify: func(info *ClientEstablishmentInfo) { info.credentialsVersion = "2" },
+		},
+		"LongLivedCredentials": {
+			reason: "The cached client of long-lived credentials should be reused until they change",
+			cached: func(info *ClientEstablishmentInfo) { info.credentialsVersion = "1" },
+			reuse:  true,
+		},
+		"RateLimitAccountChanged": {
+			reason: "A new client should be created to share the rate limiter of the account once it's known",
+			cached: func(info *ClientEstablishmentInfo) { info.rateLimitAccount = "providerconfig:default" },
+			modify: func(info *ClientEstablishmentInfo) { info.rateLimitAccount = "123456" },
+		},
+		"CredentialsExpiring": {
+			reason: "A new client should be created if the temporary credentials are about to expire",
+			cached: func(info *ClientEstablishmentInfo) {
+				info.SecurityToken = "token"
+				info.Expiration = time.Now().Add(time.Minute)
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := newClientCache()
+			var created int
+			newFn := func() (interface{}, error) {
+				created++
+				return &created, nil
+			}
+
+			first := base
+			if tc.cached != nil {
+				tc.cached(&first)
+			}
+			if _, err := c.getOrCreate(&first, ServiceOSS, newFn); err != nil {
+				t.Fatal(err)
+			}
+			second := base
+			if tc.cached != nil {
+				tc.cached(&second)
+			}
+			if tc.modify != nil {
+				tc.modify(&second)
+			}
+			if _, err := c.getOrCreate(&second, ServiceOSS, newFn); err != nil {
+				t.Fatal(err)
+			}
+
+			want := 2
+			if tc.reuse {
+				want = 1
+			}
+			if created != want {
+				t.Errorf("\n%s\ngetOrCreate(...): want %d clients created, got %d", tc.reason, want, created)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+)
+
+const (
+	// ErrFmtProviderConfigCycle is the error of ProviderConfigs referencing each other as the source
+	ErrFmtProviderConfigCycle = "source ProviderConfigs form a cycle: %s"
+	// ErrNoSourceProviderConfig is the error of not referencing a source ProviderConfig when the source is ProviderConfig
+	ErrNoSourceProviderConfig = "no source ProviderConfig specified"
+	errTrackSourceUsage       = "cannot track the usage of source ProviderConfig"
+)
+
+// getSourceCredentials resolves the credentials of the source ProviderConfig
+// of pc.
+func getSourceCredentials(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig, chain []string) (*AlibabaCredentials, error) {
+	chain = append(chain[:len(chain):len(chain)], pc.Name)
+	src, err := getSourceProviderConfig(ctx, client, pc, chain)
+	if err != nil {
+		return nil, err
+	}
+	return resolveCredentials(ctx, client, src, chain)
+}
+
+// getSourceProviderConfig gets the source ProviderConfig of pc. chain is the
+// names of the ProviderConfigs resolved so far, ending with pc itself.
+func getSourceProviderConfig(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig, chain []string) (*aliv1beta1.ProviderConfig, error) {
+	ref := pc.Spec.Credentials.ProviderConfigRef
+	if ref == nil {
+		return nil, errors.New(ErrNoSourceProviderConfig)
+	}
+	for _, name := range chain {
+		if name == ref.Name {
+			return nil, errors.Errorf(ErrFmtProviderConfigCycle, strings.Join(append(chain, ref.Name), " -> "))
+		}
+	}
+	return GetProviderConfig(ctx, client, ref.Name)
+}
+
+// GetSourceProviderConfigs gets the ProviderConfigs pc gets its source
+// credentials from, directly or indirectly, nearest first.
+func GetSourceProviderConfigs(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig) ([]*aliv1beta1.ProviderConfig, error) {
+	var (
+		srcs  []*aliv1beta1.ProviderConfig
+		chain []string
+	)
+	for pc.Spec.Credentials.Source == aliv1beta1.CredentialsSourceProviderConfig {
+		chain = append(chain, pc.Name)
+		src, err := getSourceProviderConfig(ctx, client, pc, chain)
+		if err != nil {
+			return nil, err
+		}
+		srcs = append(srcs, src)
+		pc = src
+	}
+	return srcs, nil
+}
+
+// trackSourceUsage tracks the usage of the source ProviderConfigs srcs by a
+// managed resource, so that they can't be deleted while it depends on them.
+// The usage of its own ProviderConfig is tracked by the ProviderConfigUsage
+// tracker. Only the missing usages are applied.
+func trackSourceUsage(ctx context.Context, client client.Client, mg resource.Managed, srcs []*aliv1beta1.ProviderConfig) error {
+	a := resource.NewAPIPatchingApplicator(client)
+	gvk := mg.GetObjectKind().GroupVersionKind()
+	for _, src := range srcs {
+		pcu := &aliv1beta1.ProviderConfigUsage{}
+		name := fmt.Sprintf("%s-%s", mg.GetUID(), src.Name)
+		err := client.Get(ctx, types.NamespacedName{Name: name}, pcu)
+		if err == nil {
+			continue
+		}
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrap(err, errTrackSourceUsage)
+		}
+		pcu.SetName(name)
+		pcu.SetLabels(map[string]string{xpv1.LabelKeyProviderName: src.Name})
+		pcu.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(mg, gvk))})
+		pcu.SetProviderConfigReference(xpv1.Reference{Name: src.Name})
+		pcu.SetResourceReference(xpv1.TypedReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       mg.GetName(),
+		})
+		if err := a.Apply(ctx, pcu, resource.MustBeControllableBy(mg.GetUID())); err != nil {
+			return errors.Wrap(err, errTrackSourceUsage)
+		}
+	}
+	return nil
+}
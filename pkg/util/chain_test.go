@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
+)
+
+func sourcedProviderConfig(name, source string) v1beta1.ProviderConfig {
+	pc := v1beta1.ProviderConfig{}
+	pc.SetName(name)
+	pc.Spec.Credentials.Source = v1beta1.CredentialsSourceProviderConfig
+	pc.Spec.Credentials.ProviderConfigRef = &xpv1.Reference{Name: source}
+	pc.Spec.AssumeRole = &v1beta1.AssumeRoleOptions{RoleARN: "acs:ram::" + name + ":role/crossplane"}
+	return pc
+}
+
+func TestGetCredentialsFromProviderConfig(t *testing.T) {
+	hub := v1beta1.ProviderConfig{}
+	hub.SetName("hub")
+	hub.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+	hub.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{Key: "credentials"}
+
+	noRef := sourcedProviderConfig("noref", "")
+	noRef.Spec.Credentials.ProviderConfigRef = nil
+
+	pcs := map[string]v1beta1.ProviderConfig{
+		"hub":   hub,
+		"spoke": sourcedProviderConfig("spoke", "hub"),
+		"a":     sourcedProviderConfig("a", "b"),
+		"b":     sourcedProviderConfig("b", "a"),
+		"noref": noRef,
+	}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1beta1.ProviderConfig:
+				pc, ok := pcs[key.Name]
+				if !ok {
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				}
+				pc.DeepCopyInto(o)
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"credentials": []byte("accessKeyId: ak\naccessKeySecret: sk\n")}
+			}
+			return nil
+		},
+	}
+
+	type want struct {
+		cred *AlibabaCredentials
+		err  error
+	}
+	cases := map[string]struct {
+		name string
+		want want
+	}{
+		"AssumeRoleFromSource": {
+			name: "spoke",
+			want: want{
+				cred: &AlibabaCredentials{AccessKeyID: "STS.spoke", AccessKeySecret: "sk", SecurityToken: "token"},
+			},
+		},
+		"Cycle": {
+			name: "a",
+			want: want{
+				err: errors.Errorf(ErrFmtProviderConfigCycle, "a -> b -> a"),
+			},
+		},
+		"NoSourceProviderConfig": {
+			name: "noref",
+			want: want{
+				err: errors.New(ErrNoSourceProviderConfig),
+			},
+		},
+	}
+
+	defer func(fn func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error)) {
+		NewSTSClient = fn
+	}(NewSTSClient)
+	NewSTSClient = func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error) {
+		return &fakeSTSClient{assumeRole: func(req *sts.AssumeRoleRequest) (*sts.Credentials, error) {
+			if accessKeyID != "ak" || req.RoleARN != "acs:ram::spoke:role/crossplane" {
+				return nil, errors.New("unexpected request")
+			}
+			return &sts.Credentials{AccessKeyID: "STS.spoke", AccessKeySecret: "sk", SecurityToken: "token"}, nil
+		}}, nil
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assumedRoleCredentials = newCredentialsCache()
+			cred, err := GetCredentials(context.TODO(), kube, tc.name)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nGetCredentials(...) -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.cred, cred); diff != "" {
+				t.Errorf("\nGetCredentials(...) %s\n", diff)
+			}
+		})
+	}
+}
+
+func TestGetSourceProviderConfigs(t *testing.T) {
+	hub := v1beta1.ProviderConfig{}
+	hub.SetName("hub")
+	hub.Spec.Credentials.Source = xpv1.CredentialsSourceSecret
+	pcs := map[string]v1beta1.ProviderConfig{
+		"hub":   hub,
+		"spoke": sourcedProviderConfig("spoke", "hub"),
+		"leaf":  sourcedProviderConfig("leaf", "spoke"),
+	}
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			pc := pcs[key.Name]
+			pc.DeepCopyInto(obj.(*v1beta1.ProviderConfig))
+			return nil
+		},
+	}
+
+	leaf := pcs["leaf"]
+	srcs, err := GetSourceProviderConfigs(context.TODO(), kube, &leaf)
+	if err != nil {
+		t.Fatalf("GetSourceProviderConfigs(...): %v", err)
+	}
+	var got []string
+	for _, src := range srcs {
+		got = append(got, src.Name)
+	}
+	if diff := cmp.Diff([]string{"spoke", "hub"}, got); diff != "" {
+		t.Errorf("\nGetSourceProviderConfigs(...) -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestTrackSourceUsage(t *testing.T) {
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool", UID: "uid"}}
+	tracked := sourcedProviderConfig("tracked", "hub")
+	untracked := sourcedProviderConfig("untracked", "hub")
+
+	var created []string
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+			if key.Name == "uid-tracked" {
+				return nil
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		},
+		MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			created = append(created, obj.GetName())
+			return nil
+		},
+	}
+
+	if err := trackSourceUsage(context.TODO(), kube, mg, []*v1beta1.ProviderConfig{&tracked, &untracked}); err != nil {
+		t.Fatalf("trackSourceUsage(...): %v", err)
+	}
+	if diff := cmp.Diff([]string{"uid-untracked"}, created); diff != "" {
+		t.Errorf("\ntrackSourceUsage(...): only the missing usages should be applied, -want, +got:\n%s\n", diff)
+	}
+}
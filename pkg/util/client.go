@@ -19,11 +19,17 @@
 package util
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
 )
 
 const (
@@ -32,14 +38,68 @@ const (
 	errTrackUsage                     string = "cannot track provider config usage"
 )
 
+// Service names of the Alibaba Cloud products whose SDK clients are established
+const (
+	ServiceRDS   = "rds"
+	ServiceRedis = "redis"
+	ServiceOSS   = "oss"
+	ServiceNAS   = "nas"
+	ServiceSLB   = "slb"
+	ServiceSLS   = "sls"
+)
+
 // ClientEstablishmentInfo represents all the information for establishing an SDK client
 type ClientEstablishmentInfo struct {
 	AlibabaCredentials `json:",inline"`
 	Region             string `json:"region"`
 	Endpoint           string `json:"endpoint"`
+
+	// ProviderConfigName and ProviderConfigGeneration identify the
+	// ProviderConfig the information is prepared from. The generation only
+	// changes with the spec, so updating the status of ProviderConfig doesn't
+	// replace the clients, except for setting its account.
+	ProviderConfigName       string `json:"providerConfigName"`
+	ProviderConfigGeneration int64  `json:"providerConfigGeneration"`
+
+	// Transport is the HTTP transport configuration of the client. It's nil
+	// if ProviderConfig doesn't configure the transport.
+	Transport *clients.TransportConfig `json:"transport,omitempty"`
+
+	// Caller sends the requests of the client, limiting their rate together
+	// with all the other clients of the same account and service, and
+	// recording their metrics.
+	Caller *apicall.Caller `json:"-"`
+
+	// sourceGenerations are the generations of the ProviderConfigs
+	// ProviderConfig gets its source credentials from, nearest first.
+	sourceGenerations []int64
+	// credentialsVersion is the version of the static credentials the
+	// credentials are resolved from, e.g. the resourceVersion of their Secret.
+	credentialsVersion string
+	// rateLimitAccount identifies the account whose rate limiter the Caller
+	// of the client shares. It changes once the account of ProviderConfig is
+	// known, which replaces the client.
+	rateLimitAccount string
+	// client is the cached SDK client established with the same information.
+	// The credentials aren't resolved if it's set.
+	client interface{}
 }
 
-// PrepareClient will prepare all information to establish an Alibaba Cloud resource SDK client
+// fingerprint identifies the specs of the ProviderConfigs, including their
+// credentials sources, the version of the static credentials, the rate limited
+// account and the transport configuration the client is established with. It
+// doesn't need the credentials resolved.
+func (i *ClientEstablishmentInfo) fingerprint() string {
+	generations := []string{strconv.FormatInt(i.ProviderConfigGeneration, 10)}
+	for _, g := range i.sourceGenerations {
+		generations = append(generations, strconv.FormatInt(g, 10))
+	}
+	return strings.Join([]string{strings.Join(generations, ","), i.credentialsVersion, i.rateLimitAccount, i.Transport.Fingerprint()}, "/")
+}
+
+// PrepareClient will prepare all information to establish an Alibaba Cloud resource SDK client.
+// The credentials are only resolved if there's no cached client established
+// with the same information, or its temporary credentials are about to expire.
 func PrepareClient(ctx context.Context, mg resource.Managed, res runtime.Object, c client.Client, usage resource.Tracker, providerConfigName string) (*ClientEstablishmentInfo, error) {
 	info := &ClientEstablishmentInfo{}
 
@@ -47,25 +107,67 @@ func PrepareClient(ctx context.Context, mg resource.Managed, res runtime.Object,
 		return nil, errors.Wrap(err, errTrackUsage)
 	}
 
-	cred, err := GetCredentials(ctx, c, providerConfigName)
+	pc, err := GetProviderConfig(ctx, c, providerConfigName)
 	if err != nil {
 		return nil, errors.Wrap(err, ErrPrepareClientEstablishmentInfo)
 	}
-	info.AccessKeyID = cred.AccessKeyID
-	info.AccessKeySecret = cred.AccessKeySecret
-	info.SecurityToken = cred.SecurityToken
+	if err := CheckProviderConfigHealth(pc); err != nil {
+		return nil, err
+	}
+	info.ProviderConfigName = pc.Name
+	info.ProviderConfigGeneration = pc.Generation
+	info.rateLimitAccount = rateLimitAccount(pc)
 
-	region, err := GetRegion(ctx, c, providerConfigName)
+	srcs, err := GetSourceProviderConfigs(ctx, c, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrPrepareClientEstablishmentInfo)
+	}
+	if err := trackSourceUsage(ctx, c, mg, srcs); err != nil {
+		return nil, errors.Wrap(err, ErrPrepareClientEstablishmentInfo)
+	}
+	root := pc
+	for _, src := range srcs {
+		info.sourceGenerations = append(info.sourceGenerations, src.Generation)
+		root = src
+	}
+	version, err := getCredentialsVersion(ctx, c, root)
 	if err != nil {
 		return nil, errors.Wrap(err, ErrPrepareClientEstablishmentInfo)
 	}
-	info.Region = region
+	info.credentialsVersion = version
+
+	info.Region = pc.Spec.Region
+	if region := GetResourceRegion(res); region != "" {
+		info.Region = region
+	}
 
-	endpoint, err := GetEndpoint(res, region)
+	service, err := GetService(res)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := ResolveEndpoint(pc, service, info.Region)
 	if err != nil {
 		return nil, err
 	}
 	info.Endpoint = endpoint
 
+	transport, err := GetTransportConfig(ctx, c, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrGetTransportConfig)
+	}
+	info.Transport = transport
+
+	if cli, ok := sdkClients.get(info, service); ok {
+		info.client = cli
+		return info, nil
+	}
+
+	cred, err := getCredentials(ctx, c, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrPrepareClientEstablishmentInfo)
+	}
+	info.AlibabaCredentials = *cred
+	info.Caller = apicall.NewCaller(service, info.Region, GetRateLimiter(pc, service))
+
 	return info, nil
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
+)
+
+// AnnotationCreatePending is the annotation of a managed resource whose
+// external resource is being created, set before the create request is sent.
+// The external resource may exist while its ID isn't recorded in the status of
+// the managed resource, e.g. when the response of the request was lost.
+const AnnotationCreatePending = "alibaba.crossplane.io/create-pending"
+
+const (
+	errSetCreatePending   = "cannot set the create pending annotation of the managed resource"
+	errUnsetCreatePending = "cannot remove the create pending annotation of the managed resource"
+	errNotManaged         = "copy of the managed resource is not a managed resource"
+)
+
+// ClientToken returns the client token of the requests creating the external
+// resource of the managed resource. It is derived from the UID of the managed
+// resource, so that a retried request returns the external resource created by
+// the previous one instead of creating another.
+func ClientToken(mg metav1.Object) string {
+	return string(mg.GetUID())
+}
+
+// IsCreatePending returns true if the external resource of the managed resource
+// may have been created without its ID being recorded.
+func IsCreatePending(mg metav1.Object) bool {
+	_, ok := mg.GetAnnotations()[AnnotationCreatePending]
+	return ok
+}
+
+// SetCreatePending persists the create pending annotation of the managed
+// resource before its external resource is created, so that a restarted
+// controller recovers it with the same client token.
+func SetCreatePending(ctx context.Context, kube client.Client, mg resource.Managed) error {
+	if IsCreatePending(mg) {
+		return nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	err := updateAnnotations(ctx, kube, mg, func(o metav1.Object) {
+		meta.AddAnnotations(o, map[string]string{AnnotationCreatePending: now})
+	})
+	return errors.Wrap(err, errSetCreatePending)
+}
+
+// UnsetCreatePending removes the create pending annotation of the managed
+// resource once the ID of its external resource is recorded.
+func UnsetCreatePending(ctx context.Context, kube client.Client, mg resource.Managed) error {
+	if !IsCreatePending(mg) {
+		return nil
+	}
+	err := updateAnnotations(ctx, kube, mg, func(o metav1.Object) {
+		meta.RemoveAnnotations(o, AnnotationCreatePending)
+	})
+	return errors.Wrap(err, errUnsetCreatePending)
+}
+
+// CreateFailed removes the create pending annotation of the managed resource
+// if err is a response of the API to the request creating its external
+// resource, which therefore failed. The annotation is kept if the request may
+// not have been answered, e.g. on a transport error or a timeout. It returns
+// err.
+func CreateFailed(ctx context.Context, kube client.Client, mg resource.Managed, err error) error {
+	d, ok := errorclass.GetDetails(err)
+	if !ok || d.RequestID == "" {
+		return err
+	}
+	if uerr := UnsetCreatePending(ctx, kube, mg); uerr != nil {
+		return uerr
+	}
+	return err
+}
+
+// RecoverCreated finds the ID of the external resource of the managed resource
+// whose create is pending, but whose ID isn't recorded, e.g. when the response
+// of the create request was lost. The external resource is found by the name
+// it was created with. If none is found, no ID is returned, so that the create
+// request is sent again with the same client token, which returns the external
+// resource if it was created after all. If several are, none is recovered.
+func RecoverCreated(ctx context.Context, mg resource.Managed, name string, find FindFn) (string, error) {
+	ids, err := find(ctx, name, nil)
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return pick(mg, ids)
+}
+
+// updateAnnotations updates a copy of the managed resource, as the response of
+// the update would overwrite the status of the managed resource that isn't
+// persisted yet.
+func updateAnnotations(ctx context.Context, kube client.Client, mg resource.Managed, fn func(metav1.Object)) error {
+	cp, ok := mg.DeepCopyObject().(resource.Managed)
+	if !ok {
+		return errors.New(errNotManaged)
+	}
+	fn(cp)
+	if err := kube.Update(ctx, cp); err != nil {
+		return err
+	}
+	fn(mg)
+	mg.SetResourceVersion(cp.GetResourceVersion())
+	return nil
+}
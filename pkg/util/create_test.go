@@ -0,0 +1,235 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	sdkerrors "github.com/aliyun/alibaba-cloud-sdk-go/sdk/errors"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestClientToken(t *testing.T) {
+	mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{UID: "d3b07384-d9a0-4c6b-9b1d-7f3c1e1f0a2b"}}
+	if got := ClientToken(mg); got != "d3b07384-d9a0-4c6b-9b1d-7f3c1e1f0a2b" {
+		t.Errorf("ClientToken(...): want the UID, got %q", got)
+	}
+}
+
+func TestSetCreatePending(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		err     error
+		pending bool
+		updated bool
+	}
+	cases := map[string]struct {
+		reason string
+		mg     *fake.Managed
+		err    error
+		want   want
+	}{
+		"Set": {
+			reason: "The create pending annotation should be persisted",
+			mg:     &fake.Managed{},
+			want:   want{pending: true, updated: true},
+		},
+		"AlreadyPending": {
+			reason: "The managed resource shouldn't be updated when its create is already pending",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationCreatePending: "2021-01-01T00:00:00Z"}}},
+			want:   want{pending: true},
+		},
+		"UpdateFailed": {
+			reason: "Failing to update the managed resource should return an error",
+			mg:     &fake.Managed{},
+			err:    errBoom,
+			want:   want{err: errors.Wrap(errBoom, errSetCreatePending), updated: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.mg.SetConditions(xpv1.Creating())
+			updated := false
+			kube := &test.MockClient{MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				updated = true
+				if !IsCreatePending(obj) {
+					t.Errorf("\n%s\nUpdate(...): want the create pending annotation", tc.reason)
+				}
+				return tc.err
+			}}
+
+			err := SetCreatePending(context.Background(), kube, tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetCreatePending(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got := IsCreatePending(tc.mg); got != tc.want.pending {
+				t.Errorf("\n%s\nIsCreatePending(...): want %t, got %t", tc.reason, tc.want.pending, got)
+			}
+			if updated != tc.want.updated {
+				t.Errorf("\n%s\nUpdate(...): want called %t, got %t", tc.reason, tc.want.updated, updated)
+			}
+			if diff := cmp.Diff(xpv1.Creating(), tc.mg.GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nSetCreatePending(...): the status should be kept, -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUnsetCreatePending(t *testing.T) {
+	pending := map[string]string{AnnotationCreatePending: "2021-01-01T00:00:00Z"}
+
+	cases := map[string]struct {
+		reason  string
+		mg      *fake.Managed
+		updated bool
+	}{
+		"Unset": {
+			reason:  "The create pending annotation should be removed",
+			mg:      &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: pending}},
+			updated: true,
+		},
+		"NotPending": {
+			reason: "The managed resource shouldn't be updated when no create is pending",
+			mg:     &fake.Managed{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				updated = true
+				return nil
+			}}
+
+			if err := UnsetCreatePending(context.Background(), kube, tc.mg); err != nil {
+				t.Errorf("\n%s\nUnsetCreatePending(...): %v", tc.reason, err)
+			}
+			if IsCreatePending(tc.mg) {
+				t.Errorf("\n%s\nIsCreatePending(...): want false, got true", tc.reason)
+			}
+			if updated != tc.updated {
+				t.Errorf("\n%s\nUpdate(...): want called %t, got %t", tc.reason, tc.updated, updated)
+			}
+		})
+	}
+}
+
+func TestCreateFailed(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		err     error
+		pending bool
+	}{
+		"Rejected": {
+			reason: "The create pending annotation should be removed when the API rejected the request",
+			err:    errors.Wrap(sdkerrors.NewServerError(400, `{"Code": "InvalidParameter", "RequestId": "5E4F1A2B"}`, ""), "wrapped"),
+		},
+		"ServerError": {
+			reason: "The create pending annotation should be removed when the API answered the request with an error",
+			err:    sdkerrors.NewServerError(503, `{"Code": "ServiceUnavailable", "RequestId": "5E4F1A2C"}`, ""),
+		},
+		"Timeout": {
+			reason:  "The create pending annotation should be kept when the request timed out",
+			err:     errors.New("context deadline exceeded"),
+			pending: true,
+		},
+		"NotAnAPIError": {
+			reason:  "The create pending annotation should be kept when the request may not have been answered",
+			err:     errors.New("connection reset by peer"),
+			pending: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{AnnotationCreatePending: "2021-01-01T00:00:00Z"}}}
+			kube := &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)}
+
+			err := CreateFailed(context.Background(), kube, mg, tc.err)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreateFailed(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if got := IsCreatePending(mg); got != tc.pending {
+				t.Errorf("\n%s\nIsCreatePending(...): want %t, got %t", tc.reason, tc.pending, got)
+			}
+		})
+	}
+}
+
+func TestRecoverCreated(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		id  string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		ids    []string
+		err    error
+		want   want
+	}{
+		"Recovered": {
+			reason: "The external resource created with the name should be recovered",
+			ids:    []string{"rm-1"},
+			want:   want{id: "rm-1"},
+		},
+		"NotFound": {
+			reason: "No external resource should be recovered when none is found, so that it is created again with the same client token",
+		},
+		"Ambiguous": {
+			reason: "No external resource should be recovered when several have the name",
+			ids:    []string{"rm-2", "rm-1"},
+			want:   want{err: errors.Errorf(errFmtAmbiguousExternalResource, 2, "rm-1, rm-2")},
+		},
+		"FindFailed": {
+			reason: "Failing to find the external resources should return an error",
+			err:    errBoom,
+			want:   want{err: errBoom},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			find := func(_ context.Context, name string, tags map[string]string) ([]string, error) {
+				if name != "cool" || tags != nil {
+					t.Errorf("\n%s\nfind(...): want the name %q only, got %q and %v", tc.reason, "cool", name, tags)
+				}
+				return tc.ids, tc.err
+			}
+
+			id, err := RecoverCreated(context.Background(), &fake.Managed{}, "cool", find)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRecoverCreated(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if id != tc.want.id {
+				t.Errorf("\n%s\nRecoverCreated(...): want %q, got %q", tc.reason, tc.want.id, id)
+			}
+		})
+	}
+}
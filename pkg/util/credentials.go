@@ -0,0 +1,196 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/metadata"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
+)
+
+const (
+	// ErrGetInstanceMetadataCredentials is the error of failing to get credentials from the instance metadata service
+	ErrGetInstanceMetadataCredentials = "cannot get credentials from the instance metadata service"
+	// ErrAssumeRoleWithOIDC is the error of failing to exchange an OIDC token for credentials
+	ErrAssumeRoleWithOIDC = "cannot assume RAM role with OIDC token"
+	// ErrOIDCNotComplete is the error of missing the token file, the OIDC provider ARN or the role ARN
+	ErrOIDCNotComplete = "OIDC token file, OIDC provider ARN or role ARN not existed"
+	errReadOIDCToken   = "cannot read OIDC token file"
+
+	// EnvOIDCTokenFile is the environment variable of the OIDC token file path injected by RRSA
+	EnvOIDCTokenFile = "ALIBABA_CLOUD_OIDC_TOKEN_FILE"
+	// EnvOIDCProviderARN is the environment variable of the OIDC provider ARN injected by RRSA
+	EnvOIDCProviderARN = "ALIBABA_CLOUD_OIDC_PROVIDER_ARN"
+	// EnvRoleARN is the environment variable of the RAM role ARN injected by RRSA
+	EnvRoleARN = "ALIBABA_CLOUD_ROLE_ARN"
+
+	// credentialsRefreshWindow is how long before their expiration cached
+	// temporary credentials are refreshed
+	credentialsRefreshWindow = 15 * time.Minute
+)
+
+// instanceMetadataCredentials caches the credentials got from the instance
+// metadata service, keyed by the endpoint and the role name.
+var instanceMetadataCredentials = newCredentialsCache()
+
+// oidcCredentials caches the credentials exchanged for OIDC tokens, keyed by
+// the token file, the OIDC provider and the role.
+var oidcCredentials = newCredentialsCache()
+
+// assumedRoleCredentials caches the credentials of assumed RAM roles, keyed by
+// the name and the generation of ProviderConfig, and the fingerprint of the
+// source credentials.
+var assumedRoleCredentials = newCredentialsCache()
+
+// NewSTSOIDCClient creates the STS client used to exchange OIDC tokens. It
+// could be replaced in unit tests.
+var NewSTSOIDCClient = sts.NewOIDCClient
+
+// credentialsCache caches temporary credentials until they are about to expire.
+type credentialsCache struct {
+	mu      sync.Mutex
+	entries map[string]*AlibabaCredentials
+}
+
+func newCredentialsCache() *credentialsCache {
+	return &credentialsCache{entries: map[string]*AlibabaCredentials{}}
+}
+
+// get returns the cached credentials of key, or refreshes them with fn if they
+// are missing or about to expire. The lock isn't held while refreshing, so one
+// slow refresh doesn't block the others.
+func (c *credentialsCache) get(key string, fn func() (*AlibabaCredentials, error)) (*AlibabaCredentials, error) {
+	c.mu.Lock()
+	cred, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && !isExpiring(cred.Expiration) {
+		return cred, nil
+	}
+
+	cred, err := fn()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, v := range c.entries {
+		if isExpiring(v.Expiration) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = cred
+	return cred, nil
+}
+
+// isExpiring checks whether temporary credentials expiring at expiration should
+// be refreshed. Long-lived credentials have a zero expiration and never expire.
+func isExpiring(expiration time.Time) bool {
+	return !expiration.IsZero() && time.Until(expiration) < credentialsRefreshWindow
+}
+
+// getInstanceMetadataCredentials gets the temporary credentials of the RAM role
+// attached to the ECS instance the provider runs on
+func getInstanceMetadataCredentials(ctx context.Context, opts *aliv1beta1.InstanceMetadataCredentials) (*AlibabaCredentials, error) {
+	if opts == nil {
+		opts = &aliv1beta1.InstanceMetadataCredentials{}
+	}
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = metadata.DefaultEndpoint
+	}
+
+	return instanceMetadataCredentials.get(endpoint+"/"+opts.RoleName, func() (*AlibabaCredentials, error) {
+		cred, err := metadata.NewClient(endpoint).GetRoleCredentials(ctx, opts.RoleName)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrGetInstanceMetadataCredentials)
+		}
+		return &AlibabaCredentials{
+			AccessKeyID:     cred.AccessKeyID,
+			AccessKeySecret: cred.AccessKeySecret,
+			SecurityToken:   cred.SecurityToken,
+			Expiration:      cred.Expiration,
+		}, nil
+	})
+}
+
+// getOIDCCredentials exchanges the OIDC token in the configured token file for
+// the temporary credentials of a RAM role
+func getOIDCCredentials(ctx context.Context, region string, opts *aliv1beta1.OIDCCredentials) (*AlibabaCredentials, error) {
+	if opts == nil {
+		opts = &aliv1beta1.OIDCCredentials{}
+	}
+	req := &sts.AssumeRoleWithOIDCRequest{
+		RoleARN:         valueOrEnv(opts.RoleARN, EnvRoleARN),
+		OIDCProviderARN: valueOrEnv(opts.ProviderARN, EnvOIDCProviderARN),
+		RoleSessionName: opts.RoleSessionName,
+	}
+	tokenFile := valueOrEnv(opts.TokenFile, EnvOIDCTokenFile)
+	if tokenFile == "" || req.OIDCProviderARN == "" || req.RoleARN == "" {
+		return nil, errors.New(ErrOIDCNotComplete)
+	}
+	if req.RoleSessionName == "" {
+		req.RoleSessionName = DefaultRoleSessionName
+	}
+	if opts.DurationSeconds != nil {
+		req.DurationSeconds = *opts.DurationSeconds
+	}
+
+	endpoint := sts.DefaultEndpoint
+	if region != "" {
+		endpoint = fmt.Sprintf("https://sts.%s.%s", region, Domain)
+	}
+
+	key := strings.Join([]string{endpoint, tokenFile, req.OIDCProviderARN, req.RoleARN, req.RoleSessionName}, "/")
+	return oidcCredentials.get(key, func() (*AlibabaCredentials, error) {
+		// The token file is rotated by kubelet, so it's read on every refresh.
+		token, err := ioutil.ReadFile(tokenFile) //nolint:gosec
+		if err != nil {
+			return nil, errors.Wrap(err, errReadOIDCToken)
+		}
+		req.OIDCToken = strings.TrimSpace(string(token))
+
+		cred, err := NewSTSOIDCClient(endpoint).AssumeRoleWithOIDC(ctx, req)
+		if err != nil {
+			return nil, errors.Wrap(err, ErrAssumeRoleWithOIDC)
+		}
+		return &AlibabaCredentials{
+			AccessKeyID:     cred.AccessKeyID,
+			AccessKeySecret: cred.AccessKeySecret,
+			SecurityToken:   cred.SecurityToken,
+			Expiration:      cred.Expiration,
+		}, nil
+	})
+}
+
+// valueOrEnv returns v, or the value of the environment variable env if v is empty
+func valueOrEnv(v, env string) string {
+	if v != "" {
+		return v
+	}
+	return os.Getenv(env)
+}
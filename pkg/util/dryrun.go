@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AnnotationDryRun is the annotation of a managed resource which, when set to
+// "true", only plans the changes of its external resource.
+const AnnotationDryRun = "alibaba.crossplane.io/dry-run"
+
+// TypePlanned is the type of the condition reporting the change of the
+// external resource of a managed resource planned in dry-run mode.
+const TypePlanned xpv1.ConditionType = "Planned"
+
+// Reasons of the Planned condition and of the events of the planned changes.
+const (
+	ReasonPlannedCreate xpv1.ConditionReason = "DryRunCreate"
+	ReasonPlannedUpdate xpv1.ConditionReason = "DryRunUpdate"
+	ReasonPlannedDelete xpv1.ConditionReason = "DryRunDelete"
+	ReasonNoChanges     xpv1.ConditionReason = "DryRunNoChanges"
+)
+
+type planKey struct{}
+
+// plan is the API requests which would be sent to change an external
+// resource.
+type plan struct {
+	requests []string
+}
+
+func (p *plan) String() string {
+	return "Would send " + strings.Join(p.requests, "; ")
+}
+
+// IsDryRun returns true if the changes of the external resource operated on
+// with ctx are only planned. Observing an external resource must not change it
+// in dry-run mode.
+func IsDryRun(ctx context.Context) bool {
+	_, ok := ctx.Value(planKey{}).(*plan)
+	return ok
+}
+
+// Plan returns true if the changes of the external resource operated on with
+// ctx are only planned, in which case the API request of operation is added to
+// the plan instead of being sent. The ExternalClients call it with each request
+// changing an external resource, right before sending it.
+func Plan(ctx context.Context, operation string, request interface{}) bool {
+	p, ok := ctx.Value(planKey{}).(*plan)
+	if !ok {
+		return false
+	}
+	b, err := json.Marshal(request)
+	if err != nil {
+		b = []byte(fmt.Sprintf("%+v", request))
+	}
+	p.requests = append(p.requests, operation+" "+string(b))
+	return true
+}
+
+// DryRun wraps ec so that the changes of the external resources of the
+// managed resources annotated for dry-run, or of all of them if all is true,
+// are only planned. The API requests that would be sent to create, update or
+// delete an external resource are recorded in the Planned condition of its
+// managed resource, and as an event when they change, instead of being sent.
+// The managed resources being deleted keep their finalizers until dry-run is
+// turned off, since their external resources aren't deleted.
+func DryRun(ec managed.ExternalConnecter, all bool, r event.Recorder) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ext, err := ec.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		if !all && mg.GetAnnotations()[AnnotationDryRun] != "true" {
+			return ext, nil
+		}
+		return &dryRunExternal{client: ext, record: r}, nil
+	})
+}
+
+type dryRunExternal struct {
+	client managed.ExternalClient
+	record event.Recorder
+}
+
+func (e *dryRunExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.client.Observe(context.WithValue(ctx, planKey{}, &plan{}), mg)
+	if err != nil {
+		return o, err
+	}
+	// The late initialized parameters aren't saved in dry-run mode.
+	o.ResourceLateInitialized = false
+	if o.ResourceExists && o.ResourceUpToDate && !meta.WasDeleted(mg) {
+		e.plan(mg, ReasonNoChanges, &plan{})
+	}
+	return o, nil
+}
+
+func (e *dryRunExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	p := &plan{}
+	if _, err := e.client.Create(context.WithValue(ctx, planKey{}, p), mg); err != nil {
+		return managed.ExternalCreation{}, err
+	}
+	e.plan(mg, ReasonPlannedCreate, p)
+	return managed.ExternalCreation{}, nil
+}
+
+func (e *dryRunExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	p := &plan{}
+	if _, err := e.client.Update(context.WithValue(ctx, planKey{}, p), mg); err != nil {
+		return managed.ExternalUpdate{}, err
+	}
+	e.plan(mg, ReasonPlannedUpdate, p)
+	return managed.ExternalUpdate{}, nil
+}
+
+func (e *dryRunExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	p := &plan{}
+	if err := e.client.Delete(context.WithValue(ctx, planKey{}, p), mg); err != nil {
+		return err
+	}
+	e.plan(mg, ReasonPlannedDelete, p)
+	return nil
+}
+
+// plan reports the plan p of the change of the external resource of mg. The
+// plan is only recorded as an event when it changes, rather than on every poll.
+func (e *dryRunExternal) plan(mg resource.Managed, reason xpv1.ConditionReason, p *plan) {
+	c := xpv1.Condition{
+		Type:               TypePlanned,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoChanges,
+	}
+	if len(p.requests) > 0 {
+		c.Status = corev1.ConditionTrue
+		c.Reason = reason
+		c.Message = p.String()
+		if !mg.GetCondition(TypePlanned).Equal(c) {
+			e.record.Event(mg, event.Normal(event.Reason(reason), c.Message))
+		}
+	}
+	mg.SetConditions(c)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestDryRun(t *testing.T) {
+	now := metav1.Now()
+	dryRun := map[string]string{AnnotationDryRun: "true"}
+
+	type want struct {
+		o       managed.ExternalObservation
+		reason  xpv1.ConditionReason
+		message string
+		events  int
+		changed bool
+	}
+	cases := map[string]struct {
+		reason string
+		all    bool
+		mg     *fake.Managed
+		o      managed.ExternalObservation
+		update []string
+		want   want
+	}{
+		"NotDryRun": {
+			reason: "Managed resources not annotated for dry-run should be changed",
+			mg:     &fake.Managed{},
+			o:      managed.ExternalObservation{ResourceExists: false},
+			want:   want{changed: true},
+		},
+		"Create": {
+			reason: "Creating the external resource should be planned from its request, and recorded once",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: dryRun}},
+			o:      managed.ExternalObservation{ResourceExists: false},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: false},
+				reason:  ReasonPlannedCreate,
+				message: `Would send CreateBucket {"Bucket":"cool"}`,
+				events:  1,
+			},
+		},
+		"UpdateAll": {
+			reason: "Updating the external resource should be planned when all the managed resources are in dry-run mode",
+			all:    true,
+			mg:     &fake.Managed{},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			update: []string{"cool"},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				reason:  ReasonPlannedUpdate,
+				message: `Would send TagResources ["cool"]`,
+				events:  1,
+			},
+		},
+		"UpdateNothing": {
+			reason: "No changes should be planned if updating the external resource sends no requests",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: dryRun}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				reason: ReasonNoChanges,
+			},
+		},
+		"Delete": {
+			reason: "Deleting the external resource should be planned and the managed resource kept",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: dryRun, DeletionTimestamp: &now}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason:  ReasonPlannedDelete,
+				message: `Would send DeleteBucket "cool"`,
+				events:  1,
+			},
+		},
+		"NoChanges": {
+			reason: "No changes should be planned for an external resource that is up to date",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: dryRun}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: ReasonNoChanges,
+			},
+		},
+		"LateInitialized": {
+			reason: "The late initialized parameters should not be saved in dry-run mode",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: dryRun}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				reason: ReasonNoChanges,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			changed := false
+			r := &recorder{}
+			ec := DryRun(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return tc.o, nil
+					},
+					CreateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+						if Plan(ctx, "CreateBucket", map[string]string{"Bucket": "cool"}) {
+							return managed.ExternalCreation{}, nil
+						}
+						changed = true
+						return managed.ExternalCreation{}, nil
+					},
+					UpdateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+						if len(tc.update) == 0 || Plan(ctx, "TagResources", tc.update) {
+							return managed.ExternalUpdate{}, nil
+						}
+						changed = true
+						return managed.ExternalUpdate{}, nil
+					},
+					DeleteFn: func(ctx context.Context, _ resource.Managed) error {
+						if Plan(ctx, "DeleteBucket", "cool") {
+							return nil
+						}
+						changed = true
+						return nil
+					},
+				}, nil
+			}), tc.all, r)
+
+			// The managed resource is reconciled twice, like on two polls.
+			var o managed.ExternalObservation
+			for i := 0; i < 2; i++ {
+				ext, _ := ec.Connect(context.Background(), tc.mg)
+				o, _ = ext.Observe(context.Background(), tc.mg)
+				switch {
+				case meta.WasDeleted(tc.mg):
+					_ = ext.Delete(context.Background(), tc.mg)
+				case !o.ResourceExists:
+					_, _ = ext.Create(context.Background(), tc.mg)
+				case !o.ResourceUpToDate:
+					_, _ = ext.Update(context.Background(), tc.mg)
+				}
+			}
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			got := tc.mg.GetCondition(TypePlanned)
+			if got.Reason != tc.want.reason || got.Message != tc.want.message {
+				t.Errorf("\n%s\nReconcile: want %s condition %q %q, got %q %q\n", tc.reason, TypePlanned, tc.want.reason, tc.want.message, got.Reason, got.Message)
+			}
+			if len(r.events) != tc.want.events {
+				t.Errorf("\n%s\nReconcile: want %d events, got %d\n", tc.reason, tc.want.events, len(r.events))
+			}
+			if changed != tc.want.changed {
+				t.Errorf("\n%s\nReconcile: want changed %t, got %t\n", tc.reason, tc.want.changed, changed)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	if Plan(context.Background(), "CreateBucket", "cool") {
+		t.Errorf("Plan(...): want the request to be sent when not in dry-run mode")
+	}
+	if IsDryRun(context.Background()) {
+		t.Errorf("IsDryRun(...): want false when not in dry-run mode")
+	}
+}
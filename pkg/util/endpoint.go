@@ -22,10 +22,13 @@ import (
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
+	database "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
 	nas "github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
 	oss "github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	redis "github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
 	slb "github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
 	sls "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
 )
 
 // Domain is Alibaba Cloud Domain
@@ -36,28 +39,122 @@ var (
 	errCloudResourceNotSupported = "cloud resource is not supported"
 )
 
-// GetEndpoint gets endpoints for all cloud resources
+// GetEndpoint gets the public endpoint of the service of a cloud resource. The
+// region of the cloud resource takes precedence over region.
 func GetEndpoint(res runtime.Object, region string) (string, error) {
-	if res == nil || res.GetObjectKind() == nil {
+	service, err := GetService(res)
+	if err != nil {
+		return "", err
+	}
+	if r := GetResourceRegion(res); r != "" {
+		region = r
+	}
+	return GetServiceEndpoint(service, region, false)
+}
+
+// GetResourceRegion gets the region specified in the parameters of a cloud
+// resource. An empty region is returned if it's not specified.
+func GetResourceRegion(res runtime.Object) string {
+	var region *string
+	switch cr := res.(type) {
+	case *database.RDSInstance:
+		region = cr.Spec.ForProvider.Region
+	case *redis.RedisInstance:
+		region = cr.Spec.ForProvider.Region
+	case *oss.Bucket:
+		region = cr.Spec.Region
+	case *nas.NASFileSystem:
+		region = cr.Spec.Region
+	case *nas.NASMountTarget:
+		region = cr.Spec.ForProvider.Region
+	case *slb.CLB:
+		region = cr.Spec.ForProvider.Region
+	case *sls.Project:
+		region = cr.Spec.ForProvider.Region
+	case *sls.LogStore:
+		region = cr.Spec.ForProvider.Region
+	case *sls.Logtail:
+		region = cr.Spec.ForProvider.Region
+	case *sls.LogstoreIndex:
+		region = cr.Spec.ForProvider.Region
+	case *sls.MachineGroup:
+		region = cr.Spec.ForProvider.Region
+	case *sls.MachineGroupBinding:
+		region = cr.Spec.ForProvider.Region
+	}
+	if region == nil {
+		return ""
+	}
+	return *region
+}
+
+// GetService gets the name of the service which manages a cloud resource
+func GetService(res runtime.Object) (string, error) {
+	switch res.(type) {
+	case *database.RDSInstance:
+		return ServiceRDS, nil
+	case *redis.RedisInstance:
+		return ServiceRedis, nil
+	case *oss.Bucket:
+		return ServiceOSS, nil
+	case *nas.NASFileSystem, *nas.NASMountTarget:
+		return ServiceNAS, nil
+	case *slb.CLB:
+		return ServiceSLB, nil
+	case *sls.Project, *sls.LogStore, *sls.Logtail, *sls.LogstoreIndex, *sls.MachineGroup, *sls.MachineGroupBinding:
+		return ServiceSLS, nil
+	default:
 		return "", errors.New(errCloudResourceNotSupported)
 	}
+}
 
-	if region == "" && res.GetObjectKind().GroupVersionKind().Kind != slb.CLBKind {
+// GetServiceEndpoint gets the endpoint of a service in region. The VPC
+// internal endpoint is returned if internal is true. An empty endpoint is
+// returned for the services whose SDK resolves the public endpoint itself.
+func GetServiceEndpoint(service, region string, internal bool) (string, error) {
+	if !internal && (service == ServiceRDS || service == ServiceRedis) {
+		return "", nil
+	}
+	if region == "" && (internal || service != ServiceSLB) {
 		return "", errors.New(errRegionNotValid)
 	}
 
-	var endpoint string
-	switch res.GetObjectKind().GroupVersionKind().Kind {
-	case oss.BucketKind:
-		endpoint = fmt.Sprintf("http://oss-%s.%s", region, Domain)
-	case nas.NASFileSystemKind, nas.NASMountTargetKind:
-		endpoint = fmt.Sprintf("nas.%s.%s", region, Domain)
-	case slb.CLBKind:
-		endpoint = fmt.Sprintf("slb.%s", Domain)
-	case sls.ProjectGroupKind:
-		endpoint = fmt.Sprintf("%s.log.%s", region, Domain)
+	switch service {
+	case ServiceRDS:
+		return fmt.Sprintf("rds-vpc.%s.%s", region, Domain), nil
+	case ServiceRedis:
+		return fmt.Sprintf("r-kvstore-vpc.%s.%s", region, Domain), nil
+	case ServiceOSS:
+		if internal {
+			return fmt.Sprintf("http://oss-%s-internal.%s", region, Domain), nil
+		}
+		return fmt.Sprintf("http://oss-%s.%s", region, Domain), nil
+	case ServiceNAS:
+		if internal {
+			return fmt.Sprintf("nas-vpc.%s.%s", region, Domain), nil
+		}
+		return fmt.Sprintf("nas.%s.%s", region, Domain), nil
+	case ServiceSLB:
+		if internal {
+			return fmt.Sprintf("slb-vpc.%s.%s", region, Domain), nil
+		}
+		return fmt.Sprintf("slb.%s", Domain), nil
+	case ServiceSLS:
+		if internal {
+			return fmt.Sprintf("%s-intranet.log.%s", region, Domain), nil
+		}
+		return fmt.Sprintf("%s.log.%s", region, Domain), nil
 	default:
 		return "", errors.New(errCloudResourceNotSupported)
 	}
-	return endpoint, nil
+}
+
+// ResolveEndpoint gets the endpoint of a service in region for a
+// ProviderConfig. The endpoint configured for the service in the
+// ProviderConfig takes precedence.
+func ResolveEndpoint(pc *aliv1beta1.ProviderConfig, service, region string) (string, error) {
+	if endpoint := pc.Spec.Endpoints[service]; endpoint != "" {
+		return endpoint, nil
+	}
+	return GetServiceEndpoint(service, region, pc.Spec.UseInternalEndpoints)
 }
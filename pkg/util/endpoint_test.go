@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
 )
 
 func TestGetEndpoint(t *testing.T) {
@@ -35,6 +36,7 @@ func TestGetEndpoint(t *testing.T) {
 		err      error
 	}
 	region := "cn-beijing"
+	otherRegion := "cn-shanghai"
 	cr := v1alpha1.Bucket{TypeMeta: metav1.TypeMeta{Kind: "Bucket"}}
 
 	cases := map[string]struct {
@@ -66,6 +68,19 @@ func TestGetEndpoint(t *testing.T) {
 				err:      nil,
 			},
 		},
+		"ResourceRegionPreferred": {
+			res: &v1alpha1.Bucket{
+				TypeMeta: metav1.TypeMeta{Kind: "Bucket"},
+				Spec: v1alpha1.BucketSpec{
+					BucketParameter: v1alpha1.BucketParameter{Region: &otherRegion},
+				},
+			},
+			region: region,
+			want: want{
+				endpoint: fmt.Sprintf("http://oss-%s.%s", otherRegion, Domain),
+				err:      nil,
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -80,3 +95,68 @@ func TestGetEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveEndpoint(t *testing.T) {
+	type args struct {
+		pc      *aliv1beta1.ProviderConfig
+		service string
+		region  string
+	}
+	type want struct {
+		endpoint string
+		err      error
+	}
+	region := "cn-beijing"
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"PublicEndpoint": {
+			args: args{pc: &aliv1beta1.ProviderConfig{}, service: ServiceSLS, region: region},
+			want: want{endpoint: fmt.Sprintf("%s.log.%s", region, Domain)},
+		},
+		"InternalEndpoint": {
+			args: args{
+				pc:      &aliv1beta1.ProviderConfig{Spec: aliv1beta1.ProviderConfigSpec{UseInternalEndpoints: true}},
+				service: ServiceOSS,
+				region:  region,
+			},
+			want: want{endpoint: fmt.Sprintf("http://oss-%s-internal.%s", region, Domain)},
+		},
+		"EndpointResolvedBySDK": {
+			args: args{pc: &aliv1beta1.ProviderConfig{}, service: ServiceRDS, region: region},
+			want: want{endpoint: ""},
+		},
+		"OverriddenEndpoint": {
+			args: args{
+				pc: &aliv1beta1.ProviderConfig{Spec: aliv1beta1.ProviderConfigSpec{
+					UseInternalEndpoints: true,
+					Endpoints:            map[string]string{ServiceSLS: "cn-shanghai-finance-1.log.aliyuncs.com"},
+				}},
+				service: ServiceSLS,
+				region:  region,
+			},
+			want: want{endpoint: "cn-shanghai-finance-1.log.aliyuncs.com"},
+		},
+		"EmptyRegion": {
+			args: args{
+				pc:      &aliv1beta1.ProviderConfig{Spec: aliv1beta1.ProviderConfigSpec{UseInternalEndpoints: true}},
+				service: ServiceRedis,
+			},
+			want: want{err: errors.New(errRegionNotValid)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			endpoint, err := ResolveEndpoint(tc.args.pc, tc.args.service, tc.args.region)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nResolveEndpoint(...) -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.endpoint, endpoint); diff != "" {
+				t.Errorf("\nResolveEndpoint(...) -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
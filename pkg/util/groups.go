@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errEnableAndDisableGroups = "cannot both enable and disable API groups"
+	errFmtUnknownGroup        = "unknown API group %q, must be one of %s"
+)
+
+// GroupFilter selects the API groups of the managed resources whose kinds are
+// installed and whose controllers are started. The zero value selects all of
+// them.
+type GroupFilter struct {
+	enabled  map[string]bool
+	disabled map[string]bool
+}
+
+// NewGroupFilter returns a GroupFilter selecting either only the enabled API
+// groups, or all of the known API groups but the disabled ones. The groups
+// may be given in full, e.g. sls.alibaba.crossplane.io, or by their short
+// name, e.g. sls, and several of them may be separated by commas.
+func NewGroupFilter(known, enabled, disabled []string) (GroupFilter, error) {
+	enabled, disabled = splitGroups(enabled), splitGroups(disabled)
+	if len(enabled) > 0 && len(disabled) > 0 {
+		return GroupFilter{}, errors.New(errEnableAndDisableGroups)
+	}
+
+	names := make(map[string]bool, len(known))
+	for _, g := range known {
+		names[shortGroup(g)] = true
+	}
+	toSet := func(groups []string) (map[string]bool, error) {
+		if len(groups) == 0 {
+			return nil, nil
+		}
+		set := make(map[string]bool, len(groups))
+		for _, g := range groups {
+			if !names[shortGroup(g)] {
+				return nil, errors.Errorf(errFmtUnknownGroup, g, strings.Join(sortedKeys(names), ", "))
+			}
+			set[shortGroup(g)] = true
+		}
+		return set, nil
+	}
+
+	f := GroupFilter{}
+	var err error
+	if f.enabled, err = toSet(enabled); err != nil {
+		return GroupFilter{}, err
+	}
+	if f.disabled, err = toSet(disabled); err != nil {
+		return GroupFilter{}, err
+	}
+	return f, nil
+}
+
+// Enabled returns true if the API group is selected.
+func (f GroupFilter) Enabled(group string) bool {
+	g := shortGroup(group)
+	if f.enabled != nil {
+		return f.enabled[g]
+	}
+	return !f.disabled[g]
+}
+
+// shortGroup returns the short name of an API group, e.g. sls for
+// sls.alibaba.crossplane.io.
+func shortGroup(group string) string {
+	return strings.TrimSuffix(strings.TrimSpace(group), groupSuffix)
+}
+
+func splitGroups(groups []string) []string {
+	var split []string
+	for _, g := range groups {
+		for _, s := range strings.Split(g, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				split = append(split, s)
+			}
+		}
+	}
+	return split
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestGroupFilter(t *testing.T) {
+	known := []string{"database.alibaba.crossplane.io", "oss.alibaba.crossplane.io", "sls.alibaba.crossplane.io"}
+
+	type want struct {
+		enabled []string
+		err     error
+	}
+	cases := map[string]struct {
+		enabled  []string
+		disabled []string
+		want     want
+	}{
+		"All": {
+			want: want{enabled: known},
+		},
+		"Enabled": {
+			enabled: []string{"sls,oss.alibaba.crossplane.io"},
+			want:    want{enabled: []string{"oss.alibaba.crossplane.io", "sls.alibaba.crossplane.io"}},
+		},
+		"Disabled": {
+			disabled: []string{"database"},
+			want:     want{enabled: []string{"oss.alibaba.crossplane.io", "sls.alibaba.crossplane.io"}},
+		},
+		"EnabledAndDisabled": {
+			enabled:  []string{"sls"},
+			disabled: []string{"database"},
+			want:     want{err: errors.New(errEnableAndDisableGroups)},
+		},
+		"Unknown": {
+			enabled: []string{"ecs"},
+			want:    want{err: errors.Errorf(errFmtUnknownGroup, "ecs", "database, oss, sls")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, err := NewGroupFilter(known, tc.enabled, tc.disabled)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nNewGroupFilter(...): -want error, +got error:\n%s\n", diff)
+			}
+			if err != nil {
+				return
+			}
+			var enabled []string
+			for _, g := range known {
+				if f.Enabled(g) {
+					enabled = append(enabled, g)
+				}
+			}
+			if diff := cmp.Diff(tc.want.enabled, enabled); diff != "" {
+				t.Errorf("\nEnabled(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
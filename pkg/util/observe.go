@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationManagementPolicy is the annotation of a managed resource
+	// setting how its external resource is managed.
+	AnnotationManagementPolicy = "alibaba.crossplane.io/management-policy"
+
+	// ManagementPolicyObserveOnly only observes the external resource of a
+	// managed resource, which is never created, updated or deleted. The
+	// external resource is left as is when the managed resource is deleted.
+	ManagementPolicyObserveOnly = "ObserveOnly"
+
+	errObserveOnlyNotFound = "external resource does not exist and is not created with the " + ManagementPolicyObserveOnly + " management policy"
+)
+
+// TypeDrifted is the type of the condition reporting whether the external
+// resource of an observe-only managed resource differs from its desired state.
+const TypeDrifted xpv1.ConditionType = "Drifted"
+
+// Reasons of the Drifted condition.
+const (
+	ReasonDrifted    xpv1.ConditionReason = "ExternalResourceDrifted"
+	ReasonNotDrifted xpv1.ConditionReason = "ExternalResourceUpToDate"
+)
+
+// Drifted returns a condition indicating that the external resource differs
+// from the desired state of the managed resource.
+func Drifted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDrifted,
+		Message:            "The external resource is not updated with the " + ManagementPolicyObserveOnly + " management policy",
+	}
+}
+
+// NotDrifted returns a condition indicating that the external resource
+// matches the desired state of the managed resource.
+func NotDrifted() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDrifted,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotDrifted,
+	}
+}
+
+// IsObserveOnly returns true if the external resource of the managed resource
+// is only observed.
+func IsObserveOnly(mg resource.Managed) bool {
+	return mg.GetAnnotations()[AnnotationManagementPolicy] == ManagementPolicyObserveOnly
+}
+
+// ObserveOnly wraps ec so that the external resources of the managed
+// resources annotated with the ObserveOnly management policy are only
+// observed. Their drift from the desired state is reported in the Drifted
+// condition instead of being corrected.
+func ObserveOnly(ec managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ext, err := ec.Connect(ctx, mg)
+		if err != nil {
+			return nil, err
+		}
+		return &observeOnlyExternal{client: ext}, nil
+	})
+}
+
+type observeOnlyExternal struct {
+	client managed.ExternalClient
+}
+
+func (e *observeOnlyExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	if !IsObserveOnly(mg) {
+		return e.client.Observe(ctx, mg)
+	}
+	// Reporting that the external resource doesn't exist lets the managed
+	// resource be deleted without deleting its external resource.
+	if meta.WasDeleted(mg) {
+		return managed.ExternalObservation{}, nil
+	}
+	o, err := e.client.Observe(ctx, mg)
+	if err != nil || !o.ResourceExists {
+		return o, err
+	}
+	if o.ResourceUpToDate {
+		mg.SetConditions(NotDrifted())
+	} else {
+		mg.SetConditions(Drifted())
+		o.ResourceUpToDate = true
+	}
+	return o, nil
+}
+
+func (e *observeOnlyExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalCreation{}, errors.New(errObserveOnlyNotFound)
+	}
+	return e.client.Create(ctx, mg)
+}
+
+func (e *observeOnlyExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if IsObserveOnly(mg) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return e.client.Update(ctx, mg)
+}
+
+func (e *observeOnlyExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if IsObserveOnly(mg) {
+		return nil
+	}
+	return e.client.Delete(ctx, mg)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserveOnly(t *testing.T) {
+	observeOnly := map[string]string{AnnotationManagementPolicy: ManagementPolicyObserveOnly}
+	now := metav1.Now()
+
+	type want struct {
+		o       managed.ExternalObservation
+		drifted corev1.ConditionStatus
+		created bool
+		err     error
+	}
+	cases := map[string]struct {
+		reason string
+		mg     *fake.Managed
+		o      managed.ExternalObservation
+		want   want
+	}{
+		"Managed": {
+			reason: "Managed resources without the ObserveOnly management policy should be managed as usual",
+			mg:     &fake.Managed{},
+			o:      managed.ExternalObservation{ResourceExists: false},
+			want:   want{drifted: corev1.ConditionUnknown, created: true},
+		},
+		"Drifted": {
+			reason: "Drift of observe-only managed resources should be reported instead of corrected",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: observeOnly}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				drifted: corev1.ConditionTrue,
+			},
+		},
+		"UpToDate": {
+			reason: "Observe-only managed resources that are up to date should not be drifted",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: observeOnly}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want: want{
+				o:       managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				drifted: corev1.ConditionFalse,
+			},
+		},
+		"NotFound": {
+			reason: "External resources of observe-only managed resources should not be created",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: observeOnly}},
+			o:      managed.ExternalObservation{ResourceExists: false},
+			want:   want{drifted: corev1.ConditionUnknown, err: errors.New(errObserveOnlyNotFound)},
+		},
+		"Deleted": {
+			reason: "Observe-only managed resources being deleted should be released without deleting their external resources",
+			mg:     &fake.Managed{ObjectMeta: metav1.ObjectMeta{Annotations: observeOnly, DeletionTimestamp: &now}},
+			o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+			want:   want{drifted: corev1.ConditionUnknown},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			created := false
+			ec := ObserveOnly(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return tc.o, nil
+					},
+					CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+						created = true
+						return managed.ExternalCreation{}, nil
+					},
+				}, nil
+			}))
+			ext, _ := ec.Connect(context.Background(), tc.mg)
+
+			o, _ := ext.Observe(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.o, o); diff != "" {
+				t.Errorf("\n%s\nObserve(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			got := corev1.ConditionUnknown
+			for _, c := range tc.mg.Conditions {
+				if c.Type == TypeDrifted {
+					got = c.Status
+				}
+			}
+			if got != tc.want.drifted {
+				t.Errorf("\n%s\nObserve(...): want %s condition %s, got %s\n", tc.reason, TypeDrifted, tc.want.drifted, got)
+			}
+			if o.ResourceExists {
+				return
+			}
+			_, err := ext.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCreate(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if created != tc.want.created {
+				t.Errorf("\n%s\nCreate(...): want created %t, got %t\n", tc.reason, tc.want.created, created)
+			}
+		})
+	}
+}
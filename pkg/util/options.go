@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+)
+
+const (
+	// groupSuffix is the suffix of the API groups of the managed resources,
+	// which may be omitted when configuring a group, e.g. sls for
+	// sls.alibaba.crossplane.io.
+	groupSuffix = ".alibaba.crossplane.io"
+
+	errFmtInvalidMaxConcurrentReconciles = "invalid max concurrent reconciles %q of group %q"
+)
+
+// ControllerOptions configures the controllers of the provider.
+type ControllerOptions struct {
+	// Logger of the controllers.
+	Logger logging.Logger
+
+	// GlobalRateLimiter limits the rate of the reconciles of all the
+	// controllers.
+	GlobalRateLimiter workqueue.RateLimiter
+
+	// PollInterval is how often a managed resource is checked for drift from
+	// its desired state.
+	PollInterval time.Duration
+
+	// MaxConcurrentReconciles is the maximum number of managed resources of a
+	// kind reconciled concurrently.
+	MaxConcurrentReconciles int
+
+	// GroupMaxConcurrentReconciles overrides MaxConcurrentReconciles for the
+	// kinds of an API group, e.g. sls.alibaba.crossplane.io, or sls for short.
+	GroupMaxConcurrentReconciles map[string]int
+
+	// Groups selects the API groups whose controllers are started.
+	Groups GroupFilter
+
+	// DryRun only plans the changes of the external resources of all the
+	// managed resources, instead of those annotated for dry-run.
+	DryRun bool
+}
+
+// ForGroup returns the options of the controllers of the kinds of group.
+func (o ControllerOptions) ForGroup(group string) controller.Options {
+	opts := controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+	if n, ok := o.GroupMaxConcurrentReconciles[group]; ok {
+		opts.MaxConcurrentReconciles = n
+	} else if n, ok := o.GroupMaxConcurrentReconciles[strings.TrimSuffix(group, groupSuffix)]; ok {
+		opts.MaxConcurrentReconciles = n
+	}
+	if o.GlobalRateLimiter != nil {
+		opts.RateLimiter = ratelimiter.NewDefaultManagedRateLimiter(o.GlobalRateLimiter)
+	}
+	return opts
+}
+
+// ParseGroupMaxConcurrentReconciles parses the max concurrent reconciles of
+// the API groups, e.g. {"sls": "10"}.
+func ParseGroupMaxConcurrentReconciles(groups map[string]string) (map[string]int, error) {
+	parsed := make(map[string]int, len(groups))
+	for group, v := range groups {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, errors.Errorf(errFmtInvalidMaxConcurrentReconciles, v, group)
+		}
+		parsed[group] = n
+	}
+	return parsed, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestControllerOptionsForGroup(t *testing.T) {
+	o := ControllerOptions{
+		MaxConcurrentReconciles: 1,
+		GroupMaxConcurrentReconciles: map[string]int{
+			"sls":                            10,
+			"database.alibaba.crossplane.io": 5,
+		},
+	}
+	cases := map[string]int{
+		"sls.alibaba.crossplane.io":      10,
+		"database.alibaba.crossplane.io": 5,
+		"oss.alibaba.crossplane.io":      1,
+		"alibaba.crossplane.io":          1,
+	}
+	for group, want := range cases {
+		if got := o.ForGroup(group).MaxConcurrentReconciles; got != want {
+			t.Errorf("ForGroup(%q).MaxConcurrentReconciles: want %d, got %d", group, want, got)
+		}
+	}
+	if o.ForGroup("sls.alibaba.crossplane.io").RateLimiter != nil {
+		t.Errorf("ForGroup(...).RateLimiter: want the default rate limiter without a global rate limiter")
+	}
+}
+
+func TestParseGroupMaxConcurrentReconciles(t *testing.T) {
+	type want struct {
+		groups map[string]int
+		err    error
+	}
+	cases := map[string]struct {
+		groups map[string]string
+		want   want
+	}{
+		"Valid": {
+			groups: map[string]string{"sls": "10", "oss": "2"},
+			want:   want{groups: map[string]int{"sls": 10, "oss": 2}},
+		},
+		"NotANumber": {
+			groups: map[string]string{"sls": "many"},
+			want:   want{err: errors.Errorf(errFmtInvalidMaxConcurrentReconciles, "many", "sls")},
+		},
+		"Zero": {
+			groups: map[string]string{"sls": "0"},
+			want:   want{err: errors.Errorf(errFmtInvalidMaxConcurrentReconciles, "0", "sls")},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseGroupMaxConcurrentReconciles(tc.groups)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nParseGroupMaxConcurrentReconciles(...): -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.groups, got); diff != "" {
+				t.Errorf("\nParseGroupMaxConcurrentReconciles(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -18,14 +18,23 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
 )
 
 const (
@@ -36,13 +45,35 @@ const (
 	errFailedToExtractCredentials = "failed to extract Alibaba credentials"
 	// ErrAccessKeyNotComplete is the error of not existing of AccessKeyID or AccessKeySecret
 	ErrAccessKeyNotComplete = "AccessKeyID or AccessKeySecret not existed"
+	// ErrAssumeRole is the error of failing to assume the RAM role configured in ProviderConfig
+	ErrAssumeRole = "cannot assume RAM role"
+	// ErrFmtProviderConfigUnhealthy is the error of the credentials of ProviderConfig failing validation
+	ErrFmtProviderConfigUnhealthy = "ProviderConfig %q is unhealthy: %s"
+
+	// DefaultRoleSessionName is the role session name used when ProviderConfig doesn't specify one
+	DefaultRoleSessionName = "crossplane-provider-alibaba"
+	// DefaultSTSRegion is the region used to reach STS when ProviderConfig doesn't specify one
+	DefaultSTSRegion = "cn-hangzhou"
 )
 
+// NewSTSClient creates the STS client used to exchange credentials. It could be
+// replaced in unit tests.
+var NewSTSClient = sts.NewClient
+
 // AlibabaCredentials represents ak/sk, stsToken(maybe) information
 type AlibabaCredentials struct {
 	AccessKeyID     string `yaml:"accessKeyId"`
 	AccessKeySecret string `yaml:"accessKeySecret"`
 	SecurityToken   string `yaml:"securityToken"`
+	// Expiration is the time when temporary credentials expire. It's zero for
+	// long-lived credentials.
+	Expiration time.Time `yaml:"-"`
+}
+
+// fingerprint identifies the credentials without revealing them
+func (c *AlibabaCredentials) fingerprint() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.AccessKeyID, c.AccessKeySecret, c.SecurityToken}, "\x00")))
+	return hex.EncodeToString(sum[:])
 }
 
 // GetProviderConfig gets ProviderConfig
@@ -54,30 +85,160 @@ func GetProviderConfig(ctx context.Context, k8sClient client.Client, providerCon
 	return providerConfig, nil
 }
 
+// CheckProviderConfigHealth returns an error if the credentials of
+// ProviderConfig failed the last validation of the ProviderConfig controller.
+// ProviderConfigs which have not been validated yet are considered healthy.
+func CheckProviderConfigHealth(pc *aliv1beta1.ProviderConfig) error {
+	c := pc.Status.GetCondition(xpv1.TypeReady)
+	if c.Status == corev1.ConditionFalse && c.Reason == xpv1.ReasonUnavailable {
+		return errors.Errorf(ErrFmtProviderConfigUnhealthy, pc.Name, c.Message)
+	}
+	return nil
+}
+
 // GetCredentials gets Alibaba credentials from ProviderConfig
 func GetCredentials(ctx context.Context, client client.Client, providerConfigName string) (*AlibabaCredentials, error) {
 	pc, err := GetProviderConfig(ctx, client, providerConfigName)
 	if err != nil {
 		return nil, err
 	}
+	return getCredentials(ctx, client, pc)
+}
 
-	cd := pc.Spec.Credentials
+// getCredentials gets Alibaba credentials from the fetched ProviderConfig
+func getCredentials(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig) (*AlibabaCredentials, error) {
+	return resolveCredentials(ctx, client, pc, nil)
+}
+
+// resolveCredentials resolves the credentials of pc. chain is the names of the
+// ProviderConfigs which get their source credentials from pc, directly or
+// indirectly.
+func resolveCredentials(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig, chain []string) (*AlibabaCredentials, error) {
+	var (
+		cred *AlibabaCredentials
+		err  error
+	)
+	switch cd := pc.Spec.Credentials; cd.Source {
+	case aliv1beta1.CredentialsSourceProviderConfig:
+		cred, err = getSourceCredentials(ctx, client, pc, chain)
+	case aliv1beta1.CredentialsSourceInstanceMetadata:
+		cred, err = getInstanceMetadataCredentials(ctx, cd.InstanceMetadata)
+	case aliv1beta1.CredentialsSourceOIDC:
+		cred, err = getOIDCCredentials(ctx, pc.Spec.Region, cd.OIDC)
+	default:
+		cred, err = extractCredentials(ctx, client, cd)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if pc.Spec.AssumeRole != nil {
+		key := strings.Join([]string{pc.Name, strconv.FormatInt(pc.Generation, 10), cred.fingerprint()}, "/")
+		return assumedRoleCredentials.get(key, func() (*AlibabaCredentials, error) {
+			return assumeRole(ctx, cred, pc.Spec.Region, pc.Spec.AssumeRole)
+		})
+	}
+
+	return cred, nil
+}
+
+// extractCredentials extracts the static credentials in YAML format from the
+// source configured in ProviderConfig
+func extractCredentials(ctx context.Context, client client.Client, cd aliv1beta1.ProviderCredentials) (*AlibabaCredentials, error) {
 	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, client, cd.CommonCredentialSelectors)
 	if err != nil {
 		return nil, errors.Wrap(err, ErrGetCredentials)
 	}
 
 	var cred AlibabaCredentials
-	if err := yaml.Unmarshal(data, &cred); err != nil {
+	if len(data) == 0 && cd.Source == xpv1.CredentialsSourceSecret {
+		// The Secrets of the RedisInstance controller had the credentials
+		// in separate keys rather than in YAML format.
+		if err := extractSecretKeyCredentials(ctx, client, cd.SecretRef, &cred); err != nil {
+			return nil, errors.Wrap(err, ErrGetCredentials)
+		}
+	} else if err := yaml.Unmarshal(data, &cred); err != nil {
 		return nil, errors.Wrap(err, errFailedToExtractCredentials)
 	}
 	if cred.AccessKeyID == "" || cred.AccessKeySecret == "" {
 		return nil, errors.New(ErrAccessKeyNotComplete)
 	}
-
 	return &cred, nil
 }
 
+// extractSecretKeyCredentials extracts the static credentials in the
+// accessKeyId, accessKeySecret and securityToken keys of a Secret.
+func extractSecretKeyCredentials(ctx context.Context, client client.Client, ref *xpv1.SecretKeySelector, cred *AlibabaCredentials) error {
+	s := &corev1.Secret{}
+	if err := client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return err
+	}
+	cred.AccessKeyID = string(s.Data["accessKeyId"])
+	cred.AccessKeySecret = string(s.Data["accessKeySecret"])
+	cred.SecurityToken = string(s.Data["securityToken"])
+	return nil
+}
+
+// getCredentialsVersion returns the version of the static credentials of pc,
+// which changes whenever they are updated: the resourceVersion of their Secret,
+// or the modification time of their file. It's empty for the other sources,
+// whose credentials either never change or expire.
+func getCredentialsVersion(ctx context.Context, client client.Client, pc *aliv1beta1.ProviderConfig) (string, error) {
+	cd := pc.Spec.Credentials
+	switch {
+	case cd.Source == xpv1.CredentialsSourceSecret && cd.SecretRef != nil:
+		s := &corev1.Secret{}
+		if err := client.Get(ctx, types.NamespacedName{Namespace: cd.SecretRef.Namespace, Name: cd.SecretRef.Name}, s); err != nil {
+			return "", errors.Wrap(err, ErrGetCredentials)
+		}
+		return s.ResourceVersion, nil
+	case cd.Source == xpv1.CredentialsSourceFilesystem && cd.Fs != nil:
+		fi, err := os.Stat(cd.Fs.Path)
+		if err != nil {
+			return "", errors.Wrap(err, ErrGetCredentials)
+		}
+		return fi.ModTime().UTC().Format(time.RFC3339Nano), nil
+	}
+	return "", nil
+}
+
+// assumeRole exchanges the source credentials for the temporary credentials of
+// the RAM role configured in ProviderConfig
+func assumeRole(ctx context.Context, src *AlibabaCredentials, region string, opts *aliv1beta1.AssumeRoleOptions) (*AlibabaCredentials, error) {
+	if region == "" {
+		region = DefaultSTSRegion
+	}
+	stsClient, err := NewSTSClient(ctx, src.AccessKeyID, src.AccessKeySecret, src.SecurityToken, region)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrAssumeRole)
+	}
+
+	req := &sts.AssumeRoleRequest{
+		RoleARN:         opts.RoleARN,
+		RoleSessionName: opts.RoleSessionName,
+	}
+	if req.RoleSessionName == "" {
+		req.RoleSessionName = DefaultRoleSessionName
+	}
+	if opts.ExternalID != nil {
+		req.ExternalID = *opts.ExternalID
+	}
+	if opts.DurationSeconds != nil {
+		req.DurationSeconds = *opts.DurationSeconds
+	}
+
+	cred, err := stsClient.AssumeRole(req)
+	if err != nil {
+		return nil, errors.Wrap(err, ErrAssumeRole)
+	}
+	return &AlibabaCredentials{
+		AccessKeyID:     cred.AccessKeyID,
+		AccessKeySecret: cred.AccessKeySecret,
+		SecurityToken:   cred.SecurityToken,
+		Expiration:      cred.Expiration,
+	}, nil
+}
+
 // GetRegion gets regions from ProviderConfig
 func GetRegion(ctx context.Context, client client.Client, providerConfigName string) (string, error) {
 	pc, err := GetProviderConfig(ctx, client, providerConfigName)
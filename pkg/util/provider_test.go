@@ -20,15 +20,23 @@ package util
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sts"
 )
 
 func TestGetCredentials(t *testing.T) {
@@ -66,6 +74,44 @@ func TestGetCredentials(t *testing.T) {
 				err:  errors.Wrap(errors.New("E1"), ErrGetProviderConfig),
 			},
 		},
+		"YAML": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						switch o := obj.(type) {
+						case *v1beta1.ProviderConfig:
+							pc.DeepCopyInto(o)
+						case *corev1.Secret:
+							o.Data = map[string][]byte{"credentials": []byte("accessKeyId: ak\naccessKeySecret: sk\n")}
+						}
+						return nil
+					},
+				},
+				name: "default",
+			},
+			want: want{
+				cred: &AlibabaCredentials{AccessKeyID: "ak", AccessKeySecret: "sk"},
+			},
+		},
+		"SecretKeys": {
+			args: args{
+				client: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						switch o := obj.(type) {
+						case *v1beta1.ProviderConfig:
+							pc.DeepCopyInto(o)
+						case *corev1.Secret:
+							o.Data = map[string][]byte{"accessKeyId": []byte("ak"), "accessKeySecret": []byte("sk"), "securityToken": []byte("token")}
+						}
+						return nil
+					},
+				},
+				name: "default",
+			},
+			want: want{
+				cred: &AlibabaCredentials{AccessKeyID: "ak", AccessKeySecret: "sk", SecurityToken: "token"},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -80,3 +126,278 @@ func TestGetCredentials(t *testing.T) {
 		})
 	}
 }
+
+type fakeSTSClient struct {
+	assumeRole func(req *sts.AssumeRoleRequest) (*sts.Credentials, error)
+}
+
+func (c *fakeSTSClient) AssumeRole(req *sts.AssumeRoleRequest) (*sts.Credentials, error) {
+	return c.assumeRole(req)
+}
+
+func (c *fakeSTSClient) GetCallerIdentity() (*sts.CallerIdentity, error) {
+	return nil, nil
+}
+
+func TestGetCredentialsWithAssumeRole(t *testing.T) {
+	ctx := context.TODO()
+	expiration := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var pc v1beta1.ProviderConfig
+	pc.Spec.Region = "cn-beijing"
+	pc.Spec.Credentials.Source = "Secret"
+	pc.Spec.Credentials.SecretRef = &xpv1.SecretKeySelector{
+		Key: "credentials",
+	}
+	pc.Spec.Credentials.SecretRef.Name = "default"
+	pc.Spec.AssumeRole = &v1beta1.AssumeRoleOptions{
+		RoleARN: "acs:ram::1234:role/crossplane",
+	}
+
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1beta1.ProviderConfig:
+				pc.DeepCopyInto(o)
+			case *corev1.Secret:
+				o.Data = map[string][]byte{"credentials": []byte("accessKeyId: ak\naccessKeySecret: sk\n")}
+			}
+			return nil
+		},
+	}
+
+	type want struct {
+		cred *AlibabaCredentials
+		err  error
+	}
+	cases := map[string]struct {
+		assumeRole func(req *sts.AssumeRoleRequest) (*sts.Credentials, error)
+		want       want
+	}{
+		"Success": {
+			assumeRole: func(req *sts.AssumeRoleRequest) (*sts.Credentials, error) {
+				if req.RoleARN != "acs:ram::1234:role/crossplane" || req.RoleSessionName != DefaultRoleSessionName {
+					return nil, errors.New("unexpected request")
+				}
+				return &sts.Credentials{AccessKeyID: "STS.ak", AccessKeySecret: "sk", SecurityToken: "token", Expiration: expiration}, nil
+			},
+			want: want{
+				cred: &AlibabaCredentials{AccessKeyID: "STS.ak", AccessKeySecret: "sk", SecurityToken: "token", Expiration: expiration},
+			},
+		},
+		"FailedToAssumeRole": {
+			assumeRole: func(req *sts.AssumeRoleRequest) (*sts.Credentials, error) {
+				return nil, errors.New("E1")
+			},
+			want: want{
+				err: errors.Wrap(errors.New("E1"), ErrAssumeRole),
+			},
+		},
+	}
+
+	defer func(fn func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error)) {
+		NewSTSClient = fn
+	}(NewSTSClient)
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assumedRoleCredentials = newCredentialsCache()
+			NewSTSClient = func(ctx context.Context, accessKeyID, accessKeySecret, securityToken, region string) (sts.Client, error) {
+				return &fakeSTSClient{assumeRole: tc.assumeRole}, nil
+			}
+			cred, err := GetCredentials(ctx, kube, "default")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nGetCredentials(...) -want error, +got error:\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.want.cred, cred); diff != "" {
+				t.Errorf("\nGetCredentials(...) %s\n", diff)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsFromInstanceMetadata(t *testing.T) {
+	ctx := context.TODO()
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/latest/meta-data/ram/security-credentials/":
+			fmt.Fprint(w, "crossplane")
+		case "/latest/meta-data/ram/security-credentials/crossplane":
+			fmt.Fprintf(w, `{"AccessKeyId":"STS.ak","AccessKeySecret":"sk","SecurityToken":"token","Expiration":"%s","Code":"Success"}`,
+				expiration.Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var pc v1beta1.ProviderConfig
+	pc.Spec.Credentials.Source = v1beta1.CredentialsSourceInstanceMetadata
+	pc.Spec.Credentials.InstanceMetadata = &v1beta1.InstanceMetadataCredentials{Endpoint: srv.URL}
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			pc.DeepCopyInto(obj.(*v1beta1.ProviderConfig))
+			return nil
+		}),
+	}
+
+	want := &AlibabaCredentials{AccessKeyID: "STS.ak", AccessKeySecret: "sk", SecurityToken: "token", Expiration: expiration}
+	for i := 0; i < 2; i++ {
+		cred, err := GetCredentials(ctx, kube, "default")
+		if err != nil {
+			t.Fatalf("\nGetCredentials(...): unexpected error: %v", err)
+		}
+		if diff := cmp.Diff(want, cred); diff != "" {
+			t.Errorf("\nGetCredentials(...) %s\n", diff)
+		}
+	}
+	// The role is discovered and its credentials are got only once, the second
+	// call is served from the cache.
+	if calls != 2 {
+		t.Errorf("\nGetCredentials(...): want 2 calls to the instance metadata service, got %d", calls)
+	}
+}
+
+func TestGetCredentialsFromOIDC(t *testing.T) {
+	ctx := context.TODO()
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("oidc-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("Action") != "AssumeRoleWithOIDC" || r.FormValue("OIDCToken") != "oidc-token" ||
+			r.FormValue("OIDCProviderArn") != "acs:ram::1234:oidc-provider/ack-rrsa" || r.FormValue("RoleArn") != "acs:ram::1234:role/crossplane" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"RequestId":"1","Code":"InvalidParameter","Message":"unexpected request"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"RequestId":"1","Credentials":{"AccessKeyId":"STS.ak","AccessKeySecret":"sk","SecurityToken":"token","Expiration":"%s"}}`,
+			expiration.Format(time.RFC3339))
+	}))
+	defer srv.Close()
+
+	defer func(fn func(endpoint string) sts.OIDCClient) { NewSTSOIDCClient = fn }(NewSTSOIDCClient)
+	NewSTSOIDCClient = func(string) sts.OIDCClient { return sts.NewOIDCClient(srv.URL) }
+
+	var pc v1beta1.ProviderConfig
+	pc.Spec.Credentials.Source = v1beta1.CredentialsSourceOIDC
+	pc.Spec.Credentials.OIDC = &v1beta1.OIDCCredentials{
+		TokenFile:   tokenFile,
+		ProviderARN: "acs:ram::1234:oidc-provider/ack-rrsa",
+		RoleARN:     "acs:ram::1234:role/crossplane",
+	}
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			pc.DeepCopyInto(obj.(*v1beta1.ProviderConfig))
+			return nil
+		}),
+	}
+
+	cred, err := GetCredentials(ctx, kube, "default")
+	if err != nil {
+		t.Fatalf("\nGetCredentials(...): unexpected error: %v", err)
+	}
+	want := &AlibabaCredentials{AccessKeyID: "STS.ak", AccessKeySecret: "sk", SecurityToken: "token", Expiration: expiration}
+	if diff := cmp.Diff(want, cred); diff != "" {
+		t.Errorf("\nGetCredentials(...) %s\n", diff)
+	}
+}
+
+func TestCheckProviderConfigHealth(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		conditions []xpv1.Condition
+		want       error
+	}{
+		"NotValidated": {
+			want: nil,
+		},
+		"Healthy": {
+			conditions: []xpv1.Condition{xpv1.Available()},
+			want:       nil,
+		},
+		"Unhealthy": {
+			conditions: []xpv1.Condition{xpv1.Unavailable().WithMessage(errBoom.Error())},
+			want:       errors.Errorf(ErrFmtProviderConfigUnhealthy, "default", errBoom.Error()),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{}
+			pc.SetName("default")
+			pc.Status.SetConditions(tc.conditions...)
+			err := CheckProviderConfigHealth(pc)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\nCheckProviderConfigHealth(...) -want error, +got error:\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestGetCredentialsVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(file, []byte("accessKeyId: ak\naccessKeySecret: sk\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(file, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.SetResourceVersion("42")
+			return nil
+		}),
+	}
+
+	cases := map[string]struct {
+		reason      string
+		credentials v1beta1.ProviderCredentials
+		want        string
+	}{
+		"Secret": {
+			reason: "The version of the credentials in a Secret should be its resourceVersion",
+			credentials: v1beta1.ProviderCredentials{
+				Source:                    xpv1.CredentialsSourceSecret,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{SecretRef: &xpv1.SecretKeySelector{Key: "credentials"}},
+			},
+			want: "42",
+		},
+		"Filesystem": {
+			reason: "The version of the credentials in a file should be its modification time",
+			credentials: v1beta1.ProviderCredentials{
+				Source:                    xpv1.CredentialsSourceFilesystem,
+				CommonCredentialSelectors: xpv1.CommonCredentialSelectors{Fs: &xpv1.FsSelector{Path: file}},
+			},
+			want: modified.Format(time.RFC3339Nano),
+		},
+		"InstanceMetadata": {
+			reason:      "Temporary credentials have no version, they expire",
+			credentials: v1beta1.ProviderCredentials{Source: v1beta1.CredentialsSourceInstanceMetadata},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{}
+			pc.Spec.Credentials = tc.credentials
+			got, err := getCredentialsVersion(context.TODO(), kube, pc)
+			if err != nil {
+				t.Fatalf("\n%s\ngetCredentialsVersion(...): %v", tc.reason, err)
+			}
+			if got != tc.want {
+				t.Errorf("\n%s\ngetCredentialsVersion(...): want %q, got %q", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
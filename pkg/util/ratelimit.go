@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/ratelimit"
+)
+
+// GetRateLimiter returns the rate limiter of service shared by the account of
+// ProviderConfig.
+func GetRateLimiter(pc *aliv1beta1.ProviderConfig, service string) *ratelimit.Limiter {
+	return ratelimit.Get(rateLimitAccount(pc), service, GetRateLimitConfig(pc, service))
+}
+
+// rateLimitAccount identifies the account whose requests are limited together.
+// The account of ProviderConfig is unknown until its credentials are
+// validated, so the requests are limited per ProviderConfig till then.
+func rateLimitAccount(pc *aliv1beta1.ProviderConfig) string {
+	if pc.Status.AccountID != "" {
+		return pc.Status.AccountID
+	}
+	return "providerconfig:" + pc.Name
+}
+
+// GetRateLimitConfig gets the rate limit of service configured in
+// ProviderConfig, defaulting to ratelimit.DefaultConfig.
+func GetRateLimitConfig(pc *aliv1beta1.ProviderConfig, service string) ratelimit.Config {
+	cfg := ratelimit.DefaultConfig
+	opts := pc.Spec.RateLimit
+	if opts == nil {
+		return cfg
+	}
+
+	applyRateLimit(&cfg, opts.RateLimit)
+	if limit, ok := opts.Services[service]; ok {
+		applyRateLimit(&cfg, limit)
+	}
+	if opts.MaxBackoff != nil {
+		cfg.MaxBackoff = opts.MaxBackoff.Duration
+	}
+	return cfg
+}
+
+func applyRateLimit(cfg *ratelimit.Config, limit aliv1beta1.RateLimit) {
+	if limit.RequestsPerSecond != nil {
+		cfg.RequestsPerSecond = float64(*limit.RequestsPerSecond)
+	}
+	if limit.Burst != nil {
+		cfg.Burst = *limit.Burst
+	}
+}
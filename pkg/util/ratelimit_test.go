@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/ratelimit"
+)
+
+func TestGetRateLimitConfig(t *testing.T) {
+	opts := &v1beta1.RateLimitOptions{
+		RateLimit: v1beta1.RateLimit{RequestsPerSecond: pointer.IntPtr(5)},
+		Services: map[string]v1beta1.RateLimit{
+			"rds": {RequestsPerSecond: pointer.IntPtr(2), Burst: pointer.IntPtr(4)},
+		},
+		MaxBackoff: &metav1.Duration{Duration: time.Minute},
+	}
+	cases := map[string]struct {
+		opts    *v1beta1.RateLimitOptions
+		service string
+		want    ratelimit.Config
+	}{
+		"Default": {
+			service: "rds",
+			want:    ratelimit.DefaultConfig,
+		},
+		"Account": {
+			opts:    opts,
+			service: "oss",
+			want:    ratelimit.Config{RequestsPerSecond: 5, Burst: ratelimit.DefaultConfig.Burst, MaxBackoff: time.Minute},
+		},
+		"Service": {
+			opts:    opts,
+			service: "rds",
+			want:    ratelimit.Config{RequestsPerSecond: 2, Burst: 4, MaxBackoff: time.Minute},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &v1beta1.ProviderConfig{Spec: v1beta1.ProviderConfigSpec{RateLimit: tc.opts}}
+			if diff := cmp.Diff(tc.want, GetRateLimitConfig(pc, tc.service)); diff != "" {
+				t.Errorf("\nGetRateLimitConfig(...): -want, +got:\n%s\n", diff)
+			}
+		})
+	}
+}
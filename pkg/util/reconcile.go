@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/errorclass"
+)
+
+// requeueAfter is how long to wait before reconciling a managed resource
+// again after an error of the class. The errors of the other classes are
+// requeued with the rate limited backoff of the controller.
+var requeueAfter = map[errorclass.Class]time.Duration{
+	// The rate limiter of the account already backs off, reconciling the
+	// throttled resources sooner only queues more requests.
+	errorclass.Throttled: 30 * time.Second,
+	// These can't succeed until the managed resource, the account or its
+	// permissions are changed.
+	errorclass.QuotaExceeded:    5 * time.Minute,
+	errorclass.InvalidParameter: 5 * time.Minute,
+	errorclass.Forbidden:        5 * time.Minute,
+}
+
+// ErrorClassifier classifies the errors returned when connecting to and
+// operating on the external resources, so that all the managed resources
+// handle them consistently. The errors are prefixed with their class, e.g.
+// "Throttled: ...", which is reported in the Synced condition and the events
+// of the managed resources, which are then requeued according to the class.
+// The request ID, the error code and the HTTP status of the errors returned by
+// the APIs are reported along with the class, and logged as structured fields.
+// The controllers decide themselves which NotFound errors mean their external
+// resources are gone.
+type ErrorClassifier struct {
+	log     logging.Logger
+	mu      sync.Mutex
+	classes map[string]errorclass.Class
+}
+
+// NewErrorClassifier returns an ErrorClassifier logging the errors returned by
+// the APIs to l.
+func NewErrorClassifier(l logging.Logger) *ErrorClassifier {
+	return &ErrorClassifier{log: l, classes: map[string]errorclass.Class{}}
+}
+
+// Reconciler wraps the managed resource reconciler r to requeue the managed
+// resources according to the class of their errors.
+func (c *ErrorClassifier) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		class := c.pop(req.Name)
+		if after, ok := requeueAfter[class]; ok && err == nil && result.Requeue {
+			result = reconcile.Result{RequeueAfter: after}
+		}
+		return result, err
+	})
+}
+
+// Connecter wraps ec to classify the errors of the ExternalClients it
+// connects.
+func (c *ErrorClassifier) Connecter(ec managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ext, err := ec.Connect(ctx, mg)
+		if err != nil {
+			return nil, c.classify(mg, err)
+		}
+		return &classifyingExternal{client: ext, classifier: c}, nil
+	})
+}
+
+func (c *ErrorClassifier) classify(mg resource.Managed, err error) error {
+	class := errorclass.Classify(err)
+	if class != errorclass.Unknown {
+		c.mu.Lock()
+		c.classes[mg.GetName()] = class
+		c.mu.Unlock()
+	}
+
+	prefix := string(class)
+	if d, ok := errorclass.GetDetails(err); ok {
+		c.log.Debug("Alibaba Cloud API request failed", append([]interface{}{"name", mg.GetName(), "class", class, "error", err}, d.KeysAndValues()...)...)
+		if details := d.String(); details != "" {
+			prefix = strings.TrimSpace(prefix + " [" + details + "]")
+		}
+	}
+	if prefix == "" {
+		return err
+	}
+	return errors.Wrap(err, prefix)
+}
+
+func (c *ErrorClassifier) pop(name string) errorclass.Class {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	class := c.classes[name]
+	delete(c.classes, name)
+	return class
+}
+
+type classifyingExternal struct {
+	client     managed.ExternalClient
+	classifier *ErrorClassifier
+}
+
+func (e *classifyingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := e.client.Observe(ctx, mg)
+	if err != nil {
+		return o, e.classifier.classify(mg, err)
+	}
+	return o, nil
+}
+
+func (e *classifyingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cre, err := e.client.Create(ctx, mg)
+	if err != nil {
+		return cre, e.classifier.classify(mg, err)
+	}
+	return cre, nil
+}
+
+func (e *classifyingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	upd, err := e.client.Update(ctx, mg)
+	if err != nil {
+		return upd, e.classifier.classify(mg, err)
+	}
+	return upd, nil
+}
+
+func (e *classifyingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if err := e.client.Delete(ctx, mg); err != nil {
+		return e.classifier.classify(mg, err)
+	}
+	return nil
+}
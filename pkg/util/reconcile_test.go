@@ -0,0 +1,127 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestErrorClassifier(t *testing.T) {
+	throttled := &sls.Error{Code: "ServerBusy"}
+	notFound := &sls.Error{Code: "ProjectNotExist"}
+	invalid := &sls.Error{Code: "ParameterInvalid", HTTPCode: 400, RequestID: "5F0C3A2B"}
+	unknown := &sls.Error{Code: "Boom", HTTPCode: 400, RequestID: "5F0C3A2C"}
+	boom := errors.New("boom")
+
+	type want struct {
+		err    error
+		result reconcile.Result
+	}
+	cases := map[string]struct {
+		reason  string
+		observe error
+		delete  error
+		want    want
+	}{
+		"Throttled": {
+			reason:  "Throttled errors should be prefixed with their class and requeued after a delay",
+			observe: throttled,
+			want: want{
+				err:    errors.Wrap(throttled, "Throttled [Code: ServerBusy]"),
+				result: reconcile.Result{RequeueAfter: 30 * time.Second},
+			},
+		},
+		"Details": {
+			reason:  "The request ID, the error code and the HTTP status of the errors should be reported with their class",
+			observe: invalid,
+			want: want{
+				err:    errors.Wrap(invalid, "InvalidParameter [RequestId: 5F0C3A2B, Code: ParameterInvalid, HTTPStatus: 400]"),
+				result: reconcile.Result{RequeueAfter: 5 * time.Minute},
+			},
+		},
+		"UnknownDetails": {
+			reason:  "The details of the errors returned by the APIs should be reported even if they aren't classified",
+			observe: unknown,
+			want: want{
+				err:    errors.Wrap(unknown, "[RequestId: 5F0C3A2C, Code: Boom, HTTPStatus: 400]"),
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"Unknown": {
+			reason:  "Unknown errors should be returned as is and requeued with the default backoff",
+			observe: boom,
+			want: want{
+				err:    boom,
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+		"DeleteNotFound": {
+			reason: "NotFound errors of deleting external resources should be returned, since they might be about other resources",
+			delete: notFound,
+			want: want{
+				err:    errors.Wrap(notFound, "NotFound [Code: ProjectNotExist]"),
+				result: reconcile.Result{Requeue: true},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{ObjectMeta: metav1.ObjectMeta{Name: "cool"}}
+			c := NewErrorClassifier(logging.NewNopLogger())
+			ec := c.Connecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+				return &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return managed.ExternalObservation{}, tc.observe
+					},
+					DeleteFn: func(_ context.Context, _ resource.Managed) error {
+						return tc.delete
+					},
+				}, nil
+			}))
+
+			var err error
+			r := c.Reconciler(reconcile.Func(func(ctx context.Context, _ reconcile.Request) (reconcile.Result, error) {
+				ext, _ := ec.Connect(ctx, mg)
+				if _, err = ext.Observe(ctx, mg); err == nil {
+					err = ext.Delete(ctx, mg)
+				}
+				return reconcile.Result{Requeue: true}, nil
+			}))
+			result, _ := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: mg.GetName()}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExternalClient: -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, result); diff != "" {
+				t.Errorf("\n%s\nReconcile(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	database "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	nas "github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
+	oss "github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	redis "github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
+	slb "github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
+	sls "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
+)
+
+const (
+	// TagKind is the tag of the kind of the managed resource of an external
+	// resource, e.g. rdsinstance.database.alibaba.crossplane.io.
+	TagKind = "crossplane-kind"
+	// TagName is the tag of the name of the managed resource of an external
+	// resource.
+	TagName = "crossplane-name"
+	// TagProviderConfig is the tag of the name of the ProviderConfig of the
+	// managed resource of an external resource.
+	TagProviderConfig = "crossplane-providerconfig"
+
+	errUpdateTags = "cannot update the tags of the managed resource"
+)
+
+// GetResourceTags gets the tags specified in the parameters of a cloud
+// resource. It returns false if the cloud resource doesn't support tags.
+func GetResourceTags(res runtime.Object) (map[string]string, bool) {
+	switch cr := res.(type) {
+	case *database.RDSInstance:
+		return cr.Spec.ForProvider.Tags, true
+	case *redis.RedisInstance:
+		return cr.Spec.ForProvider.Tags, true
+	case *oss.Bucket:
+		return cr.Spec.Tags, true
+	case *nas.NASFileSystem:
+		return cr.Spec.Tags, true
+	case *slb.CLB:
+		return cr.Spec.ForProvider.Tags, true
+	case *sls.Project:
+		return cr.Spec.ForProvider.Tags, true
+	}
+	return nil, false
+}
+
+// SetResourceTags sets the tags in the parameters of a cloud resource. It
+// does nothing if the cloud resource doesn't support tags.
+func SetResourceTags(res runtime.Object, tags map[string]string) {
+	switch cr := res.(type) {
+	case *database.RDSInstance:
+		cr.Spec.ForProvider.Tags = tags
+	case *redis.RedisInstance:
+		cr.Spec.ForProvider.Tags = tags
+	case *oss.Bucket:
+		cr.Spec.Tags = tags
+	case *nas.NASFileSystem:
+		cr.Spec.Tags = tags
+	case *slb.CLB:
+		cr.Spec.ForProvider.Tags = tags
+	case *sls.Project:
+		cr.Spec.ForProvider.Tags = tags
+	}
+}
+
+// Tagger adds the default tags configured in the ProviderConfig of a managed
+// resource to its tags. The tags already set on the managed resource are
+// never overridden.
+type Tagger struct {
+	client client.Client
+	kind   string
+}
+
+// NewTagger returns a Tagger of the managed resources of kind, e.g.
+// RDSInstance.database.alibaba.crossplane.io.
+func NewTagger(c client.Client, kind string) *Tagger {
+	return &Tagger{client: c, kind: strings.ToLower(kind)}
+}
+
+// Initialize adds the default tags to the tags of mg.
+func (t *Tagger) Initialize(ctx context.Context, mg resource.Managed) error {
+	tags, ok := GetResourceTags(mg)
+	// The tags of the external resources which are only observed are never
+	// updated, the default tags would be reported as a drift.
+	if !ok || IsObserveOnly(mg) {
+		return nil
+	}
+	ref := mg.GetProviderConfigReference()
+	if ref == nil {
+		return nil
+	}
+	pc, err := GetProviderConfig(ctx, t.client, ref.Name)
+	if err != nil {
+		return err
+	}
+	defaults := map[string]string{}
+	if dt := pc.Spec.DefaultTags; dt != nil {
+		for k, v := range dt.Tags {
+			defaults[k] = v
+		}
+	}
+	if dt := pc.Spec.DefaultTags; dt == nil || !dt.DisableCrossplaneTags {
+		defaults[TagKind] = t.kind
+		defaults[TagName] = mg.GetName()
+		defaults[TagProviderConfig] = ref.Name
+	}
+
+	updated := false
+	for k, v := range defaults {
+		if _, ok := tags[k]; ok {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]string{}
+		}
+		tags[k] = v
+		updated = true
+	}
+	if !updated {
+		return nil
+	}
+	SetResourceTags(mg, tags)
+	return errors.Wrap(t.client.Update(ctx, mg), errUpdateTags)
+}
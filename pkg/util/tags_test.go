@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	database "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+)
+
+func TestTagger(t *testing.T) {
+	type want struct {
+		tags    map[string]string
+		updated bool
+	}
+	cases := map[string]struct {
+		defaults    *v1beta1.DefaultTagsOptions
+		annotations map[string]string
+		tags        map[string]string
+		want        want
+	}{
+		"CrossplaneTags": {
+			want: want{
+				tags: map[string]string{
+					TagKind:           "rdsinstance.database.alibaba.crossplane.io",
+					TagName:           "cool",
+					TagProviderConfig: "default",
+				},
+				updated: true,
+			},
+		},
+		"ConfiguredTags": {
+			defaults: &v1beta1.DefaultTagsOptions{
+				Tags:                  map[string]string{"team": "platform", "env": "prod"},
+				DisableCrossplaneTags: true,
+			},
+			tags: map[string]string{"env": "dev"},
+			want: want{
+				tags:    map[string]string{"team": "platform", "env": "dev"},
+				updated: true,
+			},
+		},
+		"UpToDate": {
+			defaults: &v1beta1.DefaultTagsOptions{
+				Tags:                  map[string]string{"team": "platform"},
+				DisableCrossplaneTags: true,
+			},
+			tags: map[string]string{"team": "db"},
+			want: want{
+				tags: map[string]string{"team": "db"},
+			},
+		},
+		"ObserveOnly": {
+			annotations: map[string]string{AnnotationManagementPolicy: ManagementPolicyObserveOnly},
+			want:        want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			c := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*v1beta1.ProviderConfig).Spec.DefaultTags = tc.defaults
+					return nil
+				}),
+				MockUpdate: func(_ context.Context, _ client.Object, _ ...client.UpdateOption) error {
+					updated = true
+					return nil
+				},
+			}
+			cr := &database.RDSInstance{ObjectMeta: metav1.ObjectMeta{Name: "cool", Annotations: tc.annotations}}
+			cr.Spec.ProviderConfigReference = &xpv1.Reference{Name: "default"}
+			cr.Spec.ForProvider.Tags = tc.tags
+
+			if err := NewTagger(c, database.RDSInstanceGroupKind).Initialize(context.Background(), cr); err != nil {
+				t.Fatalf("Initialize(...): %v", err)
+			}
+			if diff := cmp.Diff(tc.want.tags, cr.Spec.ForProvider.Tags); diff != "" {
+				t.Errorf("\nInitialize(...): -want tags, +got tags:\n%s\n", diff)
+			}
+			if updated != tc.want.updated {
+				t.Errorf("\nInitialize(...): want updated %t, got %t\n", tc.want.updated, updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"sync"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.7.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// tracerName is the name of the tracer of the reconciles.
+const tracerName = "github.com/crossplane-contrib/provider-alibaba/pkg/util"
+
+// serviceName is the name of the provider in the exported traces.
+const serviceName = "provider-alibaba"
+
+const errCreateExporter = "cannot create OTLP trace exporter"
+
+// Attributes of the spans of the reconciles.
+const (
+	attrController   = attribute.Key("crossplane.controller")
+	attrName         = attribute.Key("crossplane.name")
+	attrExternalName = attribute.Key("crossplane.external_name")
+)
+
+// StartTracing exports the traces of the reconciles and the API requests to
+// the OTLP collector listening on endpoint, e.g. otel-collector:4317, over
+// gRPC. The traces aren't recorded until it's called. It returns a function
+// flushing the pending spans and stopping the export.
+func StartTracing(ctx context.Context, endpoint string, insecure bool) (func(context.Context) error, error) {
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+	if insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exp, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateExporter)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exp),
+		sdktrace.WithResource(sdkresource.NewSchemaless(semconv.ServiceNameKey.String(serviceName))))
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// Tracer traces the reconciles of the managed resources of a controller, and
+// connecting to and operating on their external resources. The API requests
+// sent meanwhile are traced as the children of their spans.
+type Tracer struct {
+	controller string
+
+	// reconciles are the spans of the reconciles in progress, by the
+	// name of their managed resources.
+	reconciles sync.Map
+}
+
+// NewTracer returns a Tracer of the reconciles of controller.
+func NewTracer(controller string) *Tracer {
+	return &Tracer{controller: controller}
+}
+
+// Reconciler wraps the managed resource reconciler r to end the span of each
+// reconcile started by Connecter, recording the error of the reconcile.
+func (t *Tracer) Reconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := r.Reconcile(ctx, req)
+		if s, ok := t.reconciles.LoadAndDelete(req.NamespacedName); ok {
+			span := s.(trace.Span)
+			recordError(span, err)
+			span.End()
+		}
+		return result, err
+	})
+}
+
+// Connecter wraps ec to trace connecting to the external resources and the
+// operations of the ExternalClients it connects. The managed resource
+// reconciler doesn't pass the context of the reconcile on to ec, the span of
+// the reconcile is thus started on connecting, and is the parent of the spans
+// of the operations. It's ended by Reconciler when the reconcile returns.
+func (t *Tracer) Connecter(ec managed.ExternalConnecter) managed.ExternalConnecter {
+	return managed.ExternalConnectorFn(func(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+		ctx, reconcileSpan := t.startManaged(ctx, "Reconcile", mg)
+		t.reconciles.Store(types.NamespacedName{Namespace: mg.GetNamespace(), Name: mg.GetName()}, reconcileSpan)
+
+		ctx, span := t.startManaged(ctx, "Connect", mg)
+		defer span.End()
+		ext, err := ec.Connect(ctx, mg)
+		if err != nil {
+			recordError(span, err)
+			recordError(reconcileSpan, err)
+			return nil, err
+		}
+		return &tracingExternal{client: ext, tracer: t, reconcile: reconcileSpan}, nil
+	})
+}
+
+func (t *Tracer) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(append([]attribute.KeyValue{attrController.String(t.controller)}, attrs...)...))
+}
+
+func (t *Tracer) startManaged(ctx context.Context, name string, mg resource.Managed) (context.Context, trace.Span) {
+	return t.start(ctx, name, attrName.String(mg.GetName()), attrExternalName.String(meta.GetExternalName(mg)))
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+type tracingExternal struct {
+	client    managed.ExternalClient
+	tracer    *Tracer
+	reconcile trace.Span
+}
+
+// start starts the span of an operation as a child of the span of the
+// reconcile.
+func (e *tracingExternal) start(ctx context.Context, name string, mg resource.Managed) (context.Context, trace.Span) {
+	return e.tracer.startManaged(trace.ContextWithSpan(ctx, e.reconcile), name, mg)
+}
+
+// end records the error of an operation, which fails the reconcile too, and
+// ends its span.
+func (e *tracingExternal) end(span trace.Span, err error) {
+	recordError(span, err)
+	recordError(e.reconcile, err)
+	span.End()
+}
+
+func (e *tracingExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx, span := e.start(ctx, "Observe", mg)
+	o, err := e.client.Observe(ctx, mg)
+	e.end(span, err)
+	return o, err
+}
+
+func (e *tracingExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx, span := e.start(ctx, "Create", mg)
+	cre, err := e.client.Create(ctx, mg)
+	e.end(span, err)
+	return cre, err
+}
+
+func (e *tracingExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx, span := e.start(ctx, "Update", mg)
+	upd, err := e.client.Update(ctx, mg)
+	e.end(span, err)
+	return upd, err
+}
+
+func (e *tracingExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx, span := e.start(ctx, "Delete", mg)
+	err := e.client.Delete(ctx, mg)
+	e.end(span, err)
+	return err
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/apicall"
+)
+
+func TestTracer(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(prev)
+
+	boom := errors.New("boom")
+	caller := apicall.NewCaller("oss", "cn-hangzhou", nil)
+
+	tracer := NewTracer("managed/bucket.oss.alibaba.crossplane.io")
+	r := tracer.Reconciler(managed.NewReconciler(&fake.Manager{
+		Client: &test.MockClient{
+			MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.SetName("cool")
+				meta.SetExternalName(obj.(resource.Managed), "cool-bucket")
+				return nil
+			}),
+			MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+		},
+		Scheme: fake.SchemeWith(&fake.Managed{}),
+	}, resource.ManagedKind(fake.GVK(&fake.Managed{})),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+		managed.WithConnectionPublishers(),
+		managed.WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+		managed.WithExternalConnecter(tracer.Connecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return &managed.ExternalClientFns{
+				ObserveFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+					return managed.ExternalObservation{}, caller.Do(ctx, "GetBucketInfo", func() error { return nil })
+				},
+				CreateFn: func(ctx context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+					return managed.ExternalCreation{}, caller.Do(ctx, "PutBucket", func() error { return boom })
+				},
+			}, nil
+		})))))
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "cool"}}); err != nil {
+		t.Fatalf("Reconcile(...): %v", err)
+	}
+
+	spans := map[string]tracetest.SpanStub{}
+	for _, s := range exporter.GetSpans() {
+		spans[s.Name] = s
+	}
+	// The parent of each span, and whether it records an error.
+	want := map[string]struct {
+		parent string
+		failed bool
+	}{
+		"Reconcile":         {failed: true},
+		"Connect":           {parent: "Reconcile"},
+		"Observe":           {parent: "Reconcile"},
+		"oss.GetBucketInfo": {parent: "Observe"},
+		"Create":            {parent: "Reconcile", failed: true},
+		"oss.PutBucket":     {parent: "Create", failed: true},
+	}
+	if len(spans) != len(want) {
+		t.Errorf("Reconcile(...): want %d spans, got %d", len(want), len(spans))
+	}
+	for name, w := range want {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("Reconcile(...): want span %s", name)
+			continue
+		}
+		parent := spans[w.parent]
+		if w.parent == "" && s.Parent.IsValid() || w.parent != "" && s.Parent.SpanID() != parent.SpanContext.SpanID() {
+			t.Errorf("Reconcile(...): want span %s to be a child of %q", name, w.parent)
+		}
+		if failed := s.Status.Code == codes.Error; failed != w.failed {
+			t.Errorf("Reconcile(...): want span %s to record an error %t, got %t", name, w.failed, failed)
+		}
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range spans["Observe"].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	wantAttrs := map[string]string{
+		"crossplane.controller":    "managed/bucket.oss.alibaba.crossplane.io",
+		"crossplane.name":          "cool",
+		"crossplane.external_name": "cool-bucket",
+	}
+	if diff := cmp.Diff(wantAttrs, attrs); diff != "" {
+		t.Errorf("Observe(...): -want attributes, +got attributes:\n%s", diff)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	aliv1beta1 "github.com/crossplane-contrib/provider-alibaba/apis/v1beta1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients"
+)
+
+const (
+	// ErrGetTransportConfig is the error of failing to resolve the transport configured in ProviderConfig
+	ErrGetTransportConfig = "cannot get the transport configuration"
+
+	errGetProxyURLSecret        = "cannot get the Secret of the proxy URL"
+	errGetCABundleSecret        = "cannot get the Secret of the CA bundle"
+	errGetCABundleConfigMap     = "cannot get the ConfigMap of the CA bundle"
+	errNoCABundleSource         = "neither a Secret nor a ConfigMap is specified for the CA bundle"
+	errFmtInvalidProxyURL       = "invalid proxy URL %q"
+	errFmtKeyNotFound           = "key %q not found in %s %s/%s"
+	errFmtUnsupportedTLSVersion = "unsupported TLS version %q"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// GetTransportConfig resolves the transport configured in ProviderConfig,
+// reading the proxy URL and the CA bundle from the referenced Secrets and
+// ConfigMaps. It returns nil if ProviderConfig doesn't configure the
+// transport.
+func GetTransportConfig(ctx context.Context, c client.Client, pc *aliv1beta1.ProviderConfig) (*clients.TransportConfig, error) {
+	opts := pc.Spec.Transport
+	if opts == nil {
+		return nil, nil
+	}
+
+	cfg := &clients.TransportConfig{
+		ProxyURL: opts.ProxyURL,
+		NoProxy:  opts.NoProxy,
+	}
+	if opts.ProxyURLSecretRef != nil {
+		data, err := getSecretKey(ctx, c, opts.ProxyURLSecretRef)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetProxyURLSecret)
+		}
+		cfg.ProxyURL = string(data)
+	}
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, errors.Errorf(errFmtInvalidProxyURL, redactURL(u))
+		}
+	}
+
+	if opts.CABundle != nil {
+		data, err := getCABundle(ctx, c, opts.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(data) {
+			return nil, errors.New(clients.ErrInvalidCABundle)
+		}
+		cfg.CABundle = data
+	}
+
+	if opts.ConnectTimeout != nil {
+		cfg.ConnectTimeout = opts.ConnectTimeout.Duration
+	}
+	if opts.ReadTimeout != nil {
+		cfg.ReadTimeout = opts.ReadTimeout.Duration
+	}
+	if opts.TLSMinVersion != "" {
+		v, ok := tlsVersions[opts.TLSMinVersion]
+		if !ok {
+			return nil, errors.Errorf(errFmtUnsupportedTLSVersion, opts.TLSMinVersion)
+		}
+		cfg.TLSMinVersion = v
+	}
+	return cfg, nil
+}
+
+func getCABundle(ctx context.Context, c client.Client, src *aliv1beta1.CABundleSource) ([]byte, error) {
+	switch {
+	case src.SecretRef != nil:
+		data, err := getSecretKey(ctx, c, src.SecretRef)
+		return data, errors.Wrap(err, errGetCABundleSecret)
+	case src.ConfigMapRef != nil:
+		ref := src.ConfigMapRef
+		cm := &corev1.ConfigMap{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return nil, errors.Wrap(err, errGetCABundleConfigMap)
+		}
+		data, ok := cm.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf(errFmtKeyNotFound, ref.Key, "ConfigMap", ref.Namespace, ref.Name)
+		}
+		return []byte(data), nil
+	default:
+		return nil, errors.New(errNoCABundleSource)
+	}
+}
+
+func getSecretKey(ctx context.Context, c client.Client, ref *xpv1.SecretKeySelector) ([]byte, error) {
+	s := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, err
+	}
+	data, ok := s.Data[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errFmtKeyNotFound, ref.Key, "Secret", ref.Namespace, ref.Name)
+	}
+	return data, nil
+}
+
+// redactURL hides the password of the proxy, which must not show up in errors.
+func redactURL(u *url.URL) string {
+	if u == nil {
+		return "<unparsable>"
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
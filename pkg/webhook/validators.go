@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	databasev1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	nasv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/nas/v1alpha1"
+	ossv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	redisv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/redis/v1alpha1"
+	slbv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/slb/v1alpha1"
+	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/oss"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/rds"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/clients/sls"
+	"github.com/crossplane-contrib/provider-alibaba/pkg/util"
+)
+
+// validators are the validators of all the kinds of managed resources.
+var validators = []Validator{
+	{New: func() resource.Managed { return &databasev1alpha1.RDSInstance{} }, Validate: validateRDSInstance},
+	{New: func() resource.Managed { return &redisv1alpha1.RedisInstance{} }, Validate: validateRedisInstance},
+	{New: func() resource.Managed { return &ossv1alpha1.Bucket{} }, Validate: validateBucket},
+	{New: func() resource.Managed { return &slsv1alpha1.Logtail{} }, Validate: validateLogtail},
+	{New: func() resource.Managed { return &slsv1alpha1.Project{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &slsv1alpha1.LogStore{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &slsv1alpha1.LogstoreIndex{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &slsv1alpha1.MachineGroup{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &slsv1alpha1.MachineGroupBinding{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &nasv1alpha1.NASFileSystem{} }, Validate: validateRegion(spec)},
+	{New: func() resource.Managed { return &nasv1alpha1.NASMountTarget{} }, Validate: validateRegion(forProvider)},
+	{New: func() resource.Managed { return &slbv1alpha1.CLB{} }, Validate: validateRegion(forProvider)},
+}
+
+var (
+	spec        = field.NewPath("spec")
+	forProvider = spec.Child("forProvider")
+)
+
+// validateRegion returns a function validating that the region of a managed
+// resource, whose parameters are at path, is not changed.
+func validateRegion(path *field.Path) func(mg, old resource.Managed) field.ErrorList {
+	return func(mg, old resource.Managed) field.ErrorList {
+		if old == nil {
+			return nil
+		}
+		return validateImmutable(path.Child("region"), util.GetResourceRegion(mg), util.GetResourceRegion(old))
+	}
+}
+
+func validateRDSInstance(mg, old resource.Managed) field.ErrorList {
+	p := mg.(*databasev1alpha1.RDSInstance).Spec.ForProvider
+	errs := validateRegion(forProvider)(mg, old)
+	if err := rds.ValidateEngine(p.Engine); err != nil {
+		errs = append(errs, field.Invalid(forProvider.Child("engine"), p.Engine, err.Error()))
+	}
+	if old != nil {
+		o := old.(*databasev1alpha1.RDSInstance).Spec.ForProvider
+		// The engine is case insensitive.
+		if !strings.EqualFold(p.Engine, o.Engine) {
+			errs = append(errs, validateImmutable(forProvider.Child("engine"), p.Engine, o.Engine)...)
+		}
+		errs = append(errs, validateImmutable(forProvider.Child("masterUsername"), p.MasterUsername, o.MasterUsername)...)
+	}
+	return errs
+}
+
+func validateRedisInstance(mg, old resource.Managed) field.ErrorList {
+	p := mg.(*redisv1alpha1.RedisInstance).Spec.ForProvider
+	errs := validateRegion(forProvider)(mg, old)
+	if old != nil {
+		o := old.(*redisv1alpha1.RedisInstance).Spec.ForProvider
+		errs = append(errs, validateImmutable(forProvider.Child("instanceType"), p.InstanceType, o.InstanceType)...)
+		errs = append(errs, validateImmutable(forProvider.Child("masterUsername"), p.MasterUsername, o.MasterUsername)...)
+	}
+	return errs
+}
+
+func validateBucket(mg, old resource.Managed) field.ErrorList {
+	p := mg.(*ossv1alpha1.Bucket).Spec.BucketParameter
+	errs := validateRegion(spec)(mg, old)
+	if _, err := oss.ValidateOSSAcl(p.ACL); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("acl"), p.ACL, err.Error()))
+	}
+	if _, err := oss.ValidateOSSStorageClass(p.StorageClass); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("storageClass"), p.StorageClass, err.Error()))
+	}
+	if _, err := oss.ValidateOSSDataRedundancyType(p.DataRedundancyType); err != nil {
+		errs = append(errs, field.Invalid(spec.Child("dataRedundancyType"), p.DataRedundancyType, err.Error()))
+	}
+	if old != nil {
+		o := old.(*ossv1alpha1.Bucket).Spec.BucketParameter
+		errs = append(errs, validateImmutable(spec.Child("storageClass"), p.StorageClass, o.StorageClass)...)
+		errs = append(errs, validateImmutable(spec.Child("dataRedundancyType"), p.DataRedundancyType, o.DataRedundancyType)...)
+	}
+	return errs
+}
+
+func validateLogtail(mg, old resource.Managed) field.ErrorList {
+	in := mg.(*slsv1alpha1.Logtail).Spec.ForProvider.InputDetail
+	errs := validateRegion(forProvider)(mg, old)
+	for _, f := range []struct {
+		name  string
+		regex *string
+	}{{"logBeginRegex", in.LogBeginRegex}, {"regex", in.Regex}} {
+		if f.regex == nil {
+			continue
+		}
+		if err := sls.ValidateLogtailRegex(*f.regex); err != nil {
+			errs = append(errs, field.Invalid(forProvider.Child("inputDetail", f.name), *f.regex, err.Error()))
+		}
+	}
+	return errs
+}
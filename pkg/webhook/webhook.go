@@ -0,0 +1,117 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhook serves the admission webhooks validating the managed
+// resources. They're registered with the API server by the manifests in
+// examples/webhook, since Crossplane packages can't ship webhooks.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const errFmtGetKind = "cannot get the kind of %T"
+
+// A Validator validates the managed resources of a kind.
+type Validator struct {
+	// New returns an empty managed resource of the kind.
+	New func() resource.Managed
+
+	// Validate validates a managed resource when it's created, and when it's
+	// updated, in which case old is the managed resource before the update.
+	Validate func(mg, old resource.Managed) field.ErrorList
+}
+
+// Setup registers the validating admission webhooks of the kinds of the API
+// groups which are enabled with the webhook server of the manager.
+func Setup(mgr ctrl.Manager, enabled func(group string) bool) error {
+	for _, v := range validators {
+		gvk, err := apiutil.GVKForObject(v.New(), mgr.GetScheme())
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetKind, v.New())
+		}
+		if !enabled(gvk.Group) {
+			continue
+		}
+		path := fmt.Sprintf("/validate-%s-%s-%s", strings.ReplaceAll(gvk.Group, ".", "-"), gvk.Version, strings.ToLower(gvk.Kind))
+		mgr.GetWebhookServer().Register(path, &webhook.Admission{Handler: &handler{validator: v}})
+	}
+	return nil
+}
+
+// handler validates the managed resources of the admission requests.
+type handler struct {
+	validator Validator
+	decoder   *admission.Decoder
+}
+
+// InjectDecoder injects the decoder of the admission requests.
+func (h *handler) InjectDecoder(d *admission.Decoder) error {
+	h.decoder = d
+	return nil
+}
+
+// Handle validates the managed resource of an admission request. The managed
+// resources being deleted are not validated, so that their finalizers can be
+// removed even if they're invalid.
+func (h *handler) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	mg := h.validator.New()
+	if err := h.decoder.Decode(req, mg); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if meta.WasDeleted(mg) {
+		return admission.Allowed("")
+	}
+	var old resource.Managed
+	if req.Operation == admissionv1.Update {
+		old = h.validator.New()
+		if err := h.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+
+	if errs := h.validator.Validate(mg, old); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// validateImmutable returns an error if the value of an immutable field of a
+// managed resource is changed by an update. Setting a field that was empty,
+// e.g. when it's late initialized, is not a change.
+func validateImmutable(path *field.Path, value, old string) field.ErrorList {
+	if old == "" || value == old {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(path, value, fmt.Sprintf("field is immutable, it was %q", old))}
+}
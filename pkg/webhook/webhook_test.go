@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane-contrib/provider-alibaba/apis"
+	databasev1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/database/v1alpha1"
+	ossv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/oss/v1alpha1"
+	slsv1alpha1 "github.com/crossplane-contrib/provider-alibaba/apis/sls/v1alpha1"
+)
+
+func rdsInstance(engine, region string) *databasev1alpha1.RDSInstance {
+	cr := &databasev1alpha1.RDSInstance{}
+	cr.SetGroupVersionKind(databasev1alpha1.RDSInstanceGroupVersionKind)
+	cr.Spec.ForProvider.Engine = engine
+	if region != "" {
+		cr.Spec.ForProvider.Region = &region
+	}
+	return cr
+}
+
+func deleted(mg resource.Managed) resource.Managed {
+	now := metav1.Now()
+	mg.SetDeletionTimestamp(&now)
+	return mg
+}
+
+func bucket(acl, dataRedundancyType string) *ossv1alpha1.Bucket {
+	cr := &ossv1alpha1.Bucket{}
+	cr.Spec.ACL = acl
+	cr.Spec.DataRedundancyType = dataRedundancyType
+	return cr
+}
+
+func bucketStorageClass(storageClass string) *ossv1alpha1.Bucket {
+	cr := bucket("private", "LRS")
+	cr.Spec.StorageClass = storageClass
+	return cr
+}
+
+func logtail(regex string) *slsv1alpha1.Logtail {
+	cr := &slsv1alpha1.Logtail{}
+	cr.Spec.ForProvider.InputDetail.Regex = &regex
+	return cr
+}
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		validate func(mg, old resource.Managed) field.ErrorList
+		mg       resource.Managed
+		old      resource.Managed
+		want     []string
+	}{
+		"RDSInstanceValid": {
+			reason:   "A valid RDSInstance should be allowed",
+			validate: validateRDSInstance,
+			mg:       rdsInstance(databasev1alpha1.MysqlEngine, ""),
+		},
+		"RDSInstanceUnsupportedEngine": {
+			reason:   "An RDSInstance with an unsupported engine should be rejected",
+			validate: validateRDSInstance,
+			mg:       rdsInstance("Oracle", ""),
+			want:     []string{"spec.forProvider.engine"},
+		},
+		"RDSInstanceEngineChanged": {
+			reason:   "Changing the engine and the region of an RDSInstance should be rejected",
+			validate: validateRDSInstance,
+			mg:       rdsInstance(databasev1alpha1.PostgresqlEngine, "cn-beijing"),
+			old:      rdsInstance(databasev1alpha1.MysqlEngine, "cn-hangzhou"),
+			want:     []string{"spec.forProvider.region", "spec.forProvider.engine"},
+		},
+		"RDSInstanceRegionSet": {
+			reason:   "Setting the region of an RDSInstance which was empty should be allowed",
+			validate: validateRDSInstance,
+			mg:       rdsInstance(databasev1alpha1.MysqlEngine, "cn-hangzhou"),
+			old:      rdsInstance(databasev1alpha1.MysqlEngine, ""),
+		},
+		"RDSInstanceEngineCase": {
+			reason:   "The engine of an RDSInstance should be case insensitive",
+			validate: validateRDSInstance,
+			mg:       rdsInstance(databasev1alpha1.MysqlEngine, ""),
+			old:      rdsInstance("mysql", ""),
+		},
+		"BucketInvalid": {
+			reason:   "A Bucket with an invalid ACL and data redundancy type should be rejected",
+			validate: validateBucket,
+			mg:       bucket("everyone", "RAID"),
+			want:     []string{"spec.acl", "spec.dataRedundancyType"},
+		},
+		"BucketDataRedundancyTypeChanged": {
+			reason:   "Changing the data redundancy type of a Bucket should be rejected",
+			validate: validateBucket,
+			mg:       bucket("private", "ZRS"),
+			old:      bucket("public-read", "LRS"),
+			want:     []string{"spec.dataRedundancyType"},
+		},
+		"BucketStorageClassChanged": {
+			reason:   "Changing the storage class of a Bucket should be rejected",
+			validate: validateBucket,
+			mg:       bucketStorageClass("IA"),
+			old:      bucketStorageClass("Standard"),
+			want:     []string{"spec.storageClass"},
+		},
+		"BucketStorageClassSet": {
+			reason:   "Setting the storage class of a Bucket which was empty should be allowed",
+			validate: validateBucket,
+			mg:       bucketStorageClass("Standard"),
+			old:      bucketStorageClass(""),
+		},
+		"LogtailMalformedRegex": {
+			reason:   "A Logtail with a malformed regex should be rejected",
+			validate: validateLogtail,
+			mg:       logtail("(\\d+"),
+			want:     []string{"spec.forProvider.inputDetail.regex"},
+		},
+		"LogtailPerlRegex": {
+			reason:   "A Logtail with a regex using lookarounds should be allowed",
+			validate: validateLogtail,
+			mg:       logtail("(?<=\\[)\\d+"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, err := range tc.validate(tc.mg, tc.old) {
+				got = append(got, err.Field)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want invalid fields, +got invalid fields:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	d, _ := admission.NewDecoder(s)
+	h := &handler{validator: Validator{New: func() resource.Managed { return &databasev1alpha1.RDSInstance{} }, Validate: validateRDSInstance}}
+	if err := h.InjectDecoder(d); err != nil {
+		t.Fatal(err)
+	}
+
+	raw := func(mg resource.Managed) runtime.RawExtension {
+		b, _ := json.Marshal(mg)
+		return runtime.RawExtension{Raw: b}
+	}
+	cases := map[string]struct {
+		req  admissionv1.AdmissionRequest
+		want bool
+	}{
+		"Create": {
+			req:  admissionv1.AdmissionRequest{Operation: admissionv1.Create, Object: raw(rdsInstance(databasev1alpha1.MysqlEngine, ""))},
+			want: true,
+		},
+		"UpdateImmutable": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(rdsInstance(databasev1alpha1.PostgresqlEngine, "")),
+				OldObject: raw(rdsInstance(databasev1alpha1.MysqlEngine, "")),
+			},
+			want: false,
+		},
+		"UpdateDeleted": {
+			req: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    raw(deleted(rdsInstance(databasev1alpha1.PostgresqlEngine, ""))),
+				OldObject: raw(deleted(rdsInstance(databasev1alpha1.MysqlEngine, ""))),
+			},
+			want: true,
+		},
+		"Delete": {
+			req:  admissionv1.AdmissionRequest{Operation: admissionv1.Delete},
+			want: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := h.Handle(context.Background(), admission.Request{AdmissionRequest: tc.req})
+			if got.Allowed != tc.want {
+				t.Errorf("Handle(...): want allowed %t, got %t: %v", tc.want, got.Allowed, got.Result)
+			}
+		})
+	}
+}